@@ -1,27 +1,24 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"os"
-	"os/exec"
+	"os/signal"
+	"syscall"
+	"time"
 
+	"github.com/bmj2728/hst/shared/pkg/audit"
 	"github.com/bmj2728/hst/shared/pkg/filelister"
-	"github.com/bmj2728/hst/shared/pkg/hostconn"
 	"github.com/bmj2728/hst/shared/pkg/hostserve"
+	"github.com/bmj2728/hst/shared/pkg/pluginmgr"
 	"github.com/hashicorp/go-hclog"
 	"github.com/hashicorp/go-plugin"
+	"github.com/novelgitllc/ansicolor/v3"
 )
 
-var handshakeConfig = plugin.HandshakeConfig{
-	ProtocolVersion:  1,
-	MagicCookieKey:   "TEST_KEY",
-	MagicCookieValue: "TEST_VALUE",
-}
-
-var pluginMap = map[string]plugin.Plugin{
-	"fl-plugin": &filelister.FileListerGRPCPlugin{},
-	"cl-plugin": &filelister.FileListerGRPCPlugin{},
-}
+// listTimeout bounds each demo ListFiles call.
+const listTimeout = 10 * time.Second
 
 func main() {
 	logger := hclog.New(&hclog.LoggerOptions{
@@ -31,106 +28,139 @@ func main() {
 	})
 
 	// Set up host services - create the implementation
-	// HostServices is a struct that embeds the HostFS and HostEnv interfaces
-	hostServices := hostserve.NewHostServices(hostserve.NewHostFS(), hostserve.NewHostEnv())
-	//Start plugin 1
-
-	// Create the plugin client - plumbing
-	client := plugin.NewClient(&plugin.ClientConfig{
-		HandshakeConfig:  handshakeConfig,
-		Plugins:          pluginMap,
-		Cmd:              exec.Command("./plugins/filelister/filelister"),
-		AllowedProtocols: []plugin.Protocol{plugin.ProtocolGRPC},
-		Logger:           logger,
-	})
-	defer client.Kill()
-
-	// Connect via gRPC - porcelain
-	rpcClient, err := client.Client()
-	if err != nil {
-		logger.Error("Failed to get RPC client", "err", err)
-		os.Exit(1)
+	// HostServices is a struct that embeds the HostFS and HostEnv interfaces. Set DRY_RUN to capture every change
+	// the plugins make in memory and report it, rather than writing to disk.
+	diskFS := hostserve.NewHostFS()
+	var hostFS hostserve.IHostFS = diskFS
+	var overlay *hostserve.OverlayFS
+	if os.Getenv("DRY_RUN") != "" {
+		overlay = hostserve.NewOverlayFS(diskFS)
+		hostFS = overlay
 	}
-
-	// Request the FileLister plugin - the raw interface
-	raw, err := rpcClient.Dispense("fl-plugin")
-	if err != nil {
-		logger.Error("Failed to dispense plugin", "err", err)
-		os.Exit(1)
+	// Set MAX_LOCK_LEASE, as in "2m", to change how long a plugin may hold an advisory lock
+	if v := os.Getenv("MAX_LOCK_LEASE"); v != "" {
+		maxLease, err := time.ParseDuration(v)
+		if err != nil || maxLease < 0 {
+			logger.Error("Invalid MAX_LOCK_LEASE", "value", v, "err", err)
+			os.Exit(1)
+		}
+		diskFS.SetMaxLockLease(maxLease)
+		if overlay != nil {
+			overlay.SetMaxLockLease(maxLease)
+		}
 	}
-
-	// Coerce the raw interface to the FileLister type
-	fileLister := raw.(filelister.FileLister)
-
-	// Setup host services for the plugin (if supported)
-	if err := hostconn.EstablishHostServices(raw, hostServices, logger); err != nil {
-		logger.Error("Failed to establish host services", "err", err)
-		os.Exit(1)
+	hostServices := hostserve.NewHostServices(hostFS, hostserve.NewHostEnv())
+
+	// Record every host service call - in memory for inspection, and to a JSON-lines file if AUDIT_LOG is set
+	auditLog := audit.NewRingBuffer(1024)
+	auditSink := audit.Sink(auditLog)
+	if path := os.Getenv("AUDIT_LOG"); path != "" {
+		fileSink, err := audit.OpenJSONLinesFile(path)
+		if err != nil {
+			logger.Error("Failed to open audit log", "path", path, "err", err)
+			os.Exit(1)
+		}
+		defer fileSink.Close()
+		auditSink = audit.MultiSink(auditLog, fileSink)
 	}
 
-	// End plugin 1
-
-	//Start plugin 2
-	color := plugin.NewClient(&plugin.ClientConfig{
-		HandshakeConfig:  handshakeConfig,
-		Plugins:          pluginMap,
-		Cmd:              exec.Command("./plugins/colorlister/colorlister"),
-		AllowedProtocols: []plugin.Protocol{plugin.ProtocolGRPC},
-		Logger:           logger,
+	// Discover, launch and connect every plugin in the plugins directory. Each plugin is restricted to the root
+	// directory and capabilities declared in its manifest. Set PLUGIN_RELOAD to pick up rebuilt plugins without
+	// restarting the host.
+	reload := os.Getenv("PLUGIN_RELOAD") != ""
+	manager := pluginmgr.New(pluginmgr.Config{
+		Dir: "./plugins",
+		Types: map[string]plugin.Plugin{
+			"filelister": &filelister.FileListerGRPCPlugin{},
+		},
+		HostServices: hostServices,
+		Audit:        auditSink,
+		Logger:       logger,
+		Reload:       reload,
 	})
-	defer color.Kill()
-
-	// Connect via gRPC - porcelain
-	rpcClientColor, err := color.Client()
-	if err != nil {
-		logger.Error("Failed to get RPC client", "err", err)
+	if err := manager.Start(); err != nil {
+		manager.Shutdown()
+		logger.Error("Failed to start plugins", "err", err)
 		os.Exit(1)
 	}
 
-	// Request the FileLister plugin - the raw interface
-	rawColor, err := rpcClientColor.Dispense("cl-plugin")
-	if err != nil {
-		logger.Error("Failed to dispense plugin", "err", err)
-		os.Exit(1)
+	// Test each plugin by listing files in the current directory, giving each call a deadline that also bounds
+	// the host service calls the plugin makes
+	for _, name := range manager.Names() {
+		ctx, cancel := context.WithTimeout(context.Background(), listTimeout)
+		h, _ := manager.Get(name)
+		// Stream the listing so entries are printed as they arrive, however large the directory
+		count, err := pluginmgr.Call(h, func(fl *filelister.GRPCClient) (int, error) {
+			count := 0
+			for entry, err := range fl.ListFilesStream(ctx, ".") {
+				if err != nil {
+					return count, err
+				}
+				fmt.Println(render(entry))
+				count++
+			}
+			return count, nil
+		})
+		cancel()
+		if err != nil {
+			logger.Error("Failed to list files", "plugin", name, "err", err)
+			continue
+		}
+		logger.Info("Successfully listed files", "plugin", name, "count", count)
 	}
 
-	// Coerce the raw interface to the FileLister type
-	colorlister := rawColor.(filelister.FileLister)
-
-	// Setup host services for the plugin (if supported)
-	if err := hostconn.EstablishHostServices(rawColor, hostServices, logger); err != nil {
-		logger.Error("Failed to establish host services", "err", err)
-		os.Exit(1)
+	// In dry-run mode, show what the plugins would have changed and then drop it
+	if overlay != nil {
+		changes, err := overlay.Changes(context.Background())
+		if err != nil {
+			logger.Error("Failed to list pending changes", "err", err)
+		}
+		diff, err := overlay.Diff(context.Background())
+		if err != nil {
+			logger.Error("Failed to diff pending changes", "err", err)
+		}
+		logger.Info("Dry run, discarding pending changes", "count", len(changes))
+		fmt.Print(diff)
+		overlay.Discard()
 	}
 
-	// End plugin 2
-
-	// Test the plugin by listing files in the current directory
-	entries, err := fileLister.ListFiles(".")
-	if err != nil {
-		logger.Error("Failed to list files", "err", err)
-		os.Exit(1)
+	// Report any host service calls that were denied
+	for _, e := range auditLog.Query(func(e audit.Event) bool { return !e.Allowed }) {
+		logger.Warn("Denied host service call", "client", e.ClientID, "method", e.Method, "path", e.Path,
+			"key", e.EnvKey)
 	}
-
-	colorEntries, err := colorlister.ListFiles(".")
-	if err != nil {
-		logger.Error("Failed to list files", "err", err)
-		os.Exit(1)
+	logger.Info("Host service calls audited", "count", auditLog.Len())
+	for _, name := range manager.Names() {
+		u := manager.Usage(name)
+		logger.Info("Host service usage", "plugin", name, "calls", u.Calls, "rejected", u.Rejected,
+			"bytes_read", u.BytesRead, "bytes_written", u.BytesWritten, "files_created", u.FilesCreated)
 	}
 
-	logger.Info("Successfully listed files - no color")
-	for _, entry := range entries {
-		fmt.Println(entry)
+	// In reload mode, keep running so plugins can be rebuilt and reloaded until interrupted
+	if reload {
+		logger.Info("Watching plugins for changes, press Ctrl-C to exit")
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		<-ctx.Done()
+		stop()
 	}
 
-	logger.Info("Successfully listed files - with color")
-	for _, entry := range colorEntries {
-		fmt.Println(entry)
-	}
-
-	// Clean shutdown - disconnect from host services
+	// Clean shutdown - disconnect from host services and stop the plugins
 	logger.Info("Shutting down plugins")
-	hostconn.DisconnectHostServices(raw, logger)
-	hostconn.DisconnectHostServices(rawColor, logger)
-	os.Exit(0)
+	manager.Shutdown()
+}
+
+// render formats a listed entry for the terminal, following the display hints the plugin suggested for it.
+func render(e filelister.FileListEntry) string {
+	line := fmt.Sprintf("%-7s %10d  %s", e.Kind, e.Size, e.Path)
+	if fg, ok := ansicolor.FgColorLookup[e.Display.Color]; ok {
+		format := ansicolor.NewFormat().WithForeground(fg)
+		if e.Display.Bold {
+			format = format.WithOption(ansicolor.SGROptBold)
+		}
+		line = format.Wrap(line, true)
+	}
+	if e.Preview != nil {
+		line += "\n" + *e.Preview
+	}
+	return line
 }
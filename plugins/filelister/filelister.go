@@ -3,102 +3,80 @@ package main
 import (
 	"bytes"
 	"context"
+	"errors"
+	"io"
 	"path/filepath"
-	"sync"
 
 	"github.com/bmj2728/hst/shared/pkg/filelister"
-	"github.com/bmj2728/hst/shared/pkg/hostserve"
-	hostservev1 "github.com/bmj2728/hst/shared/protogen/hostserve/v1"
+	"github.com/bmj2728/hst/shared/pkg/sdk"
 	"github.com/hashicorp/go-hclog"
-	"github.com/hashicorp/go-plugin"
-	"google.golang.org/grpc"
 )
 
 type FileLister struct {
-	broker            *plugin.GRPCBroker
-	hostServiceClient hostserve.IHostServices
-	conn              *grpc.ClientConn
-	connMutex         sync.Mutex
+	sdk.HostConnector
 }
 
-func (f *FileLister) ListFiles(dir string) ([]string, error) {
-	ctx := context.Background()
-	home := f.hostServiceClient.GetEnv(ctx, "HOME")
-	dirEntries, err := f.hostServiceClient.ReadDir(ctx, dir)
+func (f *FileLister) ListFiles(ctx context.Context, dir string) ([]filelister.FileListEntry, error) {
+	home, found, err := f.Host().LookupEnv(ctx, "HOME")
+	if err != nil {
+		hclog.Default().Warn("Failed to look up HOME via host service", "err", err)
+	}
+	hclog.Default().Info("Listing files", "dir", dir, "home", home, "homeSet", found)
+	dirEntries, err := f.Host().ReadDir(ctx, dir)
 	if err != nil {
 		hclog.Default().Error("Failed to read directory via host service", "dir", dir, "err", err)
 		return nil, err
 	}
 
-	var entries []string
+	var entries []filelister.FileListEntry
 	var buf bytes.Buffer
-	entries = append(entries, home)
 	for _, entry := range dirEntries {
-		if entry.IsDir() {
-			entries = append(entries, entry.Name())
-			buf.WriteString(entry.Name())
-		} else {
-			entries = append(entries, entry.Name())
-			buf.WriteString(entry.Name())
-		}
+		entries = append(entries, filelister.EntryFromDirEntry(dir, entry))
+		buf.WriteString(entry.Name())
 	}
 
-	err = f.hostServiceClient.WriteFile(ctx, filepath.Join(dir, "listed_files.txt"), buf.Bytes(), 0644)
+	err = f.Host().WriteFile(ctx, filepath.Join(dir, "listed_files.txt"), buf.Bytes(), 0644)
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
 	if err != nil {
 		hclog.Default().Error("Failed to write file via host service", "dir", dir, "err", err)
 	}
 	return entries, nil
 }
 
-func (f *FileLister) EstablishHostServices(hostServiceID uint32) {
-	f.connMutex.Lock()
-	defer f.connMutex.Unlock()
-
-	conn, err := f.broker.Dial(hostServiceID)
+// StreamFiles lists dir in batches read from the host as they arrive, so that directories of any size can be listed
+// without holding every entry in memory.
+func (f *FileLister) StreamFiles(ctx context.Context, dir string, send func([]filelister.FileListEntry) error) error {
+	hclog.Default().Info("Streaming files", "dir", dir)
+	dirReader, err := f.Host().ReadDirStream(ctx, dir)
 	if err != nil {
-		hclog.Default().Error("Failed to dial host service", "err", err)
-		return
+		hclog.Default().Error("Failed to open directory via host service", "dir", dir, "err", err)
+		return err
 	}
-
-	f.conn = conn
-	f.hostServiceClient = hostserve.NewHostServiceGRPCClient(hostservev1.NewHostServiceClient(conn))
-	hclog.Default().Info("Established host services", "id", hostServiceID)
-}
-
-func (f *FileLister) DisconnectHostServices() {
-	f.connMutex.Lock()
-	defer f.connMutex.Unlock()
-
-	if f.conn != nil {
-		if err := f.conn.Close(); err != nil {
-			hclog.Default().Error("Failed to close connection", "err", err)
+	defer dirReader.Close()
+
+	for {
+		dirEntries, err := dirReader.ReadDir(filelister.StreamBatchSize)
+		if len(dirEntries) > 0 {
+			entries := make([]filelister.FileListEntry, 0, len(dirEntries))
+			for _, entry := range dirEntries {
+				entries = append(entries, filelister.EntryFromDirEntry(dir, entry))
+			}
+			if err := send(entries); err != nil {
+				return err
+			}
+		}
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			hclog.Default().Error("Failed to read directory via host service", "dir", dir, "err", err)
+			return err
 		}
-		f.conn = nil
-		f.hostServiceClient = nil
-		hclog.Default().Info("Disconnected from host services")
 	}
 }
 
-func (f *FileLister) SetBroker(broker *plugin.GRPCBroker) {
-	f.broker = broker
-}
-
-var handshakeConfig = plugin.HandshakeConfig{
-	ProtocolVersion:  1,
-	MagicCookieKey:   "TEST_KEY",
-	MagicCookieValue: "TEST_VALUE",
-}
-
 func main() {
-	fl := &FileLister{}
-
-	pluginMap := map[string]plugin.Plugin{
-		"fl-plugin": &filelister.FileListerGRPCPlugin{Impl: fl},
-	}
-
-	plugin.Serve(&plugin.ServeConfig{
-		HandshakeConfig: handshakeConfig,
-		Plugins:         pluginMap,
-		GRPCServer:      plugin.DefaultGRPCServer,
-	})
+	sdk.Serve("fl-plugin", &filelister.FileListerGRPCPlugin{Impl: &FileLister{}})
 }
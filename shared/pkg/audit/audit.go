@@ -0,0 +1,57 @@
+package audit
+
+import (
+	"time"
+)
+
+// Event is a structured record of a single host service call made by a plugin.
+type Event struct {
+	// Time is when the call started.
+	Time time.Time `json:"time"`
+	// ClientID is the host-assigned identity of the calling plugin.
+	ClientID string `json:"client_id"`
+	// Method is the host service method that was called, e.g. "ReadFile".
+	Method string `json:"method"`
+	// Path is the filesystem path the call operated on, if any.
+	Path string `json:"path,omitempty"`
+	// Target is the second path of calls that operate on two, such as the destination of a Rename or Copy.
+	Target string `json:"target,omitempty"`
+	// EnvKey is the environment variable the call operated on, if any.
+	EnvKey string `json:"env_key,omitempty"`
+	// TxID is the transaction the call began, ended or staged a write in, if any.
+	TxID string `json:"tx_id,omitempty"`
+	// LockID is the lock the call acquired or released, if any.
+	LockID string `json:"lock_id,omitempty"`
+	// Allowed is false when the call was rejected by an access check.
+	Allowed bool `json:"allowed"`
+	// BytesRead is the number of bytes returned to the plugin.
+	BytesRead int64 `json:"bytes_read"`
+	// BytesWritten is the number of bytes the plugin wrote. It is zero for a write that failed, as nothing was put in
+	// place.
+	BytesWritten int64 `json:"bytes_written"`
+	// Duration is how long the call took, encoded in JSON as nanoseconds.
+	Duration time.Duration `json:"duration"`
+	// Error is the error the call failed with, if any.
+	Error string `json:"error,omitempty"`
+}
+
+// Sink receives audit events. Implementations must be safe for concurrent use, as events from every plugin
+// connection are recorded to the same sink.
+type Sink interface {
+	Record(e Event)
+}
+
+// multiSink records each event to several sinks in order.
+type multiSink []Sink
+
+// Record forwards the event to every sink.
+func (m multiSink) Record(e Event) {
+	for _, s := range m {
+		s.Record(e)
+	}
+}
+
+// MultiSink returns a Sink that records every event to each of the given sinks.
+func MultiSink(sinks ...Sink) Sink {
+	return multiSink(sinks)
+}
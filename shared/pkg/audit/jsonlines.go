@@ -0,0 +1,52 @@
+package audit
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"sync"
+
+	"github.com/hashicorp/go-hclog"
+)
+
+// JSONLinesSink writes each event as a single line of JSON.
+type JSONLinesSink struct {
+	mu     sync.Mutex
+	enc    *json.Encoder
+	closer io.Closer
+}
+
+// NewJSONLinesSink creates a JSONLinesSink that writes to w. The caller retains ownership of w.
+func NewJSONLinesSink(w io.Writer) *JSONLinesSink {
+	return &JSONLinesSink{enc: json.NewEncoder(w)}
+}
+
+// OpenJSONLinesFile creates a JSONLinesSink that appends to the file at path, creating it if necessary.
+// The file is closed when the sink is closed.
+func OpenJSONLinesFile(path string) (*JSONLinesSink, error) {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0600)
+	if err != nil {
+		return nil, err
+	}
+	return &JSONLinesSink{enc: json.NewEncoder(f), closer: f}, nil
+}
+
+// Record writes the event as a line of JSON. Write failures are logged, as audit recording must not fail the call
+// being audited.
+func (s *JSONLinesSink) Record(e Event) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.enc.Encode(e); err != nil {
+		hclog.Default().Error("Failed to write audit event", "method", e.Method, "err", err)
+	}
+}
+
+// Close closes the underlying file if the sink was created by OpenJSONLinesFile.
+func (s *JSONLinesSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closer == nil {
+		return nil
+	}
+	return s.closer.Close()
+}
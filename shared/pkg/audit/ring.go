@@ -0,0 +1,71 @@
+package audit
+
+import (
+	"sync"
+)
+
+// RingBuffer keeps the most recent events in memory so they can be inspected by the host or by tests.
+// Once full, each new event overwrites the oldest one.
+type RingBuffer struct {
+	mu     sync.Mutex
+	events []Event
+	next   int
+	full   bool
+}
+
+// NewRingBuffer creates a RingBuffer that holds up to size events.
+func NewRingBuffer(size int) *RingBuffer {
+	if size < 1 {
+		size = 1
+	}
+	return &RingBuffer{events: make([]Event, size)}
+}
+
+// Record stores the event, evicting the oldest event if the buffer is full.
+func (r *RingBuffer) Record(e Event) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events[r.next] = e
+	r.next = (r.next + 1) % len(r.events)
+	if r.next == 0 {
+		r.full = true
+	}
+}
+
+// Events returns a copy of the buffered events, oldest first.
+func (r *RingBuffer) Events() []Event {
+	return r.Query(func(Event) bool { return true })
+}
+
+// Query returns the buffered events for which match returns true, oldest first.
+func (r *RingBuffer) Query(match func(Event) bool) []Event {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var out []Event
+	start := 0
+	if r.full {
+		start = r.next
+	}
+	for i := range r.size() {
+		e := r.events[(start+i)%len(r.events)]
+		if match(e) {
+			out = append(out, e)
+		}
+	}
+	return out
+}
+
+// Len returns the number of buffered events.
+func (r *RingBuffer) Len() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.size()
+}
+
+// size returns the number of buffered events. The caller must hold the lock.
+func (r *RingBuffer) size() int {
+	if r.full {
+		return len(r.events)
+	}
+	return r.next
+}
@@ -0,0 +1,60 @@
+package audit
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+)
+
+// methods returns the methods of events, in order.
+func methods(events []Event) []string {
+	out := make([]string, 0, len(events))
+	for _, e := range events {
+		out = append(out, e.Method)
+	}
+	return out
+}
+
+func TestRingBufferEvictsOldest(t *testing.T) {
+	tests := []struct {
+		name     string
+		size     int
+		recorded int
+		want     []string
+	}{
+		{name: "empty", size: 3, recorded: 0, want: []string{}},
+		{name: "partly full", size: 3, recorded: 2, want: []string{"m0", "m1"}},
+		{name: "exactly full", size: 3, recorded: 3, want: []string{"m0", "m1", "m2"}},
+		{name: "wrapped", size: 3, recorded: 5, want: []string{"m2", "m3", "m4"}},
+		{name: "wrapped twice", size: 3, recorded: 7, want: []string{"m4", "m5", "m6"}},
+		{name: "size below one", size: 0, recorded: 2, want: []string{"m1"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := NewRingBuffer(tt.size)
+			for i := range tt.recorded {
+				r.Record(Event{Method: fmt.Sprintf("m%d", i)})
+			}
+			if got := methods(r.Events()); !slices.Equal(got, tt.want) {
+				t.Errorf("Events() = %v, want %v", got, tt.want)
+			}
+			if got := r.Len(); got != len(tt.want) {
+				t.Errorf("Len() = %d, want %d", got, len(tt.want))
+			}
+		})
+	}
+}
+
+func TestRingBufferQuery(t *testing.T) {
+	r := NewRingBuffer(4)
+	for i, client := range []string{"a", "b", "a", "b", "a", "a"} {
+		r.Record(Event{ClientID: client, Method: fmt.Sprintf("m%d", i)})
+	}
+	got := methods(r.Query(func(e Event) bool { return e.ClientID == "a" }))
+	if want := []string{"m2", "m4", "m5"}; !slices.Equal(got, want) {
+		t.Errorf("Query(client a) = %v, want %v", got, want)
+	}
+	if got := r.Query(func(Event) bool { return false }); len(got) != 0 {
+		t.Errorf("Query(none) = %v, want no events", got)
+	}
+}
@@ -0,0 +1,124 @@
+package filelister
+
+import (
+	"io/fs"
+	"path/filepath"
+	"time"
+
+	filelisterv1 "github.com/bmj2728/hst/shared/protogen/filelister/v1"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// EntryKind is the type of file a FileListEntry names.
+type EntryKind int
+
+const (
+	KindUnknown EntryKind = iota
+	KindFile
+	KindDir
+	KindSymlink
+	KindOther
+)
+
+// String returns the lower-case name of the kind.
+func (k EntryKind) String() string {
+	switch k {
+	case KindFile:
+		return "file"
+	case KindDir:
+		return "dir"
+	case KindSymlink:
+		return "symlink"
+	case KindOther:
+		return "other"
+	default:
+		return "unknown"
+	}
+}
+
+// DisplayHints are optional suggestions from a plugin on how the host should render an entry. Hosts are free to
+// ignore them.
+type DisplayHints struct {
+	// Color is a color name such as "blue" or "green".
+	Color string
+	Bold  bool
+}
+
+// FileListEntry is a single file found by a FileLister.
+type FileListEntry struct {
+	Name    string
+	Path    string
+	Kind    EntryKind
+	Size    int64
+	ModTime time.Time
+	// Preview holds some or all of the file's contents, or nil if the plugin did not include them.
+	Preview *string
+	Display DisplayHints
+}
+
+// EntryFromDirEntry builds a FileListEntry for a directory entry read from dir, filling in the size and
+// modification time from the entry's info when it is available.
+func EntryFromDirEntry(dir string, entry fs.DirEntry) FileListEntry {
+	e := FileListEntry{
+		Name: entry.Name(),
+		Path: filepath.Join(dir, entry.Name()),
+		Kind: kindOf(entry.Type()),
+	}
+	if info, err := entry.Info(); err == nil {
+		e.Size = info.Size()
+		e.ModTime = info.ModTime()
+	}
+	return e
+}
+
+// kindOf maps file mode type bits to an EntryKind.
+func kindOf(mode fs.FileMode) EntryKind {
+	switch {
+	case mode.IsRegular():
+		return KindFile
+	case mode.IsDir():
+		return KindDir
+	case mode&fs.ModeSymlink != 0:
+		return KindSymlink
+	default:
+		return KindOther
+	}
+}
+
+// entryToProto converts a FileListEntry into its protobuf representation.
+func entryToProto(e FileListEntry) *filelisterv1.FileListEntry {
+	pb := &filelisterv1.FileListEntry{
+		Name:    e.Name,
+		Path:    e.Path,
+		Kind:    filelisterv1.EntryKind(e.Kind),
+		Size:    e.Size,
+		Preview: e.Preview,
+		Display: &filelisterv1.DisplayHints{
+			Color: e.Display.Color,
+			Bold:  e.Display.Bold,
+		},
+	}
+	if !e.ModTime.IsZero() {
+		pb.ModTime = timestamppb.New(e.ModTime)
+	}
+	return pb
+}
+
+// entryFromProto converts a protobuf FileListEntry back into a FileListEntry.
+func entryFromProto(pb *filelisterv1.FileListEntry) FileListEntry {
+	e := FileListEntry{
+		Name:    pb.GetName(),
+		Path:    pb.GetPath(),
+		Kind:    EntryKind(pb.GetKind()),
+		Size:    pb.GetSize(),
+		Preview: pb.Preview,
+		Display: DisplayHints{
+			Color: pb.GetDisplay().GetColor(),
+			Bold:  pb.GetDisplay().GetBold(),
+		},
+	}
+	if pb.ModTime != nil {
+		e.ModTime = pb.ModTime.AsTime()
+	}
+	return e
+}
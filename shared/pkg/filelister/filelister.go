@@ -10,9 +10,20 @@ import (
 )
 
 // FileLister is the business interface for file listing plugins.
-// This interface contains only the core business logic methods.
+// This interface contains only the core business logic methods. The context carries the host's deadline and
+// cancellation, and plugins should pass it on to any host service calls they make.
 type FileLister interface {
-	ListFiles(dir string) ([]string, error)
+	ListFiles(ctx context.Context, dir string) ([]FileListEntry, error)
+}
+
+// StreamBatchSize is the maximum number of entries carried by a single FileListChunk.
+const StreamBatchSize = 1024
+
+// StreamingFileLister is implemented by plugins that can produce a listing progressively, for directories too large
+// to hold in memory. StreamFiles calls send with each batch of entries as it becomes available and stops if send
+// returns an error. Plugins that do not implement it are streamed from the result of ListFiles.
+type StreamingFileLister interface {
+	StreamFiles(ctx context.Context, dir string, send func([]FileListEntry) error) error
 }
 
 // FileListerGRPCPlugin is a grpc-based implementation of FileLister for plugin integration using hashicorp/go-plugin.
@@ -22,12 +33,10 @@ type FileListerGRPCPlugin struct {
 	Impl FileLister
 }
 
-// GRPCServer registers a FileLister gRPC server and sets up the broker if the plugin implements HostConnection.
+// GRPCServer registers a FileLister gRPC server along with the host connection service, which hands the broker to
+// the plugin if it implements HostConnection.
 func (fl *FileListerGRPCPlugin) GRPCServer(broker *plugin.GRPCBroker, s *grpc.Server) error {
-	// If the plugin's implementation implements HostConnection, set the broker
-	if hostConn, ok := fl.Impl.(hostconn.HostConnection); ok {
-		hostConn.SetBroker(broker)
-	}
+	hostconn.RegisterServer(s, broker, fl.Impl)
 	filelisterv1.RegisterFileListerServer(s, &GRPCServer{Impl: fl.Impl})
 	return nil
 }
@@ -37,7 +46,7 @@ func (fl *FileListerGRPCPlugin) GRPCClient(ctx context.Context,
 	broker *plugin.GRPCBroker,
 	c *grpc.ClientConn) (interface{}, error) {
 	return &GRPCClient{
+		Client: hostconn.NewClient(broker, c),
 		client: filelisterv1.NewFileListerClient(c),
-		broker: broker,
 	}, nil
 }
@@ -2,12 +2,14 @@ package filelister
 
 import (
 	"context"
+	"errors"
+	"io"
+	"iter"
+	"slices"
 
 	"github.com/bmj2728/hst/shared/pkg/hostconn"
 	"github.com/bmj2728/hst/shared/pkg/hostserve"
 	filelisterv1 "github.com/bmj2728/hst/shared/protogen/filelister/v1"
-	"github.com/bmj2728/hst/shared/protogen/hostserve/v1"
-	"github.com/hashicorp/go-plugin"
 	"google.golang.org/grpc"
 )
 
@@ -21,111 +23,131 @@ type GRPCServer struct {
 func (s *GRPCServer) List(ctx context.Context,
 	request *filelisterv1.FileListRequest) (*filelisterv1.FileListResponse, error) {
 
-	entries, err := s.Impl.ListFiles(request.Dir)
+	entries, err := s.Impl.ListFiles(ctx, request.Dir)
 	if err != nil {
-		errMsg := err.Error()
-		return &filelisterv1.FileListResponse{
-			Entry: nil,
-			Error: &errMsg,
-		}, nil
+		return nil, hostserve.StatusError(err)
+	}
+	pbEntries := make([]*filelisterv1.FileListEntry, 0, len(entries))
+	for _, entry := range entries {
+		pbEntries = append(pbEntries, entryToProto(entry))
 	}
 	return &filelisterv1.FileListResponse{
-		Entry: entries,
-		Error: nil,
+		Entries: pbEntries,
 	}, nil
 }
 
-// EstablishHostServices sets up communication with host services if the plugin implements the HostConnection interface.
-// If the plugin does not require host services, this method silently succeeds without taking further action.
-// The method accepts a context and a HostServiceRequest containing the host service ID.
-// Returns an Empty response and error if any issues occur during execution.
-func (s *GRPCServer) EstablishHostServices(ctx context.Context,
-	request *filelisterv1.HostServiceRequest) (*filelisterv1.Empty, error) {
+// ListStream streams the entries of a directory in chunks of at most StreamBatchSize. If the plugin implements
+// StreamingFileLister its batches are forwarded as they are produced; otherwise the result of ListFiles is split
+// into chunks.
+func (s *GRPCServer) ListStream(request *filelisterv1.FileListRequest,
+	stream grpc.ServerStreamingServer[filelisterv1.FileListChunk]) error {
+
+	send := func(entries []FileListEntry) error {
+		for batch := range slices.Chunk(entries, StreamBatchSize) {
+			pbEntries := make([]*filelisterv1.FileListEntry, 0, len(batch))
+			for _, entry := range batch {
+				pbEntries = append(pbEntries, entryToProto(entry))
+			}
+			if err := stream.Send(&filelisterv1.FileListChunk{Entries: pbEntries}); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
 
-	// Only call EstablishHostServices if the plugin implements HostConnection
-	if hostConn, ok := s.Impl.(hostconn.HostConnection); ok {
-		hostConn.EstablishHostServices(request.HostService)
+	var err error
+	if sl, ok := s.Impl.(StreamingFileLister); ok {
+		err = sl.StreamFiles(stream.Context(), request.Dir, send)
+	} else {
+		var entries []FileListEntry
+		entries, err = s.Impl.ListFiles(stream.Context(), request.Dir)
+		if err == nil {
+			err = send(entries)
+		}
 	}
-	// If plugin doesn't implement HostConnection, silently succeed
-	// (plugin doesn't need host services)
-	return &filelisterv1.Empty{}, nil
+	return hostserve.StatusError(err)
 }
 
 // GRPCClient is the client side of the plugin.
-// It implements plugin.GRPCPlugin so the plugin framework can communicate with it.
+// It implements plugin.GRPCPlugin so the plugin framework can communicate with it, and embeds hostconn.Client
+// so the host can connect the plugin to host services.
 type GRPCClient struct {
-	client        filelisterv1.FileListerClient
-	broker        *plugin.GRPCBroker
-	hostServiceID uint32
-}
-
-// SetBroker sets the gRPC broker for the client.
-// This method is a no-op on the host side since the broker is already initialized in the constructor.
-// It is implemented to fulfill the HostConnection interface requirements.
-func (c *GRPCClient) SetBroker(broker *plugin.GRPCBroker) {
-	// No-op on the host side - the broker is already set during construction
-	// This method exists to satisfy the HostConnection interface
-	c.broker = broker
-}
-
-// EstablishHostServices sets the host service ID and notifies the plugin via gRPC to establish the host service.
-func (c *GRPCClient) EstablishHostServices(hostServiceID uint32) {
-	c.hostServiceID = hostServiceID
-	// Also call the gRPC method to notify the plugin
-	_, _ = c.client.EstablishHostServices(context.Background(), &filelisterv1.HostServiceRequest{
-		HostService: hostServiceID,
-	})
-}
-
-// DisconnectHostServices performs cleanup actions during plugin shutdown, though no client-side cleanup is
-// needed currently.
-func (c *GRPCClient) DisconnectHostServices() {
-	// The host manages its own server lifecycle
-	// This is called during plugin shutdown to do any cleanup
-	// Currently no cleanup needed on the client side
+	*hostconn.Client
+	client filelisterv1.FileListerClient
 }
 
 // ListFiles retrieves the list of files in the specified directory on the remote host using the gRPC client.
-func (c *GRPCClient) ListFiles(dir string) ([]string, error) {
-	resp, err := c.client.List(context.Background(), &filelisterv1.FileListRequest{
-		Dir:         dir,
-		HostService: c.hostServiceID,
+// Cancelling ctx aborts the call in the plugin, along with any host service calls it makes on the caller's behalf.
+func (c *GRPCClient) ListFiles(ctx context.Context, dir string) ([]FileListEntry, error) {
+	resp, err := c.client.List(ctx, &filelisterv1.FileListRequest{
+		Dir: dir,
 	})
 	if err != nil {
-		return nil, err
+		return nil, newFileListerError(err)
 	}
-	if resp.Error != nil {
-		return nil, &FileListerError{Message: *resp.Error}
+	entries := make([]FileListEntry, 0, len(resp.Entries))
+	for _, entry := range resp.Entries {
+		entries = append(entries, entryFromProto(entry))
 	}
-	return resp.Entry, nil
+	return entries, nil
 }
 
-// RegisterHostService registers a host service with the broker and returns its service ID.
-// This allows plugins to dial back to host services for bidirectional communication.
-// Implements hostconn.HostServiceRegistrar interface.
-func (c *GRPCClient) RegisterHostService(hostServices hostserve.IHostServices) (uint32, error) {
-	// Allocate a unique ID for this service using the broker's built-in ID allocator
-	serviceID := c.broker.NextId()
-
-	// Start a gRPC server for the host service via the broker at the allocated ID
-	go c.broker.AcceptAndServe(serviceID, func(opts []grpc.ServerOption) *grpc.Server {
-		server := grpc.NewServer(opts...)
-		hostservev1.RegisterHostServiceServer(server, &hostserve.HostServiceGRPCServer{
-			Impl: hostServices,
+// ListFilesStream lists the specified directory progressively, yielding each entry as it arrives from the plugin.
+// An error ends the sequence and is yielded with a zero entry. Breaking out of the loop, or cancelling ctx, aborts
+// the call in the plugin.
+func (c *GRPCClient) ListFilesStream(ctx context.Context, dir string) iter.Seq2[FileListEntry, error] {
+	return func(yield func(FileListEntry, error) bool) {
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+		stream, err := c.client.ListStream(ctx, &filelisterv1.FileListRequest{
+			Dir: dir,
 		})
-		return server
-	})
-
-	return serviceID, nil
+		if err != nil {
+			yield(FileListEntry{}, newFileListerError(err))
+			return
+		}
+		for {
+			chunk, err := stream.Recv()
+			if errors.Is(err, io.EOF) {
+				return
+			}
+			if err != nil {
+				yield(FileListEntry{}, newFileListerError(err))
+				return
+			}
+			for _, entry := range chunk.Entries {
+				if !yield(entryFromProto(entry), nil) {
+					return
+				}
+			}
+		}
+	}
 }
 
 // FileListerError represents an error returned by the file listing service.
-// It contains a message describing the error.
+// Op and Path describe the operation that failed, when the plugin reported them, and Message describes the error.
+// Err, when set, is the sentinel error it corresponds to, such as fs.ErrNotExist or hostserve.ErrAccessDenied, so
+// callers can test it with errors.Is.
 type FileListerError struct {
+	Op      string
+	Path    string
 	Message string
+	Err     error
+}
+
+// newFileListerError converts an error returned by a FileLister call into a FileListerError, restoring the details
+// the plugin attached to it.
+func newFileListerError(err error) *FileListerError {
+	e := hostserve.ErrorFromStatus(err)
+	return &FileListerError{Op: e.Op, Path: e.Path, Message: e.Message, Err: e.Err}
 }
 
 // Error returns the error message stored in the FileListerError instance.
 func (e *FileListerError) Error() string {
 	return e.Message
 }
+
+// Unwrap returns the sentinel error the FileListerError corresponds to, if any.
+func (e *FileListerError) Unwrap() error {
+	return e.Err
+}
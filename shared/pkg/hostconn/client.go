@@ -0,0 +1,107 @@
+package hostconn
+
+import (
+	"context"
+	"sync"
+
+	"github.com/bmj2728/hst/shared/pkg/hostserve"
+	hostconnv1 "github.com/bmj2728/hst/shared/protogen/hostconn/v1"
+	"github.com/bmj2728/hst/shared/protogen/hostserve/v1"
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/go-plugin"
+	"google.golang.org/grpc"
+)
+
+// Client is the host side of the host connection. Host-side plugin client wrappers embed it to implement both
+// HostServiceRegistrar and HostConnection for any plugin type whose server calls RegisterServer:
+//
+//	func (p *MyGRPCPlugin) GRPCClient(ctx context.Context, broker *plugin.GRPCBroker, c *grpc.ClientConn) (interface{}, error) {
+//		return &MyGRPCClient{Client: hostconn.NewClient(broker, c), client: mypb.NewMyClient(c)}, nil
+//	}
+type Client struct {
+	broker *plugin.GRPCBroker
+	client hostconnv1.HostConnectionClient
+
+	mu            sync.Mutex
+	hostServiceID uint32
+	hostServer    *hostserve.HostServiceGRPCServer
+}
+
+// NewClient creates a Client that talks to the plugin over conn and serves host services through broker.
+func NewClient(broker *plugin.GRPCBroker, conn *grpc.ClientConn) *Client {
+	return &Client{
+		broker: broker,
+		client: hostconnv1.NewHostConnectionClient(conn),
+	}
+}
+
+// SetBroker replaces the gRPC broker used to serve host services. The broker is normally supplied to NewClient.
+func (c *Client) SetBroker(broker *plugin.GRPCBroker) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.broker = broker
+}
+
+// HostServiceID returns the broker service ID of the host services registered for the plugin, or zero if none
+// have been established.
+func (c *Client) HostServiceID() uint32 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.hostServiceID
+}
+
+// RegisterHostService serves the host service server with the broker and returns its service ID.
+// The server is closed once the broker stops serving it.
+// Implements the HostServiceRegistrar interface.
+func (c *Client) RegisterHostService(hostServer *hostserve.HostServiceGRPCServer) (uint32, error) {
+	c.mu.Lock()
+	broker := c.broker
+	c.hostServer = hostServer
+	c.mu.Unlock()
+
+	// Allocate a unique ID for this service using the broker's built-in ID allocator
+	serviceID := broker.NextId()
+
+	// Start a gRPC server for the host service via the broker at the allocated ID
+	go func() {
+		defer hostServer.Close()
+		broker.AcceptAndServe(serviceID, func(opts []grpc.ServerOption) *grpc.Server {
+			server := grpc.NewServer(opts...)
+			hostservev1.RegisterHostServiceServer(server, hostServer)
+			return server
+		})
+	}()
+
+	return serviceID, nil
+}
+
+// EstablishHostServices records the host service ID and tells the plugin to dial it.
+func (c *Client) EstablishHostServices(hostServiceID uint32) {
+	c.mu.Lock()
+	c.hostServiceID = hostServiceID
+	c.mu.Unlock()
+
+	_, err := c.client.EstablishHostServices(context.Background(), &hostconnv1.EstablishHostServicesRequest{
+		HostService: hostServiceID,
+	})
+	if err != nil {
+		hclog.Default().Error("Failed to notify plugin of host services", "id", hostServiceID, "err", err)
+	}
+}
+
+// DisconnectHostServices tells the plugin to close its connection to host services, then releases any locks the
+// plugin still holds through them.
+func (c *Client) DisconnectHostServices() {
+	c.mu.Lock()
+	c.hostServiceID = 0
+	hostServer := c.hostServer
+	c.mu.Unlock()
+
+	_, err := c.client.DisconnectHostServices(context.Background(), &hostconnv1.DisconnectHostServicesRequest{})
+	if err != nil {
+		hclog.Default().Debug("Failed to notify plugin of host services disconnect", "err", err)
+	}
+	if hostServer != nil {
+		hostServer.ReleaseLocks()
+	}
+}
@@ -3,7 +3,9 @@ package hostconn
 import (
 	"fmt"
 
+	"github.com/bmj2728/hst/shared/pkg/audit"
 	"github.com/bmj2728/hst/shared/pkg/hostserve"
+	"github.com/google/uuid"
 	"github.com/hashicorp/go-hclog"
 	"github.com/hashicorp/go-plugin"
 )
@@ -29,16 +31,51 @@ type HostConnection interface {
 // HostServiceRegistrar allows plugin clients to register host services with the broker.
 // This is typically implemented by the host-side plugin client wrapper (e.g., GRPCClient).
 type HostServiceRegistrar interface {
-	// RegisterHostService registers a host service implementation with the broker
-	// and returns the allocated service ID that plugins can use to dial back.
-	RegisterHostService(hostServices hostserve.IHostServices) (uint32, error)
+	// RegisterHostService serves the host service server with the broker and returns the
+	// allocated service ID that plugins can use to dial back. The registrar must Close the
+	// server once the broker stops serving it.
+	RegisterHostService(server *hostserve.HostServiceGRPCServer) (uint32, error)
+}
+
+// Option configures how EstablishHostServices connects a plugin to host services.
+type Option func(*options)
+
+// options holds the settings applied by Option values.
+type options struct {
+	clientID string
+	rootDir  string
+	audit    audit.Sink
+}
+
+// WithClientID sets the identity the host assigns to the plugin. If it is not provided, a UUIDv7 is generated.
+func WithClientID(clientID string) Option {
+	return func(o *options) {
+		o.clientID = clientID
+	}
+}
+
+// WithAudit records an audit event to sink for every host service call the plugin makes.
+func WithAudit(sink audit.Sink) Option {
+	return func(o *options) {
+		o.audit = sink
+	}
+}
+
+// WithRoot confines the plugin's filesystem calls to rootDir. If it is not provided, the plugin is confined to the
+// host's working directory.
+func WithRoot(rootDir string) Option {
+	return func(o *options) {
+		o.rootDir = rootDir
+	}
 }
 
 // EstablishHostServices handles the complete setup flow for connecting a plugin to host services.
 // It encapsulates the following steps:
 // 1. Checks if plugin supports host service registration (via HostServiceRegistrar)
-// 2. Registers the host service with the broker and gets a service ID
-// 3. Notifies the plugin of the service ID (via HostConnection.EstablishHostServices)
+// 2. Assigns the plugin its identity, which the plugin cannot choose or change
+// 3. Confines the plugin's filesystem calls to its root directory
+// 4. Registers the host service with the broker and gets a service ID
+// 5. Notifies the plugin of the service ID (via HostConnection.EstablishHostServices)
 //
 // This function gracefully handles plugins that don't support host services.
 //
@@ -46,6 +83,7 @@ type HostServiceRegistrar interface {
 //   - pluginClient: The dispensed plugin client (typically from rpcClient.Dispense())
 //   - hostServices: The host service implementation to expose to the plugin
 //   - logger: Logger for status messages
+//   - opts: Optional settings such as WithClientID, WithRoot and WithAudit
 //
 // Returns an error if registration fails. Returns nil if plugin doesn't support
 // host services (this is not considered an error).
@@ -53,6 +91,7 @@ func EstablishHostServices(
 	pluginClient interface{},
 	hostServices hostserve.IHostServices,
 	logger hclog.Logger,
+	opts ...Option,
 ) error {
 	// Check if plugin supports host service registration
 	registrar, ok := pluginClient.(HostServiceRegistrar)
@@ -61,12 +100,31 @@ func EstablishHostServices(
 		return nil // Not an error - plugin simply doesn't need host services
 	}
 
+	o := options{rootDir: "."}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.clientID == "" {
+		clientUUID, err := uuid.NewV7()
+		if err != nil {
+			return fmt.Errorf("failed to generate client ID: %w", err)
+		}
+		o.clientID = clientUUID.String()
+	}
+
+	server, err := hostserve.NewHostServiceGRPCServer(hostServices, o.clientID, o.rootDir)
+	if err != nil {
+		return fmt.Errorf("failed to open plugin root: %w", err)
+	}
+	server.Audit = o.audit
+
 	// Register host service with broker and get service ID
-	serviceID, err := registrar.RegisterHostService(hostServices)
+	serviceID, err := registrar.RegisterHostService(server)
 	if err != nil {
+		server.Close()
 		return fmt.Errorf("failed to register host service: %w", err)
 	}
-	logger.Info("Host service registered with broker", "id", serviceID)
+	logger.Info("Host service registered with broker", "id", serviceID, "clientID", o.clientID, "root", o.rootDir)
 
 	// Notify plugin of the service ID so it can dial back
 	if hostConn, ok := pluginClient.(HostConnection); ok {
@@ -0,0 +1,54 @@
+package hostconn
+
+import (
+	"context"
+
+	hostconnv1 "github.com/bmj2728/hst/shared/protogen/hostconn/v1"
+	"github.com/hashicorp/go-plugin"
+	"google.golang.org/grpc"
+)
+
+// server is the plugin side of the host connection. It forwards host notifications to the plugin implementation.
+type server struct {
+	impl HostConnection
+	hostconnv1.UnimplementedHostConnectionServer
+}
+
+// RegisterServer registers the host connection service on a plugin's gRPC server. Plugin types call it from
+// their plugin.GRPCPlugin GRPCServer method, next to registering their own service:
+//
+//	func (p *MyGRPCPlugin) GRPCServer(broker *plugin.GRPCBroker, s *grpc.Server) error {
+//		hostconn.RegisterServer(s, broker, p.Impl)
+//		mypb.RegisterMyServer(s, &MyGRPCServer{Impl: p.Impl})
+//		return nil
+//	}
+//
+// If impl implements HostConnection it is handed the broker and notified when host services are established or
+// disconnected. Otherwise, the notifications are accepted and ignored, as the plugin doesn't need host services.
+func RegisterServer(s *grpc.Server, broker *plugin.GRPCBroker, impl interface{}) {
+	hostConn, ok := impl.(HostConnection)
+	if ok {
+		hostConn.SetBroker(broker)
+	}
+	hostconnv1.RegisterHostConnectionServer(s, &server{impl: hostConn})
+}
+
+// EstablishHostServices passes the host service ID to the plugin so it can dial back to the host.
+func (s *server) EstablishHostServices(ctx context.Context,
+	request *hostconnv1.EstablishHostServicesRequest,
+) (*hostconnv1.EstablishHostServicesResponse, error) {
+	if s.impl != nil {
+		s.impl.EstablishHostServices(request.HostService)
+	}
+	return &hostconnv1.EstablishHostServicesResponse{}, nil
+}
+
+// DisconnectHostServices tells the plugin to close its connection to the host.
+func (s *server) DisconnectHostServices(ctx context.Context,
+	request *hostconnv1.DisconnectHostServicesRequest,
+) (*hostconnv1.DisconnectHostServicesResponse, error) {
+	if s.impl != nil {
+		s.impl.DisconnectHostServices()
+	}
+	return &hostconnv1.DisconnectHostServicesResponse{}, nil
+}
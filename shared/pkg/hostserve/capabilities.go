@@ -0,0 +1,591 @@
+package hostserve
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/bmatcuk/doublestar/v4"
+	"github.com/hashicorp/go-hclog"
+)
+
+// CapabilityKind identifies the class of host resource a capability grants access to.
+type CapabilityKind string
+
+const (
+	// CapabilityRead grants read access to files and directories matching the capability pattern.
+	CapabilityRead CapabilityKind = "read"
+	// CapabilityWrite grants write access to files matching the capability pattern.
+	CapabilityWrite CapabilityKind = "write"
+	// CapabilityEnv grants access to environment variables whose keys match the capability pattern.
+	CapabilityEnv CapabilityKind = "env"
+)
+
+var (
+	// ErrAccessDenied indicates a host service call was rejected because the plugin lacks the capability for it.
+	ErrAccessDenied = errors.New("access denied")
+	// ErrInvalidCapability indicates a capability string could not be parsed.
+	ErrInvalidCapability = errors.New("invalid capability")
+)
+
+// AccessDeniedError describes a host service call that was rejected by a CapabilityChecker.
+// It matches ErrAccessDenied with errors.Is.
+type AccessDeniedError struct {
+	Op       string
+	Resource string
+}
+
+// Error returns a description of the denied operation and resource.
+func (e *AccessDeniedError) Error() string {
+	return fmt.Sprintf("%s %s: %s", e.Op, e.Resource, ErrAccessDenied)
+}
+
+// Unwrap returns ErrAccessDenied so callers can test for denials with errors.Is.
+func (e *AccessDeniedError) Unwrap() error {
+	return ErrAccessDenied
+}
+
+// RedactedValue replaces the value of a secret-looking environment variable that a plugin was not granted by name.
+const RedactedValue = "[REDACTED]"
+
+// SecretEnvPatterns are doublestar patterns for environment variable keys that look like they hold secrets. Keys are
+// matched case-insensitively. A plugin only sees the values of such variables if its capabilities name the key
+// exactly, as in "env:GITHUB_TOKEN"; wildcard grants such as "env:*" see RedactedValue instead.
+var SecretEnvPatterns = []string{
+	"*SECRET*",
+	"*TOKEN*",
+	"*PASSWORD*",
+	"*PASSWD*",
+	"*API_KEY*",
+	"*APIKEY*",
+	"*ACCESS_KEY*",
+	"*PRIVATE_KEY*",
+	"*CREDENTIAL*",
+}
+
+// IsSecretEnvKey reports whether key matches any of the SecretEnvPatterns.
+func IsSecretEnvKey(key string) bool {
+	key = strings.ToUpper(key)
+	for _, pattern := range SecretEnvPatterns {
+		if ok, _ := doublestar.Match(pattern, key); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// Capability is a single parsed "<kind>:<pattern>" grant. Patterns use doublestar glob syntax, so "**" matches
+// any number of path segments.
+type Capability struct {
+	Kind    CapabilityKind
+	Pattern string
+}
+
+// ParseCapability parses a capability string of the form "<kind>:<pattern>", e.g. "read:config/**".
+func ParseCapability(s string) (Capability, error) {
+	kind, pattern, ok := strings.Cut(s, ":")
+	if !ok || pattern == "" {
+		return Capability{}, fmt.Errorf("%w: %q", ErrInvalidCapability, s)
+	}
+	c := Capability{Kind: CapabilityKind(kind), Pattern: pattern}
+	switch c.Kind {
+	case CapabilityRead, CapabilityWrite, CapabilityEnv:
+	default:
+		return Capability{}, fmt.Errorf("%w: unknown kind %q", ErrInvalidCapability, kind)
+	}
+	if !doublestar.ValidatePattern(filepath.ToSlash(pattern)) {
+		return Capability{}, fmt.Errorf("%w: bad pattern %q", ErrInvalidCapability, pattern)
+	}
+	return c, nil
+}
+
+// Capabilities is the set of grants declared by a single plugin. Relative path patterns, and the relative paths
+// they are checked against, are resolved against the base directory supplied to NewCapabilities. This should be
+// the root directory the plugin is confined to.
+type Capabilities struct {
+	base  string
+	read  []string
+	write []string
+	env   []string
+}
+
+// NewCapabilities parses the given capability strings, resolving relative path patterns against base.
+func NewCapabilities(base string, specs []string) (*Capabilities, error) {
+	base, err := filepath.Abs(base)
+	if err != nil {
+		return nil, err
+	}
+	caps := &Capabilities{base: base}
+	for _, spec := range specs {
+		c, err := ParseCapability(spec)
+		if err != nil {
+			return nil, err
+		}
+		switch c.Kind {
+		case CapabilityRead:
+			caps.read = append(caps.read, resolvePattern(base, c.Pattern))
+		case CapabilityWrite:
+			caps.write = append(caps.write, resolvePattern(base, c.Pattern))
+		case CapabilityEnv:
+			caps.env = append(caps.env, c.Pattern)
+		}
+	}
+	return caps, nil
+}
+
+// resolvePattern anchors a path pattern at base unless it is already absolute.
+func resolvePattern(base, pattern string) string {
+	if !filepath.IsAbs(pattern) {
+		pattern = filepath.Join(base, pattern)
+	}
+	return filepath.ToSlash(pattern)
+}
+
+// matchPath reports whether path, resolved against the base directory, matches any of the patterns. Paths naming a
+// file the host keeps for its own use (see internalName) never match, whatever the patterns.
+func (c *Capabilities) matchPath(patterns []string, path string) bool {
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(c.base, path)
+	}
+	path = filepath.ToSlash(filepath.Clean(path))
+	if internalPath(path) {
+		return false
+	}
+	for _, pattern := range patterns {
+		if ok, _ := doublestar.Match(pattern, path); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// CanRead reports whether the capabilities allow reading the given path.
+func (c *Capabilities) CanRead(path string) bool {
+	return c.matchPath(c.read, path)
+}
+
+// CanWrite reports whether the capabilities allow writing the given path.
+func (c *Capabilities) CanWrite(path string) bool {
+	return c.matchPath(c.write, path)
+}
+
+// CanGetEnv reports whether the capabilities allow reading the given environment variable.
+func (c *Capabilities) CanGetEnv(key string) bool {
+	for _, pattern := range c.env {
+		if ok, _ := doublestar.Match(pattern, key); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// EnvValue returns value as a plugin holding the capabilities may see it. Values of secret-looking keys are replaced
+// with RedactedValue unless the key was granted by name.
+func (c *Capabilities) EnvValue(key, value string) string {
+	if IsSecretEnvKey(key) && !slices.Contains(c.env, key) {
+		return RedactedValue
+	}
+	return value
+}
+
+// CapabilityChecker wraps an IHostServices implementation and allows or denies each call against a plugin's
+// declared capabilities. Hosts create one checker per plugin around the shared host services.
+type CapabilityChecker struct {
+	impl IHostServices
+	caps *Capabilities
+}
+
+// NewCapabilityChecker creates a CapabilityChecker that forwards permitted calls to impl.
+func NewCapabilityChecker(impl IHostServices, caps *Capabilities) *CapabilityChecker {
+	return &CapabilityChecker{
+		impl: impl,
+		caps: caps,
+	}
+}
+
+// deny logs and returns an AccessDeniedError for the given operation and resource, and marks the call as denied in
+// its audit record.
+func deny(ctx context.Context, op, resource string) error {
+	hclog.Default().Warn("Access denied", "clientID", ClientIDFromContext(ctx), "op", op, "resource", resource)
+	markDenied(ctx)
+	return &AccessDeniedError{Op: op, Resource: resource}
+}
+
+// ReadDir reads the directory if the plugin holds a read capability for it.
+func (cc *CapabilityChecker) ReadDir(ctx context.Context, path string) ([]fs.DirEntry, error) {
+	if !cc.caps.CanRead(path) {
+		return nil, deny(ctx, "ReadDir", path)
+	}
+	return cc.impl.ReadDir(ctx, path)
+}
+
+// ReadFile reads the file if the plugin holds a read capability for it.
+func (cc *CapabilityChecker) ReadFile(ctx context.Context, path string) ([]byte, error) {
+	if !cc.caps.CanRead(path) {
+		return nil, deny(ctx, "ReadFile", path)
+	}
+	return cc.impl.ReadFile(ctx, path)
+}
+
+// WriteFile writes the file if the plugin holds a write capability for it.
+func (cc *CapabilityChecker) WriteFile(ctx context.Context, path string, data []byte, perm os.FileMode) error {
+	if !cc.caps.CanWrite(path) {
+		return deny(ctx, "WriteFile", path)
+	}
+	return cc.impl.WriteFile(ctx, path, data, perm)
+}
+
+// WriteFileIf writes the file if the plugin holds a write capability for it. A precondition on the file's contents
+// or modification time reveals them, so it also requires a read capability.
+func (cc *CapabilityChecker) WriteFileIf(ctx context.Context, path string, data []byte, perm os.FileMode,
+	pre WritePrecondition,
+) error {
+	if !cc.caps.CanWrite(path) || (pre.readsFile() && !cc.caps.CanRead(path)) {
+		return deny(ctx, "WriteFileIf", path)
+	}
+	return cc.impl.WriteFileIf(ctx, path, data, perm, pre)
+}
+
+// Stat returns the file info if the plugin holds a read capability for the path.
+func (cc *CapabilityChecker) Stat(ctx context.Context, path string) (fs.FileInfo, error) {
+	if !cc.caps.CanRead(path) {
+		return nil, deny(ctx, "Stat", path)
+	}
+	return cc.impl.Stat(ctx, path)
+}
+
+// Lstat returns the file info, without following a final symbolic link, if the plugin holds a read capability
+// for the path.
+func (cc *CapabilityChecker) Lstat(ctx context.Context, path string) (fs.FileInfo, error) {
+	if !cc.caps.CanRead(path) {
+		return nil, deny(ctx, "Lstat", path)
+	}
+	return cc.impl.Lstat(ctx, path)
+}
+
+// ReadFileStream opens the file for streaming reads if the plugin holds a read capability for it.
+func (cc *CapabilityChecker) ReadFileStream(ctx context.Context, path string) (io.ReadCloser, error) {
+	if !cc.caps.CanRead(path) {
+		return nil, deny(ctx, "ReadFileStream", path)
+	}
+	return cc.impl.ReadFileStream(ctx, path)
+}
+
+// ReadDirStream opens the directory for batched reads if the plugin holds a read capability for it.
+func (cc *CapabilityChecker) ReadDirStream(ctx context.Context, path string) (DirReader, error) {
+	if !cc.caps.CanRead(path) {
+		return nil, deny(ctx, "ReadDirStream", path)
+	}
+	return cc.impl.ReadDirStream(ctx, path)
+}
+
+// WriteFileStream opens the file for streaming writes if the plugin holds a write capability for it.
+func (cc *CapabilityChecker) WriteFileStream(ctx context.Context,
+	path string,
+	perm os.FileMode,
+) (io.WriteCloser, error) {
+	if !cc.caps.CanWrite(path) {
+		return nil, deny(ctx, "WriteFileStream", path)
+	}
+	return cc.impl.WriteFileStream(ctx, path, perm)
+}
+
+// MkdirAll creates the directory if the plugin holds a write capability for it and for every missing parent that
+// would be created along with it. Parents are looked up through the wrapped services, so the lookups count towards
+// the plugin's call rate.
+func (cc *CapabilityChecker) MkdirAll(ctx context.Context, path string, perm os.FileMode) error {
+	if !cc.caps.CanWrite(path) {
+		return deny(ctx, "MkdirAll", path)
+	}
+	for dir := filepath.Dir(path); ; dir = filepath.Dir(dir) {
+		if _, err := cc.impl.Lstat(ctx, dir); !errors.Is(err, fs.ErrNotExist) {
+			break
+		}
+		if !cc.caps.CanWrite(dir) {
+			return deny(ctx, "MkdirAll", dir)
+		}
+		if filepath.Dir(dir) == dir {
+			break
+		}
+	}
+	return cc.impl.MkdirAll(ctx, path, perm)
+}
+
+// Remove removes the path if the plugin holds a write capability for it.
+func (cc *CapabilityChecker) Remove(ctx context.Context, path string) error {
+	if !cc.caps.CanWrite(path) {
+		return deny(ctx, "Remove", path)
+	}
+	return cc.impl.Remove(ctx, path)
+}
+
+// RemoveAll removes the path and its children if the plugin holds a write capability for the path and for every
+// entry beneath it. The tree is walked before anything is removed, so a grant that covers a directory but not all of
+// its contents removes nothing.
+func (cc *CapabilityChecker) RemoveAll(ctx context.Context, path string) error {
+	if !cc.caps.CanWrite(path) {
+		return deny(ctx, "RemoveAll", path)
+	}
+	if err := cc.canWriteTree(ctx, "RemoveAll", path, ""); err != nil {
+		return err
+	}
+	return cc.impl.RemoveAll(ctx, path)
+}
+
+// canWriteTree walks the tree at path and returns an AccessDeniedError, naming op, for the first entry the plugin
+// cannot write, or the error that kept part of the tree from being walked. If moveTo is set, each entry must also be
+// writable at the path it would be moved to beneath moveTo. A path that does not exist has nothing to check.
+func (cc *CapabilityChecker) canWriteTree(ctx context.Context, op, path, moveTo string) error {
+	walkCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	entries, err := cc.impl.Walk(walkCtx, path, WalkOptions{})
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	for e := range entries {
+		switch {
+		case e.Path != "" && !cc.caps.CanWrite(e.Path):
+			return deny(ctx, op, e.Path)
+		case e.Path != "" && moveTo != "":
+			rel, err := filepath.Rel(path, e.Path)
+			if err != nil {
+				return err
+			}
+			if dst := filepath.Join(moveTo, rel); !cc.caps.CanWrite(dst) {
+				return deny(ctx, op, dst)
+			}
+		}
+		if e.Err != nil {
+			return e.Err
+		}
+	}
+	return nil
+}
+
+// Rename moves the file if the plugin holds a write capability for both the old and new paths. A directory is only
+// moved if the plugin can write every entry beneath it both where it is and where it would be moved to, and every
+// entry of any directory it would replace, so that renaming a directory cannot move or overwrite files the plugin
+// holds no capability for.
+func (cc *CapabilityChecker) Rename(ctx context.Context, oldPath, newPath string) error {
+	if !cc.caps.CanWrite(oldPath) {
+		return deny(ctx, "Rename", oldPath)
+	}
+	if !cc.caps.CanWrite(newPath) {
+		return deny(ctx, "Rename", newPath)
+	}
+	if info, err := cc.impl.Lstat(ctx, oldPath); err == nil && info.IsDir() {
+		if err := cc.canWriteTree(ctx, "Rename", oldPath, newPath); err != nil {
+			return err
+		}
+		if err := cc.canWriteTree(ctx, "Rename", newPath, ""); err != nil {
+			return err
+		}
+	}
+	return cc.impl.Rename(ctx, oldPath, newPath)
+}
+
+// Copy copies the file if the plugin holds a read capability for src and a write capability for dst.
+func (cc *CapabilityChecker) Copy(ctx context.Context, src, dst string) (int64, error) {
+	if !cc.caps.CanRead(src) {
+		return 0, deny(ctx, "Copy", src)
+	}
+	if !cc.caps.CanWrite(dst) {
+		return 0, deny(ctx, "Copy", dst)
+	}
+	return cc.impl.Copy(ctx, src, dst)
+}
+
+// Chmod changes the file's permissions if the plugin holds a write capability for it.
+func (cc *CapabilityChecker) Chmod(ctx context.Context, path string, mode os.FileMode) error {
+	if !cc.caps.CanWrite(path) {
+		return deny(ctx, "Chmod", path)
+	}
+	return cc.impl.Chmod(ctx, path, mode)
+}
+
+// Truncate resizes the file if the plugin holds a write capability for it.
+func (cc *CapabilityChecker) Truncate(ctx context.Context, path string, size int64) error {
+	if !cc.caps.CanWrite(path) {
+		return deny(ctx, "Truncate", path)
+	}
+	return cc.impl.Truncate(ctx, path, size)
+}
+
+// Append appends to the file if the plugin holds a write capability for it.
+func (cc *CapabilityChecker) Append(ctx context.Context, path string, data []byte, perm os.FileMode) error {
+	if !cc.caps.CanWrite(path) {
+		return deny(ctx, "Append", path)
+	}
+	return cc.impl.Append(ctx, path, data, perm)
+}
+
+// Watch watches the path if the plugin holds a read capability for it. Changes beneath the path are only reported
+// for paths the plugin can also read.
+func (cc *CapabilityChecker) Watch(ctx context.Context, path string, recursive bool) (<-chan WatchEvent, error) {
+	if !cc.caps.CanRead(path) {
+		return nil, deny(ctx, "Watch", path)
+	}
+	events, err := cc.impl.Watch(ctx, path, recursive)
+	if err != nil {
+		return nil, err
+	}
+	filtered := make(chan WatchEvent)
+	go func() {
+		defer close(filtered)
+		for e := range events {
+			if e.Err == nil && !cc.caps.CanRead(e.Path) {
+				continue
+			}
+			select {
+			case filtered <- e:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return filtered, nil
+}
+
+// Walk walks the path if the plugin holds a read capability for it. Entries beneath the path are only reported if
+// the plugin can also read them.
+func (cc *CapabilityChecker) Walk(ctx context.Context, path string, opts WalkOptions) (<-chan WalkEntry, error) {
+	if !cc.caps.CanRead(path) {
+		return nil, deny(ctx, "Walk", path)
+	}
+	entries, err := cc.impl.Walk(ctx, path, opts)
+	if err != nil {
+		return nil, err
+	}
+	filtered := make(chan WalkEntry)
+	go func() {
+		defer close(filtered)
+		for e := range entries {
+			if e.Path != "" && !cc.caps.CanRead(e.Path) {
+				continue
+			}
+			select {
+			case filtered <- e:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return filtered, nil
+}
+
+// Glob matches the pattern if the plugin holds a read capability for the directory it starts from. Only matches
+// the plugin can also read are returned.
+func (cc *CapabilityChecker) Glob(ctx context.Context, pattern string) ([]string, error) {
+	base, _ := splitGlob(pattern)
+	if !cc.caps.CanRead(base) {
+		return nil, deny(ctx, "Glob", pattern)
+	}
+	matches, err := cc.impl.Glob(ctx, pattern)
+	if err != nil {
+		return nil, err
+	}
+	readable := matches[:0]
+	for _, match := range matches {
+		if cc.caps.CanRead(match) {
+			readable = append(readable, match)
+		}
+	}
+	return readable, nil
+}
+
+// BeginTx starts a transaction. No capability is needed, as each write staged in it is checked when it is made.
+func (cc *CapabilityChecker) BeginTx(ctx context.Context) (string, error) {
+	return cc.impl.BeginTx(ctx)
+}
+
+// CommitTx commits the transaction, whose writes were checked as they were staged.
+func (cc *CapabilityChecker) CommitTx(ctx context.Context, txID string) error {
+	return cc.impl.CommitTx(ctx, txID)
+}
+
+// RollbackTx rolls back the transaction.
+func (cc *CapabilityChecker) RollbackTx(ctx context.Context, txID string) error {
+	return cc.impl.RollbackTx(ctx, txID)
+}
+
+// Lock acquires a lock if the plugin holds a read capability for the path, for a shared lock, or a write capability,
+// for an exclusive one.
+func (cc *CapabilityChecker) Lock(ctx context.Context, path string, mode LockMode,
+	lease time.Duration,
+) (string, time.Time, error) {
+	if !cc.canLock(path, mode) {
+		return "", time.Time{}, deny(ctx, "Lock", path)
+	}
+	return cc.impl.Lock(ctx, path, mode, lease)
+}
+
+// TryLock acquires a lock without waiting, given the same capabilities as Lock.
+func (cc *CapabilityChecker) TryLock(ctx context.Context, path string, mode LockMode,
+	lease time.Duration,
+) (string, time.Time, error) {
+	if !cc.canLock(path, mode) {
+		return "", time.Time{}, deny(ctx, "TryLock", path)
+	}
+	return cc.impl.TryLock(ctx, path, mode, lease)
+}
+
+// Unlock releases a lock. No capability is needed, as only the plugin that acquired the lock can release it.
+func (cc *CapabilityChecker) Unlock(ctx context.Context, lockID string) error {
+	return cc.impl.Unlock(ctx, lockID)
+}
+
+// canLock reports whether the plugin may lock path in mode.
+func (cc *CapabilityChecker) canLock(path string, mode LockMode) bool {
+	if mode == LockExclusive {
+		return cc.caps.CanWrite(path)
+	}
+	return cc.caps.CanRead(path)
+}
+
+// GetEnv returns the variable if the plugin holds an env capability for it. As GetEnv cannot report errors,
+// denied keys are logged and read as unset.
+func (cc *CapabilityChecker) GetEnv(ctx context.Context, key string) string {
+	if !cc.caps.CanGetEnv(key) {
+		_ = deny(ctx, "GetEnv", key)
+		return ""
+	}
+	return cc.caps.EnvValue(key, cc.impl.GetEnv(ctx, key))
+}
+
+// LookupEnv returns the variable if the plugin holds an env capability for it.
+func (cc *CapabilityChecker) LookupEnv(ctx context.Context, key string) (string, bool, error) {
+	if !cc.caps.CanGetEnv(key) {
+		return "", false, deny(ctx, "LookupEnv", key)
+	}
+	val, found, err := cc.impl.LookupEnv(ctx, key)
+	if err != nil || !found {
+		return "", found, err
+	}
+	return cc.caps.EnvValue(key, val), true, nil
+}
+
+// Environ lists the variables the plugin holds an env capability for, leaving out the rest.
+func (cc *CapabilityChecker) Environ(ctx context.Context) ([]string, error) {
+	vars, err := cc.impl.Environ(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var visible []string
+	for _, kv := range vars {
+		key, val, _ := strings.Cut(kv, "=")
+		if cc.caps.CanGetEnv(key) {
+			visible = append(visible, key+"="+cc.caps.EnvValue(key, val))
+		}
+	}
+	return visible, nil
+}
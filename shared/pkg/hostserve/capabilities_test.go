@@ -0,0 +1,361 @@
+package hostserve
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+// checkedMemFS returns a context confined to a new root, and a CapabilityChecker granting specs over a MemFS seeded
+// by seed, which is called with unchecked access to the MemFS.
+func checkedMemFS(t *testing.T, specs []string, env map[string]string,
+	seed func(ctx context.Context, m *MemFS),
+) (context.Context, *CapabilityChecker) {
+	t.Helper()
+	ctx, dir := rootContext(t)
+	m := NewMemFS()
+	if seed != nil {
+		seed(ctx, m)
+	}
+	caps, err := NewCapabilities(dir, specs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return ctx, NewCapabilityChecker(NewHostServices(m, NewMapEnv(env)), caps)
+}
+
+func TestParseCapability(t *testing.T) {
+	tests := []struct {
+		spec    string
+		want    Capability
+		wantErr bool
+	}{
+		{spec: "read:config/**", want: Capability{Kind: CapabilityRead, Pattern: "config/**"}},
+		{spec: "write:out/*.txt", want: Capability{Kind: CapabilityWrite, Pattern: "out/*.txt"}},
+		{spec: "env:HOME", want: Capability{Kind: CapabilityEnv, Pattern: "HOME"}},
+		{spec: "exec:/bin/sh", wantErr: true},
+		{spec: "read:", wantErr: true},
+		{spec: "read", wantErr: true},
+		{spec: "read:[", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			got, err := ParseCapability(tt.spec)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidCapability) {
+					t.Errorf("ParseCapability(%q) = %v, want ErrInvalidCapability", tt.spec, err)
+				}
+				return
+			}
+			if err != nil || got != tt.want {
+				t.Errorf("ParseCapability(%q) = %+v, %v, want %+v", tt.spec, got, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestCapabilitiesMatchPaths(t *testing.T) {
+	caps, err := NewCapabilities("/plugin", []string{"read:data/**", "write:out/*", "read:/etc/hosts", "env:APP_*"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name string
+		got  bool
+		want bool
+	}{
+		{name: "read below granted directory", got: caps.CanRead("data/a/b.txt"), want: true},
+		{name: "read granted directory itself", got: caps.CanRead("data"), want: true},
+		{name: "read absolute path under base", got: caps.CanRead("/plugin/data/x"), want: true},
+		{name: "read ungranted sibling", got: caps.CanRead("secrets/key"), want: false},
+		{name: "read climbing out of grant", got: caps.CanRead("data/../secrets/key"), want: false},
+		{name: "read absolute grant", got: caps.CanRead("/etc/hosts"), want: true},
+		{name: "read is not write", got: caps.CanWrite("data/a.txt"), want: false},
+		{name: "write direct child", got: caps.CanWrite("out/a.txt"), want: true},
+		{name: "write grandchild of single star", got: caps.CanWrite("out/a/b.txt"), want: false},
+		{name: "write is not read", got: caps.CanRead("out/a.txt"), want: false},
+		{name: "env matching pattern", got: caps.CanGetEnv("APP_MODE"), want: true},
+		{name: "env not matching pattern", got: caps.CanGetEnv("HOME"), want: false},
+	}
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("%s: got %v, want %v", tt.name, tt.got, tt.want)
+		}
+	}
+}
+
+func TestCapabilityCheckerAllowsAndDenies(t *testing.T) {
+	// Dotfiles are granted, so that internal files are shown to be refused for what they are
+	specs := []string{"read:in/**", "read:**/.*", "write:out/**"}
+	ctx, cc := checkedMemFS(t, specs, nil, func(ctx context.Context, m *MemFS) {
+		for _, dir := range []string{"in", "out", "secret"} {
+			if err := m.MkdirAll(ctx, dir, 0); err != nil {
+				t.Fatal(err)
+			}
+		}
+		for _, file := range []string{"in/a.txt", "out/b.txt", "secret/key"} {
+			if err := m.WriteFile(ctx, file, []byte(file), 0); err != nil {
+				t.Fatal(err)
+			}
+		}
+	})
+
+	tests := []struct {
+		name    string
+		call    func() error
+		allowed bool
+	}{
+		{name: "ReadFile granted", allowed: true, call: func() error {
+			_, err := cc.ReadFile(ctx, "in/a.txt")
+			return err
+		}},
+		{name: "ReadFile ungranted", call: func() error {
+			_, err := cc.ReadFile(ctx, "secret/key")
+			return err
+		}},
+		{name: "ReadFile with only write grant", call: func() error {
+			_, err := cc.ReadFile(ctx, "out/b.txt")
+			return err
+		}},
+		{name: "ReadDir granted", allowed: true, call: func() error {
+			_, err := cc.ReadDir(ctx, "in")
+			return err
+		}},
+		{name: "Stat ungranted", call: func() error {
+			_, err := cc.Stat(ctx, "secret/key")
+			return err
+		}},
+		{name: "WriteFile granted", allowed: true, call: func() error {
+			return cc.WriteFile(ctx, "out/c.txt", []byte("c"), 0)
+		}},
+		{name: "WriteFile with only read grant", call: func() error {
+			return cc.WriteFile(ctx, "in/a.txt", []byte("x"), 0)
+		}},
+		{name: "WriteFileIf reading contents without read grant", call: func() error {
+			return cc.WriteFileIf(ctx, "out/b.txt", []byte("x"), 0, WritePrecondition{SHA256: []byte{0}})
+		}},
+		{name: "Remove ungranted", call: func() error {
+			return cc.Remove(ctx, "secret/key")
+		}},
+		{name: "Rename into ungranted path", call: func() error {
+			return cc.Rename(ctx, "out/b.txt", "secret/b.txt")
+		}},
+		{name: "Copy from granted to granted", allowed: true, call: func() error {
+			_, err := cc.Copy(ctx, "in/a.txt", "out/a.txt")
+			return err
+		}},
+		{name: "Copy from unreadable source", call: func() error {
+			_, err := cc.Copy(ctx, "secret/key", "out/key")
+			return err
+		}},
+		{name: "shared Lock with read grant", allowed: true, call: func() error {
+			id, _, err := cc.TryLock(ctx, "in/a.txt", LockShared, 0)
+			if err == nil {
+				err = cc.Unlock(ctx, id)
+			}
+			return err
+		}},
+		{name: "WriteFile naming an internal file", call: func() error {
+			return cc.WriteFile(ctx, "out/"+tempPrefix+"x-b.txt", []byte("x"), 0)
+		}},
+		{name: "ReadFile naming an internal file", call: func() error {
+			_, err := cc.ReadFile(ctx, "in/"+tempPrefix+"x-a.txt")
+			return err
+		}},
+		{name: "ReadDir naming a staging directory", call: func() error {
+			_, err := cc.ReadDir(ctx, TxDirPrefix+"x")
+			return err
+		}},
+		{name: "exclusive Lock with only read grant", call: func() error {
+			_, _, err := cc.TryLock(ctx, "in/a.txt", LockExclusive, 0)
+			return err
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.call()
+			switch {
+			case tt.allowed && err != nil:
+				t.Errorf("got %v, want the call allowed", err)
+			case !tt.allowed && !errors.Is(err, ErrAccessDenied):
+				t.Errorf("got %v, want ErrAccessDenied", err)
+			}
+		})
+	}
+}
+
+func TestCapabilityCheckerFiltersListings(t *testing.T) {
+	ctx, cc := checkedMemFS(t, []string{"read:.", "read:pub/**"}, nil, func(ctx context.Context, m *MemFS) {
+		for _, file := range []string{"pub/a.txt", "pub/b.txt", "priv/c.txt"} {
+			if err := m.MkdirAll(ctx, filepath.Dir(file), 0); err != nil {
+				t.Fatal(err)
+			}
+			if err := m.WriteFile(ctx, file, nil, 0); err != nil {
+				t.Fatal(err)
+			}
+		}
+	})
+
+	matches, err := cc.Glob(ctx, "**/*.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	slices.Sort(matches)
+	if want := []string{"pub/a.txt", "pub/b.txt"}; !slices.Equal(matches, want) {
+		t.Errorf("Glob = %v, want %v", matches, want)
+	}
+
+	entries, err := cc.Walk(ctx, ".", WalkOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var walked []string
+	for e := range entries {
+		walked = append(walked, e.Path)
+	}
+	if want := []string{".", "pub", "pub/a.txt", "pub/b.txt"}; !slices.Equal(walked, want) {
+		t.Errorf("Walk = %v, want %v", walked, want)
+	}
+}
+
+func TestCapabilityCheckerEnv(t *testing.T) {
+	env := map[string]string{"APP_MODE": "dev", "APP_TOKEN": "t0ken", "GITHUB_TOKEN": "gh", "HOME": "/home/x"}
+	ctx, cc := checkedMemFS(t, []string{"env:APP_*", "env:GITHUB_TOKEN"}, env, nil)
+
+	tests := []struct {
+		key   string
+		want  string
+		found bool
+		err   error
+	}{
+		{key: "APP_MODE", want: "dev", found: true},
+		{key: "APP_TOKEN", want: RedactedValue, found: true},
+		{key: "GITHUB_TOKEN", want: "gh", found: true},
+		{key: "APP_MISSING"},
+		{key: "HOME", err: ErrAccessDenied},
+	}
+	for _, tt := range tests {
+		got, found, err := cc.LookupEnv(ctx, tt.key)
+		if got != tt.want || found != tt.found || !errors.Is(err, tt.err) {
+			t.Errorf("LookupEnv(%q) = %q, %v, %v, want %q, %v, %v", tt.key, got, found, err, tt.want, tt.found,
+				tt.err)
+		}
+	}
+
+	vars, err := cc.Environ(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	slices.Sort(vars)
+	want := []string{"APP_MODE=dev", "APP_TOKEN=" + RedactedValue, "GITHUB_TOKEN=gh"}
+	if !slices.Equal(vars, want) {
+		t.Errorf("Environ = %v, want %v", vars, want)
+	}
+}
+
+func TestCapabilityCheckerMkdirAllChecksMissingParents(t *testing.T) {
+	specs := []string{"write:out", "write:out/a", "write:out/*/c"}
+	ctx, cc := checkedMemFS(t, specs, nil, func(ctx context.Context, m *MemFS) {
+		if err := m.MkdirAll(ctx, "existing", 0); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	tests := []struct {
+		name    string
+		path    string
+		allowed bool
+	}{
+		{name: "granted with granted parent", path: "out/a", allowed: true},
+		{name: "granted leaf under ungranted missing parent", path: "out/b/c"},
+		{name: "ungranted leaf", path: "existing/d"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := cc.MkdirAll(ctx, tt.path, 0)
+			switch {
+			case tt.allowed && err != nil:
+				t.Errorf("MkdirAll(%q) = %v, want it allowed", tt.path, err)
+			case !tt.allowed && !errors.Is(err, ErrAccessDenied):
+				t.Errorf("MkdirAll(%q) = %v, want ErrAccessDenied", tt.path, err)
+			}
+		})
+	}
+	if _, err := cc.impl.Stat(ctx, "out/b"); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("ungranted parent out/b was created: %v", err)
+	}
+}
+
+func TestCapabilityCheckerRemoveAllChecksEveryEntry(t *testing.T) {
+	ctx, cc := checkedMemFS(t, []string{"write:out/*", "write:tmp/**"}, nil, func(ctx context.Context, m *MemFS) {
+		for _, file := range []string{"out/x/deep/file", "tmp/y/deep/file"} {
+			if err := m.MkdirAll(ctx, filepath.Dir(file), 0); err != nil {
+				t.Fatal(err)
+			}
+			if err := m.WriteFile(ctx, file, nil, 0); err != nil {
+				t.Fatal(err)
+			}
+		}
+	})
+
+	if err := cc.RemoveAll(ctx, "out/x"); !errors.Is(err, ErrAccessDenied) {
+		t.Errorf("RemoveAll(out/x) = %v, want ErrAccessDenied", err)
+	}
+	if _, err := cc.impl.Stat(ctx, "out/x/deep/file"); err != nil {
+		t.Errorf("denied RemoveAll removed part of the tree: %v", err)
+	}
+	if err := cc.RemoveAll(ctx, "tmp/y"); err != nil {
+		t.Errorf("RemoveAll(tmp/y) = %v, want it allowed", err)
+	}
+	if err := cc.RemoveAll(ctx, "tmp/missing"); err != nil {
+		t.Errorf("RemoveAll(tmp/missing) = %v, want nil", err)
+	}
+}
+
+func TestCapabilityCheckerRenameChecksDirectoryTrees(t *testing.T) {
+	specs := []string{"write:locked", "write:open", "write:open/**", "write:shallow", "write:dst", "write:dst/**",
+		"write:full", "write:full/a.txt", "write:file.txt", "write:moved.txt"}
+	tests := []struct {
+		name             string
+		oldPath, newPath string
+		// denied is the path the rename is refused for, if it is
+		denied string
+	}{
+		{name: "source entry not writable", oldPath: "locked", newPath: "dst", denied: "locked/a.txt"},
+		{name: "destination entry not writable", oldPath: "open", newPath: "shallow", denied: "shallow/a.txt"},
+		{name: "replaced entry not writable", oldPath: "open", newPath: "full", denied: "full/b.txt"},
+		{name: "whole trees writable", oldPath: "open", newPath: "dst"},
+		{name: "file", oldPath: "file.txt", newPath: "moved.txt"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cc := checkedMemFS(t, specs, nil, func(ctx context.Context, m *MemFS) {
+				for _, file := range []string{"locked/a.txt", "open/a.txt", "full/b.txt", "file.txt"} {
+					if err := m.MkdirAll(ctx, filepath.Dir(file), 0); err != nil {
+						t.Fatal(err)
+					}
+					if err := m.WriteFile(ctx, file, nil, 0); err != nil {
+						t.Fatal(err)
+					}
+				}
+			})
+			err := cc.Rename(ctx, tt.oldPath, tt.newPath)
+			if tt.denied == "" {
+				if err != nil {
+					t.Fatalf("Rename(%s, %s) = %v, want it allowed", tt.oldPath, tt.newPath, err)
+				}
+				return
+			}
+			var denied *AccessDeniedError
+			if !errors.As(err, &denied) || denied.Resource != tt.denied {
+				t.Fatalf("Rename(%s, %s) = %v, want access to %s denied", tt.oldPath, tt.newPath, err, tt.denied)
+			}
+			if _, err := cc.impl.Stat(ctx, tt.oldPath); err != nil {
+				t.Errorf("denied Rename moved %s: %v", tt.oldPath, err)
+			}
+		})
+	}
+}
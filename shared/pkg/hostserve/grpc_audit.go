@@ -0,0 +1,74 @@
+package hostserve
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/bmj2728/hst/shared/pkg/audit"
+	"github.com/hashicorp/go-hclog"
+)
+
+// callAudit accumulates the audit event for a single host service call while it is being served.
+type callAudit struct {
+	sink   audit.Sink
+	event  audit.Event
+	denied bool
+	err    error
+}
+
+// auditKey is the context key used to carry the in-progress callAudit to host service implementations.
+type auditKey struct{}
+
+// beginAudit starts the audit event for a call to method made by the server's plugin. The returned record must be
+// finished once the call completes.
+func (s *HostServiceGRPCServer) beginAudit(method, path, envKey string) *callAudit {
+	return &callAudit{
+		sink: s.Audit,
+		event: audit.Event{
+			Time:     time.Now(),
+			ClientID: s.ClientID,
+			Method:   method,
+			Path:     path,
+			EnvKey:   envKey,
+		},
+	}
+}
+
+// withAudit returns a copy of ctx carrying the call's audit record, so that access checks further down the
+// chain can mark the call as denied even when they cannot return an error.
+func withAudit(ctx context.Context, a *callAudit) context.Context {
+	return context.WithValue(ctx, auditKey{}, a)
+}
+
+// markDenied flags the call in ctx, if it is being audited, as rejected by an access check.
+func markDenied(ctx context.Context) {
+	if a, ok := ctx.Value(auditKey{}).(*callAudit); ok {
+		a.denied = true
+	}
+}
+
+// fail records the error the call failed with.
+func (a *callAudit) fail(err error) {
+	a.err = err
+}
+
+// failStatus records the error the call failed with and converts it into the gRPC status returned to the plugin.
+func (a *callAudit) failStatus(err error) error {
+	a.fail(err)
+	return StatusError(err)
+}
+
+// finish completes the event and records it to the sink, if the server has one.
+func (a *callAudit) finish() {
+	a.event.Duration = time.Since(a.event.Time)
+	a.event.Allowed = !a.denied && !errors.Is(a.err, ErrAccessDenied)
+	if a.err != nil {
+		a.event.Error = a.err.Error()
+	}
+	hclog.Default().Debug("Host service call", "clientID", a.event.ClientID, "method", a.event.Method,
+		"allowed", a.event.Allowed, "duration", a.event.Duration)
+	if a.sink != nil {
+		a.sink.Record(a.event)
+	}
+}
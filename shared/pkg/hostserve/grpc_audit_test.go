@@ -0,0 +1,41 @@
+package hostserve
+
+import (
+	"context"
+	"testing"
+
+	"github.com/bmj2728/hst/shared/pkg/audit"
+	"github.com/bmj2728/hst/shared/protogen/hostserve/v1"
+)
+
+func TestAuditRecordsBytesWritten(t *testing.T) {
+	dir := t.TempDir()
+	server, err := NewHostServiceGRPCServer(NewHostServices(NewHostFS(), NewHostEnv()), "test", dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(server.Close)
+	ring := audit.NewRingBuffer(8)
+	server.Audit = ring
+
+	ctx := context.Background()
+	data := []byte("hello")
+	written := &hostservev1.WriteFileRequest{Path: "a.txt", Data: data, Perm: 0o644}
+	if _, err := server.WriteFile(ctx, written); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := server.WriteFile(ctx, &hostservev1.WriteFileRequest{Path: "missing/a.txt", Data: data}); err == nil {
+		t.Fatal("WriteFile into a missing directory succeeded")
+	}
+
+	events := ring.Events()
+	if len(events) != 2 {
+		t.Fatalf("recorded %d events, want 2", len(events))
+	}
+	if e := events[0]; e.Method != "WriteFile" || e.BytesWritten != int64(len(data)) || e.Error != "" {
+		t.Errorf("successful write audited as %+v, want %d bytes written", e, len(data))
+	}
+	if e := events[1]; e.BytesWritten != 0 || e.Error == "" || !e.Allowed {
+		t.Errorf("failed write audited as %+v, want no bytes written and its error", e)
+	}
+}
@@ -4,17 +4,39 @@ import (
 	"context"
 
 	hostservev1 "github.com/bmj2728/hst/shared/protogen/hostserve/v1"
+	"github.com/hashicorp/go-hclog"
 )
 
 // GetEnv retrieves the value of the specified environment variable via a gRPC call to the host service.
-// Returns an empty string if an error occurs.
+// Returns an empty string if an error occurs; use LookupEnv to tell failures and unset variables apart.
 func (c *HostServiceGRPCClient) GetEnv(ctx context.Context, key string) string {
-	ctx = addClientIDToContext(ctx, c.clientID)
 	resp, err := c.client.GetEnv(ctx, &hostservev1.GetEnvRequest{
 		Key: key,
 	})
 	if err != nil {
+		hclog.Default().Warn("Failed to get environment variable from host", "key", key, "err", err)
 		return ""
 	}
 	return resp.Val
 }
+
+// LookupEnv retrieves the value of the specified environment variable from the host service, reporting whether it
+// is set.
+func (c *HostServiceGRPCClient) LookupEnv(ctx context.Context, key string) (string, bool, error) {
+	resp, err := c.client.LookupEnv(ctx, &hostservev1.LookupEnvRequest{
+		Key: key,
+	})
+	if err != nil {
+		return "", false, ErrorFromStatus(err)
+	}
+	return resp.Val, resp.Found, nil
+}
+
+// Environ lists the environment variables the host makes visible to the plugin, in "KEY=value" form.
+func (c *HostServiceGRPCClient) Environ(ctx context.Context) ([]string, error) {
+	resp, err := c.client.Environ(ctx, &hostservev1.EnvironRequest{})
+	if err != nil {
+		return nil, ErrorFromStatus(err)
+	}
+	return resp.Vars, nil
+}
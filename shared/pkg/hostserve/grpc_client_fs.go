@@ -2,33 +2,29 @@ package hostserve
 
 import (
 	"context"
+	"errors"
+	"io"
 	"io/fs"
 	"os"
 
 	"github.com/bmj2728/hst/shared/protogen/hostserve/v1"
+	"google.golang.org/grpc"
 )
 
 // ReadDir retrieves a list of directory entries from the given path through a gRPC call to the host service.
 // Returns a slice of fs.DirEntry or an error if the operation fails.
 func (c *HostServiceGRPCClient) ReadDir(ctx context.Context, path string) ([]fs.DirEntry, error) {
-	ctx = addClientIDToContext(ctx, c.clientID)
 	resp, err := c.client.ReadDir(ctx, &hostservev1.ReadDirRequest{
 		Path: path,
 	})
 	if err != nil {
-		return nil, err
-	}
-	if resp.Error != nil {
-		return nil, &HostServiceError{Message: *resp.Error}
+		return nil, ErrorFromStatus(err)
 	}
 
 	// Convert protobuf DirEntry to fs.DirEntry
 	var entries []fs.DirEntry
 	for _, entry := range resp.Entries {
-		entries = append(entries, &RemoteDirEntry{
-			name:  entry.Name,
-			isDir: entry.IsDir,
-		})
+		entries = append(entries, dirEntryFromProto(entry))
 	}
 
 	return entries, nil
@@ -37,38 +33,304 @@ func (c *HostServiceGRPCClient) ReadDir(ctx context.Context, path string) ([]fs.
 // ReadFile reads the specified file from the given directory and returns its contents as a byte slice.
 // Returns an error if the file cannot be read or the service encounters an issue.
 func (c *HostServiceGRPCClient) ReadFile(ctx context.Context, path string) ([]byte, error) {
-	ctx = addClientIDToContext(ctx, c.clientID)
 	resp, err := c.client.ReadFile(ctx, &hostservev1.ReadFileRequest{
 		Path: path,
 	})
 	if err != nil {
-		return nil, &HostServiceError{Message: err.Error()}
-	}
-	if resp.Error != nil {
-		return nil, &HostServiceError{Message: *resp.Error}
+		return nil, ErrorFromStatus(err)
 	}
 	return resp.Contents, nil
 }
 
 func (c *HostServiceGRPCClient) WriteFile(ctx context.Context, path string, data []byte, perm os.FileMode) error {
-	ctx = addClientIDToContext(ctx, c.clientID)
+	return c.WriteFileIf(ctx, path, data, perm, WritePrecondition{})
+}
+
+// WriteFileIf writes data to the file at path on the host, provided the file satisfies pre. A refused write returns
+// an error matching ErrConflict.
+func (c *HostServiceGRPCClient) WriteFileIf(ctx context.Context, path string, data []byte, perm os.FileMode,
+	pre WritePrecondition,
+) error {
+	if perm == 0 {
+		perm = StandardPermissions
+	}
+	_, err := c.client.WriteFile(ctx, &hostservev1.WriteFileRequest{
+		Path:         path,
+		Data:         data,
+		Perm:         uint32(perm),
+		TxId:         TxFromContext(ctx),
+		Precondition: preconditionToProto(pre),
+	})
+	if err != nil {
+		return ErrorFromStatus(err)
+	}
+	return nil
+}
+
+// Stat retrieves the file info for the given path from the host service, following symbolic links.
+func (c *HostServiceGRPCClient) Stat(ctx context.Context, path string) (fs.FileInfo, error) {
+	resp, err := c.client.Stat(ctx, &hostservev1.StatRequest{
+		Path: path,
+	})
+	return fileInfoFromResponse(resp, err)
+}
+
+// Lstat retrieves the file info for the given path from the host service without following a final
+// symbolic link.
+func (c *HostServiceGRPCClient) Lstat(ctx context.Context, path string) (fs.FileInfo, error) {
+	resp, err := c.client.Lstat(ctx, &hostservev1.StatRequest{
+		Path: path,
+	})
+	return fileInfoFromResponse(resp, err)
+}
+
+// fileInfoFromResponse converts the result of a Stat or Lstat call into an fs.FileInfo or an error.
+func fileInfoFromResponse(resp *hostservev1.StatResponse, err error) (fs.FileInfo, error) {
+	if err != nil {
+		return nil, ErrorFromStatus(err)
+	}
+	return fileInfoFromProto(resp.Info), nil
+}
+
+// ReadFileStream opens a server stream for the specified file and returns an io.ReadCloser over its chunks.
+// Closing the reader before the end of the file cancels the underlying stream.
+func (c *HostServiceGRPCClient) ReadFileStream(ctx context.Context, path string) (io.ReadCloser, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	stream, err := c.client.ReadFileStream(ctx, &hostservev1.ReadFileRequest{
+		Path: path,
+	})
+	if err != nil {
+		cancel()
+		return nil, ErrorFromStatus(err)
+	}
+	return &remoteFileReader{stream: stream, cancel: cancel}, nil
+}
+
+// WriteFileStream opens a client stream that creates or truncates the specified file and returns an
+// io.WriteCloser for its contents. The write is only committed by the host once the writer is closed.
+func (c *HostServiceGRPCClient) WriteFileStream(ctx context.Context,
+	path string,
+	perm os.FileMode,
+) (io.WriteCloser, error) {
 	if perm == 0 {
 		perm = StandardPermissions
 	}
-	resp, err := c.client.WriteFile(ctx, &hostservev1.WriteFileRequest{
+	ctx, cancel := context.WithCancel(ctx)
+	stream, err := c.client.WriteFileStream(ctx)
+	if err != nil {
+		cancel()
+		return nil, ErrorFromStatus(err)
+	}
+	return &remoteFileWriter{stream: stream, cancel: cancel, path: path, perm: perm, txID: TxFromContext(ctx)}, nil
+}
+
+// remoteFileReader implements io.ReadCloser on top of a ReadFileStream server stream.
+type remoteFileReader struct {
+	stream grpc.ServerStreamingClient[hostservev1.ReadFileChunk]
+	cancel context.CancelFunc
+	buf    []byte
+	offset uint64
+	final  bool
+	err    error
+}
+
+// Read copies buffered chunk data into p, receiving the next chunk from the host when the buffer is empty.
+// It returns io.EOF once the final chunk has been consumed.
+func (r *remoteFileReader) Read(p []byte) (int, error) {
+	for len(r.buf) == 0 {
+		if r.err != nil {
+			return 0, r.err
+		}
+		if r.final {
+			return 0, io.EOF
+		}
+		r.err = r.recv()
+	}
+	n := copy(p, r.buf)
+	r.buf = r.buf[n:]
+	return n, nil
+}
+
+// recv receives the next chunk from the stream, validating its offset before buffering its data.
+func (r *remoteFileReader) recv() error {
+	msg, err := r.stream.Recv()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return ErrIncompleteStream
+		}
+		return ErrorFromStatus(err)
+	}
+	chunk := msg.GetChunk()
+	if chunk.GetOffset() != r.offset {
+		return ErrChunkOutOfOrder
+	}
+	r.buf = chunk.GetData()
+	r.offset += uint64(len(r.buf))
+	r.final = chunk.GetIsFinal()
+	return nil
+}
+
+// Close cancels the underlying stream, releasing the file on the host.
+func (r *remoteFileReader) Close() error {
+	r.cancel()
+	return nil
+}
+
+// remoteFileWriter implements io.WriteCloser on top of a WriteFileStream client stream. The path, permissions and
+// transaction are sent with every chunk; the host only uses the values from the first one.
+type remoteFileWriter struct {
+	stream grpc.ClientStreamingClient[hostservev1.WriteFileChunk, hostservev1.WriteFileResponse]
+	cancel context.CancelFunc
+	path   string
+	perm   os.FileMode
+	txID   string
+	offset uint64
+	err    error
+	closed bool
+}
+
+// Write sends p to the host in chunks of at most StreamChunkSize bytes.
+func (w *remoteFileWriter) Write(p []byte) (int, error) {
+	if w.closed {
+		return 0, os.ErrClosed
+	}
+	if w.err != nil {
+		return 0, w.err
+	}
+	var written int
+	for len(p) > 0 {
+		n := min(len(p), StreamChunkSize)
+		if err := w.send(p[:n], false); err != nil {
+			w.err = err
+			return written, err
+		}
+		written += n
+		p = p[n:]
+	}
+	return written, nil
+}
+
+// send transmits a single chunk. If the host has already ended the stream, the host's response is collected so
+// the caller sees the real cause rather than io.EOF.
+func (w *remoteFileWriter) send(data []byte, final bool) error {
+	err := w.stream.Send(&hostservev1.WriteFileChunk{
+		Path: w.path,
+		Perm: uint32(w.perm),
+		TxId: w.txID,
+		Chunk: &hostservev1.FileChunk{
+			Data:    data,
+			Offset:  w.offset,
+			IsFinal: final,
+		},
+	})
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			if err := w.closeAndRecv(); err != nil {
+				return err
+			}
+			return ErrIncompleteStream
+		}
+		return ErrorFromStatus(err)
+	}
+	w.offset += uint64(len(data))
+	return nil
+}
+
+// closeAndRecv half-closes the stream and converts the host's response into an error.
+func (w *remoteFileWriter) closeAndRecv() error {
+	_, err := w.stream.CloseAndRecv()
+	if err != nil {
+		return ErrorFromStatus(err)
+	}
+	return nil
+}
+
+// Close sends the final chunk and waits for the host to confirm the file has been written.
+func (w *remoteFileWriter) Close() error {
+	if w.closed {
+		return os.ErrClosed
+	}
+	w.closed = true
+	defer w.cancel()
+	if w.err != nil {
+		return w.err
+	}
+	if err := w.send(nil, true); err != nil {
+		return err
+	}
+	return w.closeAndRecv()
+}
+
+// ReadDirStream opens a directory on the host for batched reads. Entries are streamed from the host in chunks of
+// up to ReadDirBatchSize and buffered until the caller reads them. Closing the reader cancels the stream.
+func (c *HostServiceGRPCClient) ReadDirStream(ctx context.Context, path string) (DirReader, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	stream, err := c.client.ReadDirStream(ctx, &hostservev1.ReadDirRequest{
 		Path: path,
-		Data: data,
-		Perm: uint32(perm),
 	})
 	if err != nil {
-		return &HostServiceError{Message: err.Error()}
+		cancel()
+		return nil, ErrorFromStatus(err)
 	}
-	// Defensive: handle unexpected nil resp
-	if resp == nil {
-		return &HostServiceError{Message: "nil response from WriteFile"}
+	r := &remoteDirReader{stream: stream, cancel: cancel}
+	// Read the first chunk so that errors opening the directory are returned here rather than from ReadDir
+	r.fill()
+	if r.err != nil && !errors.Is(r.err, io.EOF) {
+		cancel()
+		return nil, r.err
 	}
-	if resp.Error != nil {
-		return &HostServiceError{Message: *resp.Error}
+	return r, nil
+}
+
+// remoteDirReader is a DirReader that buffers directory entries received from a ReadDirStream call.
+type remoteDirReader struct {
+	stream grpc.ServerStreamingClient[hostservev1.ReadDirChunk]
+	cancel context.CancelFunc
+	buf    []fs.DirEntry
+	err    error
+}
+
+// fill receives the next chunk from the stream, appending its entries to the buffer or recording the error that
+// ended the stream.
+func (r *remoteDirReader) fill() {
+	msg, err := r.stream.Recv()
+	switch {
+	case errors.Is(err, io.EOF):
+		r.err = io.EOF
+	case err != nil:
+		r.err = ErrorFromStatus(err)
+	default:
+		for _, entry := range msg.Entries {
+			r.buf = append(r.buf, dirEntryFromProto(entry))
+		}
+	}
+}
+
+// ReadDir returns up to n buffered entries, receiving more from the host as needed. With n <= 0 it returns all
+// remaining entries.
+func (r *remoteDirReader) ReadDir(n int) ([]fs.DirEntry, error) {
+	for r.err == nil && (n <= 0 || len(r.buf) < n) {
+		r.fill()
 	}
+	if n <= 0 {
+		entries := r.buf
+		r.buf = nil
+		if errors.Is(r.err, io.EOF) {
+			return entries, nil
+		}
+		return entries, r.err
+	}
+	if len(r.buf) == 0 {
+		return nil, r.err
+	}
+	k := min(n, len(r.buf))
+	entries := r.buf[:k:k]
+	r.buf = r.buf[k:]
+	return entries, nil
+}
+
+// Close cancels the stream.
+func (r *remoteDirReader) Close() error {
+	r.cancel()
 	return nil
 }
@@ -0,0 +1,104 @@
+package hostserve
+
+import (
+	"context"
+	"os"
+
+	"github.com/bmj2728/hst/shared/protogen/hostserve/v1"
+)
+
+// MkdirAll creates the directory at path, along with any missing parents, on the host.
+func (c *HostServiceGRPCClient) MkdirAll(ctx context.Context, path string, perm os.FileMode) error {
+	_, err := c.client.MkdirAll(ctx, &hostservev1.MkdirAllRequest{
+		Path: path,
+		Perm: uint32(perm),
+	})
+	if err != nil {
+		return ErrorFromStatus(err)
+	}
+	return nil
+}
+
+// Remove removes the file or empty directory at path on the host.
+func (c *HostServiceGRPCClient) Remove(ctx context.Context, path string) error {
+	_, err := c.client.Remove(ctx, &hostservev1.RemoveRequest{
+		Path: path,
+	})
+	if err != nil {
+		return ErrorFromStatus(err)
+	}
+	return nil
+}
+
+// RemoveAll removes path and any children it contains on the host.
+func (c *HostServiceGRPCClient) RemoveAll(ctx context.Context, path string) error {
+	_, err := c.client.RemoveAll(ctx, &hostservev1.RemoveAllRequest{
+		Path: path,
+	})
+	if err != nil {
+		return ErrorFromStatus(err)
+	}
+	return nil
+}
+
+// Rename moves oldPath to newPath on the host.
+func (c *HostServiceGRPCClient) Rename(ctx context.Context, oldPath, newPath string) error {
+	_, err := c.client.Rename(ctx, &hostservev1.RenameRequest{
+		OldPath: oldPath,
+		NewPath: newPath,
+	})
+	if err != nil {
+		return ErrorFromStatus(err)
+	}
+	return nil
+}
+
+// Copy copies the file at src to dst on the host without transferring its contents to the plugin.
+func (c *HostServiceGRPCClient) Copy(ctx context.Context, src, dst string) (int64, error) {
+	resp, err := c.client.Copy(ctx, &hostservev1.CopyRequest{
+		Src:  src,
+		Dst:  dst,
+		TxId: TxFromContext(ctx),
+	})
+	if err != nil {
+		return 0, ErrorFromStatus(err)
+	}
+	return resp.BytesCopied, nil
+}
+
+// Chmod changes the permissions of the file at path on the host.
+func (c *HostServiceGRPCClient) Chmod(ctx context.Context, path string, mode os.FileMode) error {
+	_, err := c.client.Chmod(ctx, &hostservev1.ChmodRequest{
+		Path: path,
+		Mode: uint32(mode),
+	})
+	if err != nil {
+		return ErrorFromStatus(err)
+	}
+	return nil
+}
+
+// Truncate changes the size of the file at path on the host.
+func (c *HostServiceGRPCClient) Truncate(ctx context.Context, path string, size int64) error {
+	_, err := c.client.Truncate(ctx, &hostservev1.TruncateRequest{
+		Path: path,
+		Size: size,
+	})
+	if err != nil {
+		return ErrorFromStatus(err)
+	}
+	return nil
+}
+
+// Append appends data to the file at path on the host, creating it if it does not exist.
+func (c *HostServiceGRPCClient) Append(ctx context.Context, path string, data []byte, perm os.FileMode) error {
+	_, err := c.client.Append(ctx, &hostservev1.AppendRequest{
+		Path: path,
+		Data: data,
+		Perm: uint32(perm),
+	})
+	if err != nil {
+		return ErrorFromStatus(err)
+	}
+	return nil
+}
@@ -0,0 +1,53 @@
+package hostserve
+
+import (
+	"context"
+	"time"
+
+	"github.com/bmj2728/hst/shared/protogen/hostserve/v1"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// Lock acquires an advisory lock on path on the host, waiting until it is available or ctx is done, and returns its
+// ID and the time its lease runs out. A zero lease uses the host's default, and the host cuts leases longer than it
+// allows. The host releases the lock if the plugin disconnects while holding it.
+func (c *HostServiceGRPCClient) Lock(ctx context.Context, path string, mode LockMode,
+	lease time.Duration,
+) (string, time.Time, error) {
+	resp, err := c.client.Lock(ctx, lockRequest(path, mode, lease))
+	if err != nil {
+		return "", time.Time{}, ErrorFromStatus(err)
+	}
+	return resp.LockId, resp.Expires.AsTime(), nil
+}
+
+// TryLock acquires an advisory lock on path on the host like Lock, but fails with ErrLocked rather than waiting.
+func (c *HostServiceGRPCClient) TryLock(ctx context.Context, path string, mode LockMode,
+	lease time.Duration,
+) (string, time.Time, error) {
+	resp, err := c.client.TryLock(ctx, lockRequest(path, mode, lease))
+	if err != nil {
+		return "", time.Time{}, ErrorFromStatus(err)
+	}
+	return resp.LockId, resp.Expires.AsTime(), nil
+}
+
+// Unlock releases a lock acquired on the host.
+func (c *HostServiceGRPCClient) Unlock(ctx context.Context, lockID string) error {
+	_, err := c.client.Unlock(ctx, &hostservev1.UnlockRequest{
+		LockId: lockID,
+	})
+	if err != nil {
+		return ErrorFromStatus(err)
+	}
+	return nil
+}
+
+// lockRequest builds the request for a lock on path, leaving the lease unset if it is zero.
+func lockRequest(path string, mode LockMode, lease time.Duration) *hostservev1.LockRequest {
+	req := &hostservev1.LockRequest{Path: path, Mode: hostservev1.LockMode(mode)}
+	if lease != 0 {
+		req.Lease = durationpb.New(lease)
+	}
+	return req
+}
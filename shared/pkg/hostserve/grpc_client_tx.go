@@ -0,0 +1,38 @@
+package hostserve
+
+import (
+	"context"
+
+	"github.com/bmj2728/hst/shared/protogen/hostserve/v1"
+)
+
+// BeginTx starts a transaction on the host and returns its ID. Pass it to WithTx to stage writes in the transaction.
+func (c *HostServiceGRPCClient) BeginTx(ctx context.Context) (string, error) {
+	resp, err := c.client.BeginTx(ctx, &hostservev1.BeginTxRequest{})
+	if err != nil {
+		return "", ErrorFromStatus(err)
+	}
+	return resp.TxId, nil
+}
+
+// CommitTx commits a transaction on the host, moving every write staged in it into place.
+func (c *HostServiceGRPCClient) CommitTx(ctx context.Context, txID string) error {
+	_, err := c.client.CommitTx(ctx, &hostservev1.CommitTxRequest{
+		TxId: txID,
+	})
+	if err != nil {
+		return ErrorFromStatus(err)
+	}
+	return nil
+}
+
+// RollbackTx rolls back a transaction on the host, discarding every write staged in it.
+func (c *HostServiceGRPCClient) RollbackTx(ctx context.Context, txID string) error {
+	_, err := c.client.RollbackTx(ctx, &hostservev1.RollbackTxRequest{
+		TxId: txID,
+	})
+	if err != nil {
+		return ErrorFromStatus(err)
+	}
+	return nil
+}
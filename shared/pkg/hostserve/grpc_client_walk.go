@@ -0,0 +1,78 @@
+package hostserve
+
+import (
+	"context"
+	"errors"
+	"io"
+
+	"github.com/bmj2728/hst/shared/protogen/hostserve/v1"
+)
+
+// Walk asks the host to walk the tree rooted at path and returns a channel of the entries it visits, so that a whole
+// tree is listed in a single call rather than one ReadDir per directory. Errors starting the walk, such as a missing
+// path or a denied capability, are returned directly. The channel is closed once the walk is complete or ctx is done;
+// if the walk failed part way, the final entry carries the error.
+func (c *HostServiceGRPCClient) Walk(ctx context.Context, path string, opts WalkOptions) (<-chan WalkEntry, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	stream, err := c.client.Walk(ctx, &hostservev1.WalkRequest{
+		Path:           path,
+		MaxDepth:       int32(opts.MaxDepth),
+		FollowSymlinks: opts.FollowSymlinks,
+		Skip:           opts.Skip,
+	})
+	if err != nil {
+		cancel()
+		return nil, ErrorFromStatus(err)
+	}
+	// The walked path itself is always the first entry, so the first chunk reports whether the walk started
+	first, err := stream.Recv()
+	if err != nil {
+		cancel()
+		if errors.Is(err, io.EOF) {
+			return nil, ErrIncompleteStream
+		}
+		return nil, ErrorFromStatus(err)
+	}
+
+	entries := make(chan WalkEntry)
+	go func() {
+		defer cancel()
+		defer close(entries)
+		send := func(e WalkEntry) bool {
+			select {
+			case entries <- e:
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		}
+		msg := first
+		for {
+			for _, entry := range msg.Entries {
+				if !send(walkEntryFromProto(entry)) {
+					return
+				}
+			}
+			msg, err = stream.Recv()
+			switch {
+			case errors.Is(err, io.EOF) || ctx.Err() != nil:
+				return
+			case err != nil:
+				send(WalkEntry{Err: ErrorFromStatus(err)})
+				return
+			}
+		}
+	}()
+	return entries, nil
+}
+
+// Glob returns the paths on the host matching a doublestar pattern.
+func (c *HostServiceGRPCClient) Glob(ctx context.Context, pattern string) ([]string, error) {
+	resp, err := c.client.Glob(ctx, &hostservev1.GlobRequest{
+		Pattern: pattern,
+	})
+	if err != nil {
+		return nil, ErrorFromStatus(err)
+	}
+	return resp.Matches, nil
+}
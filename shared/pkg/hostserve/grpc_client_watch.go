@@ -0,0 +1,60 @@
+package hostserve
+
+import (
+	"context"
+	"errors"
+	"io"
+
+	"github.com/bmj2728/hst/shared/protogen/hostserve/v1"
+)
+
+// Watch asks the host to watch path and returns a channel of the changes it reports. It waits for the host to
+// acknowledge the watch, so errors such as a missing path or a denied capability are returned directly. The
+// channel is closed when ctx is done or the host ends the watch; if the watch failed, the final event carries the
+// error.
+func (c *HostServiceGRPCClient) Watch(ctx context.Context, path string, recursive bool) (<-chan WatchEvent, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	stream, err := c.client.Watch(ctx, &hostservev1.WatchRequest{
+		Path:      path,
+		Recursive: recursive,
+	})
+	if err != nil {
+		cancel()
+		return nil, ErrorFromStatus(err)
+	}
+	_, err = stream.Recv()
+	if err != nil {
+		cancel()
+		if errors.Is(err, io.EOF) {
+			return nil, ErrIncompleteStream
+		}
+		return nil, ErrorFromStatus(err)
+	}
+
+	events := make(chan WatchEvent)
+	go func() {
+		defer cancel()
+		defer close(events)
+		for {
+			msg, err := stream.Recv()
+			var e WatchEvent
+			switch {
+			case errors.Is(err, io.EOF) || ctx.Err() != nil:
+				return
+			case err != nil:
+				e.Err = ErrorFromStatus(err)
+			default:
+				e = WatchEvent{Path: msg.Path, Op: WatchOp(msg.Op)}
+			}
+			select {
+			case events <- e:
+			case <-ctx.Done():
+				return
+			}
+			if e.Err != nil {
+				return
+			}
+		}
+	}()
+	return events, nil
+}
@@ -0,0 +1,159 @@
+package hostserve
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"os"
+
+	"github.com/bmj2728/hst/shared/protogen/hostserve/v1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// HostServiceError represents an error returned by the host service. Op and Path describe the operation that failed,
+// when the host reported them, and Message is the full description of the error. Err, when set, is the sentinel
+// error it corresponds to, such as fs.ErrNotExist or ErrAccessDenied, so callers can test it with errors.Is.
+type HostServiceError struct {
+	Op      string
+	Path    string
+	Message string
+	Err     error
+}
+
+// Error returns the error message stored in the HostServiceError as a string.
+func (e *HostServiceError) Error() string {
+	return e.Message
+}
+
+// Unwrap returns the sentinel error the HostServiceError corresponds to, if any.
+func (e *HostServiceError) Unwrap() error {
+	return e.Err
+}
+
+// errorKinds lists the sentinel errors that survive the trip over gRPC, the kind each is reported as and the status
+// code it is sent with. Errors are classified by the first sentinel they match, so more specific sentinels come
+// first.
+var errorKinds = []struct {
+	kind hostservev1.ErrorKind
+	err  error
+	code codes.Code
+}{
+	{hostservev1.ErrorKind_ERROR_KIND_ACCESS_DENIED, ErrAccessDenied, codes.PermissionDenied},
+	{hostservev1.ErrorKind_ERROR_KIND_INVALID_PATH, ErrInvalidPath, codes.InvalidArgument},
+	{hostservev1.ErrorKind_ERROR_KIND_NOT_EXIST, fs.ErrNotExist, codes.NotFound},
+	{hostservev1.ErrorKind_ERROR_KIND_EXIST, fs.ErrExist, codes.AlreadyExists},
+	{hostservev1.ErrorKind_ERROR_KIND_PERMISSION, fs.ErrPermission, codes.PermissionDenied},
+	{hostservev1.ErrorKind_ERROR_KIND_INVALID, fs.ErrInvalid, codes.InvalidArgument},
+	{hostservev1.ErrorKind_ERROR_KIND_CLOSED, fs.ErrClosed, codes.FailedPrecondition},
+	{hostservev1.ErrorKind_ERROR_KIND_UNSUPPORTED, ErrWatchUnsupported, codes.Unimplemented},
+	{hostservev1.ErrorKind_ERROR_KIND_CHUNK_OUT_OF_ORDER, ErrChunkOutOfOrder, codes.InvalidArgument},
+	{hostservev1.ErrorKind_ERROR_KIND_INCOMPLETE_STREAM, ErrIncompleteStream, codes.InvalidArgument},
+	{hostservev1.ErrorKind_ERROR_KIND_WALK_CYCLE, ErrWalkCycle, codes.FailedPrecondition},
+	{hostservev1.ErrorKind_ERROR_KIND_CANCELED, context.Canceled, codes.Canceled},
+	{hostservev1.ErrorKind_ERROR_KIND_DEADLINE_EXCEEDED, context.DeadlineExceeded, codes.DeadlineExceeded},
+	{hostservev1.ErrorKind_ERROR_KIND_TX_NOT_FOUND, ErrTxNotFound, codes.NotFound},
+	{hostservev1.ErrorKind_ERROR_KIND_CONFLICT, ErrConflict, codes.Aborted},
+	{hostservev1.ErrorKind_ERROR_KIND_LOCKED, ErrLocked, codes.Unavailable},
+	{hostservev1.ErrorKind_ERROR_KIND_LOCK_NOT_FOUND, ErrLockNotFound, codes.NotFound},
+	{hostservev1.ErrorKind_ERROR_KIND_QUOTA_EXCEEDED, ErrQuotaExceeded, codes.ResourceExhausted},
+}
+
+// codeErrors maps the status codes that unambiguously identify a sentinel to it, for statuses that arrive without an
+// ErrorDetail.
+var codeErrors = map[codes.Code]error{
+	codes.PermissionDenied: ErrAccessDenied,
+	codes.NotFound:         fs.ErrNotExist,
+	codes.AlreadyExists:    fs.ErrExist,
+	codes.Canceled:         context.Canceled,
+	codes.DeadlineExceeded: context.DeadlineExceeded,
+}
+
+// errorDetail describes err for the trip to the plugin. The kind is the first sentinel err matches, and the op and
+// path are taken from the *fs.PathError, *os.LinkError, *AccessDeniedError, *ConflictError, *QuotaError or
+// *HostServiceError it wraps.
+func errorDetail(err error) *hostservev1.ErrorDetail {
+	d := &hostservev1.ErrorDetail{Message: err.Error()}
+	for _, k := range errorKinds {
+		if errors.Is(err, k.err) {
+			d.Kind = k.kind
+			break
+		}
+	}
+	var (
+		pathErr     *fs.PathError
+		linkErr     *os.LinkError
+		deniedErr   *AccessDeniedError
+		conflictErr *ConflictError
+		quotaErr    *QuotaError
+		hostErr     *HostServiceError
+	)
+	switch {
+	case errors.As(err, &deniedErr):
+		d.Op, d.Path = deniedErr.Op, deniedErr.Resource
+	case errors.As(err, &conflictErr):
+		d.Op, d.Path = "write", conflictErr.Path
+	case errors.As(err, &quotaErr):
+		d.Op, d.Path = quotaErr.Op, quotaErr.Path
+	case errors.As(err, &hostErr):
+		d.Op, d.Path = hostErr.Op, hostErr.Path
+	case errors.As(err, &pathErr):
+		d.Op, d.Path = pathErr.Op, pathErr.Path
+	case errors.As(err, &linkErr):
+		d.Op, d.Path = linkErr.Op, linkErr.Old
+	}
+	return d
+}
+
+// errorFromDetail restores the HostServiceError an ErrorDetail describes.
+func errorFromDetail(d *hostservev1.ErrorDetail) *HostServiceError {
+	e := &HostServiceError{Op: d.GetOp(), Path: d.GetPath(), Message: d.GetMessage()}
+	for _, k := range errorKinds {
+		if k.kind == d.GetKind() {
+			e.Err = k.err
+			break
+		}
+	}
+	return e
+}
+
+// StatusError converts err into a gRPC status error for return from a service handler. The status code reflects the
+// kind of error and an ErrorDetail carrying its kind, op and path is attached, so that ErrorFromStatus can restore it
+// on the other side. Errors that already carry a status, such as failed stream sends, are returned unchanged.
+func StatusError(err error) error {
+	if err == nil {
+		return nil
+	}
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+	d := errorDetail(err)
+	code := codes.Unknown
+	for _, k := range errorKinds {
+		if k.kind == d.Kind {
+			code = k.code
+			break
+		}
+	}
+	st := status.New(code, err.Error())
+	if detailed, derr := st.WithDetails(d); derr == nil {
+		st = detailed
+	}
+	return st.Err()
+}
+
+// ErrorFromStatus converts an error returned by a gRPC call into a HostServiceError, restoring its sentinel, op and
+// path from the attached ErrorDetail. Statuses without one, such as transport failures, are restored from their
+// code alone where it identifies a sentinel.
+func ErrorFromStatus(err error) *HostServiceError {
+	st := status.Convert(err)
+	for _, detail := range st.Details() {
+		if d, ok := detail.(*hostservev1.ErrorDetail); ok {
+			return errorFromDetail(d)
+		}
+	}
+	if sentinel, ok := codeErrors[st.Code()]; ok {
+		return &HostServiceError{Message: st.Message(), Err: sentinel}
+	}
+	return &HostServiceError{Message: err.Error()}
+}
@@ -0,0 +1,77 @@
+package hostserve
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestStatusErrorRoundTrip(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		code     codes.Code
+		sentinel error
+		op, path string
+	}{
+		{name: "conflict", err: &ConflictError{Path: "a.txt", Reason: "file exists"}, code: codes.Aborted,
+			sentinel: ErrConflict, op: "write", path: "a.txt"},
+		{name: "wrapped conflict", err: fmt.Errorf("commit: %w", &ConflictError{Path: "b.txt", Reason: "r"}),
+			code: codes.Aborted, sentinel: ErrConflict, op: "write", path: "b.txt"},
+		{name: "access denied", err: &AccessDeniedError{Op: "ReadFile", Resource: "secret"},
+			code: codes.PermissionDenied, sentinel: ErrAccessDenied, op: "ReadFile", path: "secret"},
+		{name: "not exist", err: &fs.PathError{Op: "open", Path: "missing", Err: fs.ErrNotExist},
+			code: codes.NotFound, sentinel: fs.ErrNotExist, op: "open", path: "missing"},
+		{name: "locked", err: &fs.PathError{Op: "lock", Path: "a.txt", Err: ErrLocked}, code: codes.Unavailable,
+			sentinel: ErrLocked, op: "lock", path: "a.txt"},
+		{name: "quota exceeded", err: &QuotaError{Op: "WriteFile", Path: "a.txt", Resource: ResourceBytesWritten},
+			code: codes.ResourceExhausted, sentinel: ErrQuotaExceeded, op: "WriteFile", path: "a.txt"},
+		{name: "lock not found", err: fmt.Errorf("%w: id", ErrLockNotFound), code: codes.NotFound,
+			sentinel: ErrLockNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := StatusError(tt.err)
+			if got := status.Code(err); got != tt.code {
+				t.Errorf("status code = %v, want %v", got, tt.code)
+			}
+			restored := ErrorFromStatus(err)
+			if !errors.Is(restored, tt.sentinel) {
+				t.Errorf("restored error %v does not match %v", restored, tt.sentinel)
+			}
+			if restored.Op != tt.op || restored.Path != tt.path {
+				t.Errorf("restored op and path = %q, %q, want %q, %q", restored.Op, restored.Path, tt.op, tt.path)
+			}
+			if restored.Message != tt.err.Error() {
+				t.Errorf("restored message = %q, want %q", restored.Message, tt.err.Error())
+			}
+		})
+	}
+}
+
+func TestGRPCClientReportsConflict(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "a.txt"), []byte("old"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	client := grpcClient(t, NewHostServices(NewHostFS(), NewHostEnv()), dir)
+
+	err := client.WriteFileIf(context.Background(), "a.txt", []byte("new"), 0, WritePrecondition{MustNotExist: true})
+	if !errors.Is(err, ErrConflict) {
+		t.Fatalf("WriteFileIf = %v, want ErrConflict", err)
+	}
+	var hostErr *HostServiceError
+	if !errors.As(err, &hostErr) || hostErr.Path != "a.txt" {
+		t.Errorf("WriteFileIf = %#v, want a HostServiceError for a.txt", err)
+	}
+	if data, err := os.ReadFile(filepath.Join(dir, "a.txt")); string(data) != "old" {
+		t.Errorf("a.txt = %q, %v after refused write, want %q", data, err, "old")
+	}
+}
@@ -4,16 +4,46 @@ import (
 	"context"
 
 	hostservev1 "github.com/bmj2728/hst/shared/protogen/hostserve/v1"
-	"github.com/hashicorp/go-hclog"
 )
 
 // GetEnv handles a gRPC request to retrieve the value of an environment variable identified by the request key.
 func (s *HostServiceGRPCServer) GetEnv(ctx context.Context,
 	request *hostservev1.GetEnvRequest) (*hostservev1.GetEnvResponse, error) {
 
-	clientID := getClientIDFromContext(ctx)
-	hclog.Default().Info("GetEnv request from client", "clientID", clientID)
+	a := s.beginAudit("GetEnv", "", request.Key)
+	defer a.finish()
+	ctx = s.callContext(ctx, a)
 
 	val := s.Impl.GetEnv(ctx, request.Key)
 	return &hostservev1.GetEnvResponse{Val: val}, nil
 }
+
+// LookupEnv handles a gRPC request for the value of an environment variable and whether it is set.
+func (s *HostServiceGRPCServer) LookupEnv(ctx context.Context,
+	request *hostservev1.LookupEnvRequest) (*hostservev1.LookupEnvResponse, error) {
+
+	a := s.beginAudit("LookupEnv", "", request.Key)
+	defer a.finish()
+	ctx = s.callContext(ctx, a)
+
+	val, found, err := s.Impl.LookupEnv(ctx, request.Key)
+	if err != nil {
+		return nil, a.failStatus(err)
+	}
+	return &hostservev1.LookupEnvResponse{Val: val, Found: found}, nil
+}
+
+// Environ handles a gRPC request to list the environment variables visible to the plugin.
+func (s *HostServiceGRPCServer) Environ(ctx context.Context,
+	request *hostservev1.EnvironRequest) (*hostservev1.EnvironResponse, error) {
+
+	a := s.beginAudit("Environ", "", "")
+	defer a.finish()
+	ctx = s.callContext(ctx, a)
+
+	vars, err := s.Impl.Environ(ctx)
+	if err != nil {
+		return nil, a.failStatus(err)
+	}
+	return &hostservev1.EnvironResponse{Vars: vars}, nil
+}
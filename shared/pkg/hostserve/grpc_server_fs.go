@@ -2,10 +2,13 @@ package hostserve
 
 import (
 	"context"
+	"errors"
+	"io"
 	"os"
 
 	"github.com/bmj2728/hst/shared/protogen/hostserve/v1"
 	"github.com/hashicorp/go-hclog"
+	"google.golang.org/grpc"
 )
 
 // ReadDir processes a gRPC request to read contents of a directory specified by the request path and returns
@@ -14,30 +17,23 @@ func (s *HostServiceGRPCServer) ReadDir(ctx context.Context,
 	request *hostservev1.ReadDirRequest,
 ) (*hostservev1.ReadDirResponse, error) {
 
-	clientID := getClientIDFromContext(ctx)
-	hclog.Default().Info("ReadDir request from client", "clientID", clientID)
+	a := s.beginAudit("ReadDir", request.Path, "")
+	defer a.finish()
+	ctx = s.callContext(ctx, a)
 
 	entries, err := s.Impl.ReadDir(ctx, request.Path)
 	if err != nil {
-		errMsg := err.Error()
-		return &hostservev1.ReadDirResponse{
-			Entries: nil,
-			Error:   &errMsg,
-		}, nil
+		return nil, a.failStatus(err)
 	}
 
 	// Convert fs.DirEntry to protobuf DirEntry
 	var pbEntries []*hostservev1.DirEntry
 	for _, entry := range entries {
-		pbEntries = append(pbEntries, &hostservev1.DirEntry{
-			Name:  entry.Name(),
-			IsDir: entry.IsDir(),
-		})
+		pbEntries = append(pbEntries, dirEntryToProto(entry))
 	}
 
 	return &hostservev1.ReadDirResponse{
 		Entries: pbEntries,
-		Error:   nil,
 	}, nil
 }
 
@@ -47,34 +43,223 @@ func (s *HostServiceGRPCServer) ReadFile(ctx context.Context,
 	request *hostservev1.ReadFileRequest,
 ) (*hostservev1.ReadFileResponse, error) {
 
-	clientID := getClientIDFromContext(ctx)
-	hclog.Default().Info("ReadFile request from client", "clientID", clientID)
+	a := s.beginAudit("ReadFile", request.Path, "")
+	defer a.finish()
+	ctx = s.callContext(ctx, a)
 
 	bytes, err := s.Impl.ReadFile(ctx, request.Path)
 	if err != nil {
-		errMsg := err.Error()
-		return &hostservev1.ReadFileResponse{
-			Contents: nil,
-			Error:    &errMsg,
-		}, nil
+		return nil, a.failStatus(err)
 	}
+	a.event.BytesRead = int64(len(bytes))
 	return &hostservev1.ReadFileResponse{
 		Contents: bytes,
-		Error:    nil,
 	}, nil
 }
 
+// WriteFile handles a gRPC request to write data to a file with the requested permissions, provided the file
+// satisfies the request's precondition if it has one.
 func (s *HostServiceGRPCServer) WriteFile(ctx context.Context,
 	request *hostservev1.WriteFileRequest,
 ) (*hostservev1.WriteFileResponse, error) {
 
-	clientID := getClientIDFromContext(ctx)
-	hclog.Default().Info("WriteFile request from client", "clientID", clientID)
+	a := s.beginAudit("WriteFile", request.Path, "")
+	defer a.finish()
+	ctx = s.callContext(ctx, a)
+	ctx = callTx(ctx, a, request.TxId)
 
-	err := s.Impl.WriteFile(ctx, request.Path, request.Data, os.FileMode(request.Perm))
+	// Only conditional writes are passed on as WriteFileIf, so that Impl and the checkers wrapping it see, name and
+	// charge each write as the call the plugin made, and do not treat every plain write as a conditional one.
+	var err error
+	if pre := preconditionFromProto(request.Precondition); pre.IsZero() {
+		err = s.Impl.WriteFile(ctx, request.Path, request.Data, os.FileMode(request.Perm))
+	} else {
+		err = s.Impl.WriteFileIf(ctx, request.Path, request.Data, os.FileMode(request.Perm), pre)
+	}
+	if err != nil {
+		return nil, a.failStatus(err)
+	}
+	a.event.BytesWritten = int64(len(request.Data))
+	return &hostservev1.WriteFileResponse{}, nil
+}
+
+// Stat handles a gRPC request for the file info of a path, following symbolic links.
+func (s *HostServiceGRPCServer) Stat(ctx context.Context,
+	request *hostservev1.StatRequest,
+) (*hostservev1.StatResponse, error) {
+
+	a := s.beginAudit("Stat", request.Path, "")
+	defer a.finish()
+	ctx = s.callContext(ctx, a)
+
+	info, err := s.Impl.Stat(ctx, request.Path)
+	if err != nil {
+		return nil, a.failStatus(err)
+	}
+	return &hostservev1.StatResponse{Info: fileInfoToProto(info)}, nil
+}
+
+// Lstat handles a gRPC request for the file info of a path without following a final symbolic link.
+func (s *HostServiceGRPCServer) Lstat(ctx context.Context,
+	request *hostservev1.StatRequest,
+) (*hostservev1.StatResponse, error) {
+
+	a := s.beginAudit("Lstat", request.Path, "")
+	defer a.finish()
+	ctx = s.callContext(ctx, a)
+
+	info, err := s.Impl.Lstat(ctx, request.Path)
+	if err != nil {
+		return nil, a.failStatus(err)
+	}
+	return &hostservev1.StatResponse{Info: fileInfoToProto(info)}, nil
+}
+
+// ReadFileStream handles a gRPC request to read a file as a sequence of chunks. Each chunk carries its offset
+// within the file and the last chunk is flagged as final.
+func (s *HostServiceGRPCServer) ReadFileStream(request *hostservev1.ReadFileRequest,
+	stream grpc.ServerStreamingServer[hostservev1.ReadFileChunk],
+) error {
+
+	a := s.beginAudit("ReadFileStream", request.Path, "")
+	defer a.finish()
+	ctx := s.callContext(stream.Context(), a)
+
+	reader, err := s.Impl.ReadFileStream(ctx, request.Path)
+	if err != nil {
+		return a.failStatus(err)
+	}
+	defer func() {
+		if err := reader.Close(); err != nil {
+			hclog.Default().Error("Failed to close file stream", "path", request.Path, "err", err)
+		}
+	}()
+
+	buf := make([]byte, StreamChunkSize)
+	var offset uint64
+	for {
+		n, err := io.ReadFull(reader, buf)
+		final := errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF)
+		if err != nil && !final {
+			return a.failStatus(err)
+		}
+		if err := stream.Send(&hostservev1.ReadFileChunk{
+			Chunk: &hostservev1.FileChunk{
+				Data:    buf[:n],
+				Offset:  offset,
+				IsFinal: final,
+			},
+		}); err != nil {
+			a.fail(err)
+			return err
+		}
+		a.event.BytesRead += int64(n)
+		if final {
+			return nil
+		}
+		offset += uint64(n)
+	}
+}
+
+// WriteFileStream handles a gRPC request to write a file from a sequence of chunks. The path and permissions are
+// taken from the first chunk, chunks must arrive in order and the stream must end with a chunk flagged as final.
+func (s *HostServiceGRPCServer) WriteFileStream(
+	stream grpc.ClientStreamingServer[hostservev1.WriteFileChunk, hostservev1.WriteFileResponse],
+) error {
+
+	a := s.beginAudit("WriteFileStream", "", "")
+	defer a.finish()
+	ctx := s.callContext(stream.Context(), a)
+
+	first, err := stream.Recv()
 	if err != nil {
-		errMsg := err.Error()
-		return &hostservev1.WriteFileResponse{Error: &errMsg}, nil
+		if errors.Is(err, io.EOF) {
+			return a.failStatus(ErrIncompleteStream)
+		}
+		a.fail(err)
+		return err
+	}
+	a.event.Path = first.Path
+	ctx = callTx(ctx, a, first.TxId)
+
+	writer, err := s.Impl.WriteFileStream(ctx, first.Path, os.FileMode(first.Perm))
+	if err != nil {
+		return a.failStatus(err)
+	}
+
+	msg := first
+	var written uint64
+	for {
+		chunk := msg.GetChunk()
+		if chunk.GetOffset() != written {
+			abortWriter(writer)
+			return a.failStatus(ErrChunkOutOfOrder)
+		}
+		n, err := writer.Write(chunk.GetData())
+		written += uint64(n)
+		if err != nil {
+			abortWriter(writer)
+			return a.failStatus(err)
+		}
+		if chunk.GetIsFinal() {
+			break
+		}
+		msg, err = stream.Recv()
+		if err != nil {
+			abortWriter(writer)
+			if errors.Is(err, io.EOF) {
+				return a.failStatus(ErrIncompleteStream)
+			}
+			a.fail(err)
+			return err
+		}
+	}
+
+	if err := writer.Close(); err != nil {
+		return a.failStatus(err)
+	}
+	// Only a write that was put in place counts, so the bytes are recorded once the writer has closed cleanly
+	a.event.BytesWritten = int64(written)
+	return stream.SendAndClose(&hostservev1.WriteFileResponse{})
+}
+
+// ReadDirStream handles a gRPC request to read a directory as a sequence of batches of at most ReadDirBatchSize
+// entries, so that directories of any size fit within the gRPC message limit.
+func (s *HostServiceGRPCServer) ReadDirStream(request *hostservev1.ReadDirRequest,
+	stream grpc.ServerStreamingServer[hostservev1.ReadDirChunk],
+) error {
+
+	a := s.beginAudit("ReadDirStream", request.Path, "")
+	defer a.finish()
+	ctx := s.callContext(stream.Context(), a)
+
+	dir, err := s.Impl.ReadDirStream(ctx, request.Path)
+	if err != nil {
+		return a.failStatus(err)
+	}
+	defer func() {
+		if err := dir.Close(); err != nil {
+			hclog.Default().Error("Failed to close directory stream", "path", request.Path, "err", err)
+		}
+	}()
+
+	for {
+		entries, err := dir.ReadDir(ReadDirBatchSize)
+		if len(entries) > 0 {
+			pbEntries := make([]*hostservev1.DirEntry, 0, len(entries))
+			for _, entry := range entries {
+				pbEntries = append(pbEntries, dirEntryToProto(entry))
+			}
+			if err := stream.Send(&hostservev1.ReadDirChunk{Entries: pbEntries}); err != nil {
+				a.fail(err)
+				return err
+			}
+		}
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return a.failStatus(err)
+		}
 	}
-	return &hostservev1.WriteFileResponse{Error: nil}, nil
 }
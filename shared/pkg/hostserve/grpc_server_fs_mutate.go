@@ -0,0 +1,134 @@
+package hostserve
+
+import (
+	"context"
+	"os"
+
+	"github.com/bmj2728/hst/shared/protogen/hostserve/v1"
+)
+
+// MkdirAll handles a gRPC request to create a directory and any missing parents.
+func (s *HostServiceGRPCServer) MkdirAll(ctx context.Context,
+	request *hostservev1.MkdirAllRequest,
+) (*hostservev1.MkdirAllResponse, error) {
+
+	a := s.beginAudit("MkdirAll", request.Path, "")
+	defer a.finish()
+	ctx = s.callContext(ctx, a)
+
+	if err := s.Impl.MkdirAll(ctx, request.Path, os.FileMode(request.Perm)); err != nil {
+		return nil, a.failStatus(err)
+	}
+	return &hostservev1.MkdirAllResponse{}, nil
+}
+
+// Remove handles a gRPC request to remove a file or empty directory.
+func (s *HostServiceGRPCServer) Remove(ctx context.Context,
+	request *hostservev1.RemoveRequest,
+) (*hostservev1.RemoveResponse, error) {
+
+	a := s.beginAudit("Remove", request.Path, "")
+	defer a.finish()
+	ctx = s.callContext(ctx, a)
+
+	if err := s.Impl.Remove(ctx, request.Path); err != nil {
+		return nil, a.failStatus(err)
+	}
+	return &hostservev1.RemoveResponse{}, nil
+}
+
+// RemoveAll handles a gRPC request to remove a path and any children it contains.
+func (s *HostServiceGRPCServer) RemoveAll(ctx context.Context,
+	request *hostservev1.RemoveAllRequest,
+) (*hostservev1.RemoveAllResponse, error) {
+
+	a := s.beginAudit("RemoveAll", request.Path, "")
+	defer a.finish()
+	ctx = s.callContext(ctx, a)
+
+	if err := s.Impl.RemoveAll(ctx, request.Path); err != nil {
+		return nil, a.failStatus(err)
+	}
+	return &hostservev1.RemoveAllResponse{}, nil
+}
+
+// Rename handles a gRPC request to move a file from one path to another.
+func (s *HostServiceGRPCServer) Rename(ctx context.Context,
+	request *hostservev1.RenameRequest,
+) (*hostservev1.RenameResponse, error) {
+
+	a := s.beginAudit("Rename", request.OldPath, "")
+	a.event.Target = request.NewPath
+	defer a.finish()
+	ctx = s.callContext(ctx, a)
+
+	if err := s.Impl.Rename(ctx, request.OldPath, request.NewPath); err != nil {
+		return nil, a.failStatus(err)
+	}
+	return &hostservev1.RenameResponse{}, nil
+}
+
+// Copy handles a gRPC request to copy a file on the host and returns the number of bytes copied.
+func (s *HostServiceGRPCServer) Copy(ctx context.Context,
+	request *hostservev1.CopyRequest,
+) (*hostservev1.CopyResponse, error) {
+
+	a := s.beginAudit("Copy", request.Src, "")
+	a.event.Target = request.Dst
+	defer a.finish()
+	ctx = s.callContext(ctx, a)
+	ctx = callTx(ctx, a, request.TxId)
+
+	n, err := s.Impl.Copy(ctx, request.Src, request.Dst)
+	if err != nil {
+		return nil, a.failStatus(err)
+	}
+	a.event.BytesWritten = n
+	return &hostservev1.CopyResponse{BytesCopied: n}, nil
+}
+
+// Chmod handles a gRPC request to change the permissions of a file.
+func (s *HostServiceGRPCServer) Chmod(ctx context.Context,
+	request *hostservev1.ChmodRequest,
+) (*hostservev1.ChmodResponse, error) {
+
+	a := s.beginAudit("Chmod", request.Path, "")
+	defer a.finish()
+	ctx = s.callContext(ctx, a)
+
+	if err := s.Impl.Chmod(ctx, request.Path, os.FileMode(request.Mode)); err != nil {
+		return nil, a.failStatus(err)
+	}
+	return &hostservev1.ChmodResponse{}, nil
+}
+
+// Truncate handles a gRPC request to change the size of a file.
+func (s *HostServiceGRPCServer) Truncate(ctx context.Context,
+	request *hostservev1.TruncateRequest,
+) (*hostservev1.TruncateResponse, error) {
+
+	a := s.beginAudit("Truncate", request.Path, "")
+	defer a.finish()
+	ctx = s.callContext(ctx, a)
+
+	if err := s.Impl.Truncate(ctx, request.Path, request.Size); err != nil {
+		return nil, a.failStatus(err)
+	}
+	return &hostservev1.TruncateResponse{}, nil
+}
+
+// Append handles a gRPC request to append data to a file, creating it if needed.
+func (s *HostServiceGRPCServer) Append(ctx context.Context,
+	request *hostservev1.AppendRequest,
+) (*hostservev1.AppendResponse, error) {
+
+	a := s.beginAudit("Append", request.Path, "")
+	defer a.finish()
+	ctx = s.callContext(ctx, a)
+
+	if err := s.Impl.Append(ctx, request.Path, request.Data, os.FileMode(request.Perm)); err != nil {
+		return nil, a.failStatus(err)
+	}
+	a.event.BytesWritten = int64(len(request.Data))
+	return &hostservev1.AppendResponse{}, nil
+}
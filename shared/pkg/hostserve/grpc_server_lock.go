@@ -0,0 +1,103 @@
+package hostserve
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"slices"
+
+	"github.com/bmj2728/hst/shared/protogen/hostserve/v1"
+	"github.com/hashicorp/go-hclog"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Lock handles a gRPC request to acquire an advisory lock, waiting until it is available or the plugin gives up.
+// The lock is tracked until the plugin releases it, so that it can be released if the plugin disconnects first.
+func (s *HostServiceGRPCServer) Lock(ctx context.Context,
+	request *hostservev1.LockRequest,
+) (*hostservev1.LockResponse, error) {
+
+	a := s.beginAudit("Lock", request.Path, "")
+	defer a.finish()
+	ctx = s.callContext(ctx, a)
+
+	lockID, expires, err := s.Impl.Lock(ctx, request.Path, LockMode(request.Mode), request.Lease.AsDuration())
+	if err != nil {
+		return nil, a.failStatus(err)
+	}
+	s.holdLock(a, lockID)
+	return &hostservev1.LockResponse{LockId: lockID, Expires: timestamppb.New(expires)}, nil
+}
+
+// TryLock handles a gRPC request to acquire an advisory lock without waiting for it.
+func (s *HostServiceGRPCServer) TryLock(ctx context.Context,
+	request *hostservev1.LockRequest,
+) (*hostservev1.LockResponse, error) {
+
+	a := s.beginAudit("TryLock", request.Path, "")
+	defer a.finish()
+	ctx = s.callContext(ctx, a)
+
+	lockID, expires, err := s.Impl.TryLock(ctx, request.Path, LockMode(request.Mode), request.Lease.AsDuration())
+	if err != nil {
+		return nil, a.failStatus(err)
+	}
+	s.holdLock(a, lockID)
+	return &hostservev1.LockResponse{LockId: lockID, Expires: timestamppb.New(expires)}, nil
+}
+
+// Unlock handles a gRPC request to release an advisory lock.
+func (s *HostServiceGRPCServer) Unlock(ctx context.Context,
+	request *hostservev1.UnlockRequest,
+) (*hostservev1.UnlockResponse, error) {
+
+	a := s.beginAudit("Unlock", "", "")
+	a.event.LockID = request.LockId
+	defer a.finish()
+	ctx = s.callContext(ctx, a)
+
+	s.lockMu.Lock()
+	delete(s.locks, request.LockId)
+	s.lockMu.Unlock()
+	if err := s.Impl.Unlock(ctx, request.LockId); err != nil {
+		return nil, a.failStatus(err)
+	}
+	return &hostservev1.UnlockResponse{}, nil
+}
+
+// holdLock records a lock the plugin acquired, in its audit event and among the locks the server tracks.
+func (s *HostServiceGRPCServer) holdLock(a *callAudit, lockID string) {
+	a.event.LockID = lockID
+	s.lockMu.Lock()
+	defer s.lockMu.Unlock()
+	if s.locks == nil {
+		s.locks = make(map[string]struct{})
+	}
+	s.locks[lockID] = struct{}{}
+}
+
+// ReleaseLocks releases every lock the plugin still holds. It is called when the plugin disconnects from host
+// services, and again by Close. Each release is audited as a call made on the plugin's behalf; locks whose lease has
+// already run out need no release and are skipped quietly.
+func (s *HostServiceGRPCServer) ReleaseLocks() {
+	s.lockMu.Lock()
+	lockIDs := slices.Sorted(maps.Keys(s.locks))
+	clear(s.locks)
+	s.lockMu.Unlock()
+
+	for _, lockID := range lockIDs {
+		a := s.beginAudit("Unlock", "", "")
+		a.event.LockID = lockID
+		err := s.Impl.Unlock(s.callContext(context.Background(), a), lockID)
+		switch {
+		case errors.Is(err, ErrLockNotFound):
+		case err != nil:
+			a.fail(err)
+			hclog.Default().Error("Failed to release abandoned lock", "clientID", s.ClientID, "lock", lockID,
+				"err", err)
+		default:
+			hclog.Default().Warn("Released abandoned lock", "clientID", s.ClientID, "lock", lockID)
+		}
+		a.finish()
+	}
+}
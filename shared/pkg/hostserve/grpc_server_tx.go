@@ -0,0 +1,109 @@
+package hostserve
+
+import (
+	"context"
+	"maps"
+	"slices"
+
+	"github.com/bmj2728/hst/shared/protogen/hostserve/v1"
+	"github.com/hashicorp/go-hclog"
+)
+
+// callTx stages the writes of a call in the transaction the plugin sent with it, if any, and records the transaction
+// in the call's audit event.
+func callTx(ctx context.Context, a *callAudit, txID string) context.Context {
+	if txID == "" {
+		return ctx
+	}
+	a.event.TxID = txID
+	return WithTx(ctx, txID)
+}
+
+// BeginTx handles a gRPC request to start a transaction. The transaction is tracked until the plugin ends it, so that
+// it can be rolled back if the plugin disconnects first.
+func (s *HostServiceGRPCServer) BeginTx(ctx context.Context,
+	request *hostservev1.BeginTxRequest,
+) (*hostservev1.BeginTxResponse, error) {
+
+	a := s.beginAudit("BeginTx", "", "")
+	defer a.finish()
+	ctx = s.callContext(ctx, a)
+
+	txID, err := s.Impl.BeginTx(ctx)
+	if err != nil {
+		return nil, a.failStatus(err)
+	}
+	a.event.TxID = txID
+	s.txMu.Lock()
+	defer s.txMu.Unlock()
+	if s.txs == nil {
+		s.txs = make(map[string]struct{})
+	}
+	s.txs[txID] = struct{}{}
+	return &hostservev1.BeginTxResponse{TxId: txID}, nil
+}
+
+// CommitTx handles a gRPC request to commit a transaction.
+func (s *HostServiceGRPCServer) CommitTx(ctx context.Context,
+	request *hostservev1.CommitTxRequest,
+) (*hostservev1.CommitTxResponse, error) {
+
+	a := s.beginAudit("CommitTx", "", "")
+	a.event.TxID = request.TxId
+	defer a.finish()
+	ctx = s.callContext(ctx, a)
+
+	err := s.Impl.CommitTx(ctx, request.TxId)
+	s.endTx(request.TxId)
+	if err != nil {
+		return nil, a.failStatus(err)
+	}
+	return &hostservev1.CommitTxResponse{}, nil
+}
+
+// RollbackTx handles a gRPC request to roll back a transaction.
+func (s *HostServiceGRPCServer) RollbackTx(ctx context.Context,
+	request *hostservev1.RollbackTxRequest,
+) (*hostservev1.RollbackTxResponse, error) {
+
+	a := s.beginAudit("RollbackTx", "", "")
+	a.event.TxID = request.TxId
+	defer a.finish()
+	ctx = s.callContext(ctx, a)
+
+	err := s.Impl.RollbackTx(ctx, request.TxId)
+	s.endTx(request.TxId)
+	if err != nil {
+		return nil, a.failStatus(err)
+	}
+	return &hostservev1.RollbackTxResponse{}, nil
+}
+
+// endTx stops tracking a transaction the plugin has ended.
+func (s *HostServiceGRPCServer) endTx(txID string) {
+	s.txMu.Lock()
+	defer s.txMu.Unlock()
+	delete(s.txs, txID)
+}
+
+// rollbackOpenTxs rolls back every transaction the plugin has not ended. Each rollback is audited as a call made
+// on the plugin's behalf.
+func (s *HostServiceGRPCServer) rollbackOpenTxs() {
+	s.txMu.Lock()
+	txIDs := slices.Sorted(maps.Keys(s.txs))
+	clear(s.txs)
+	s.txMu.Unlock()
+
+	for _, txID := range txIDs {
+		a := s.beginAudit("RollbackTx", "", "")
+		a.event.TxID = txID
+		if err := s.Impl.RollbackTx(s.callContext(context.Background(), a), txID); err != nil {
+			a.fail(err)
+			hclog.Default().Error("Failed to roll back abandoned transaction", "clientID", s.ClientID, "tx", txID,
+				"err", err)
+		} else {
+			hclog.Default().Warn("Rolled back abandoned transaction", "clientID", s.ClientID, "tx", txID)
+		}
+		a.finish()
+	}
+}
@@ -0,0 +1,92 @@
+package hostserve
+
+import (
+	"context"
+
+	"github.com/bmj2728/hst/shared/protogen/hostserve/v1"
+	"google.golang.org/grpc"
+)
+
+// Walk handles a gRPC request to walk a tree. Entries are sent in batches of up to ReadDirBatchSize, and a batch is
+// also sent whenever the walk has no further entry ready, so that slow walks still deliver results progressively.
+func (s *HostServiceGRPCServer) Walk(request *hostservev1.WalkRequest,
+	stream grpc.ServerStreamingServer[hostservev1.WalkChunk],
+) error {
+
+	a := s.beginAudit("Walk", request.Path, "")
+	defer a.finish()
+	ctx, cancel := context.WithCancel(s.callContext(stream.Context(), a))
+	defer cancel()
+
+	entries, err := s.Impl.Walk(ctx, request.Path, WalkOptions{
+		MaxDepth:       int(request.MaxDepth),
+		FollowSymlinks: request.FollowSymlinks,
+		Skip:           request.Skip,
+	})
+	if err != nil {
+		return a.failStatus(err)
+	}
+
+	var batch []*hostservev1.WalkEntry
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		err := stream.Send(&hostservev1.WalkChunk{Entries: batch})
+		batch = nil
+		return err
+	}
+	for {
+		var (
+			e  WalkEntry
+			ok bool
+		)
+		select {
+		case e, ok = <-entries:
+		default:
+			if err := flush(); err != nil {
+				a.fail(err)
+				return err
+			}
+			e, ok = <-entries
+		}
+		if !ok {
+			break
+		}
+		if e.Path == "" && e.Err != nil {
+			if err := flush(); err != nil {
+				a.fail(err)
+				return err
+			}
+			return a.failStatus(e.Err)
+		}
+		batch = append(batch, walkEntryToProto(e))
+		if len(batch) >= ReadDirBatchSize {
+			if err := flush(); err != nil {
+				a.fail(err)
+				return err
+			}
+		}
+	}
+	if err := flush(); err != nil {
+		a.fail(err)
+		return err
+	}
+	return nil
+}
+
+// Glob handles a gRPC request for the paths matching a pattern.
+func (s *HostServiceGRPCServer) Glob(ctx context.Context,
+	request *hostservev1.GlobRequest,
+) (*hostservev1.GlobResponse, error) {
+
+	a := s.beginAudit("Glob", request.Pattern, "")
+	defer a.finish()
+	ctx = s.callContext(ctx, a)
+
+	matches, err := s.Impl.Glob(ctx, request.Pattern)
+	if err != nil {
+		return nil, a.failStatus(err)
+	}
+	return &hostservev1.GlobResponse{Matches: matches}, nil
+}
@@ -0,0 +1,40 @@
+package hostserve
+
+import (
+	"github.com/bmj2728/hst/shared/protogen/hostserve/v1"
+	"google.golang.org/grpc"
+)
+
+// Watch handles a gRPC request to stream changes to a path. Once the watch is established an empty event is sent
+// to acknowledge it, after which every coalesced change is forwarded until the plugin cancels the stream.
+func (s *HostServiceGRPCServer) Watch(request *hostservev1.WatchRequest,
+	stream grpc.ServerStreamingServer[hostservev1.WatchEvent],
+) error {
+
+	a := s.beginAudit("Watch", request.Path, "")
+	defer a.finish()
+	ctx := s.callContext(stream.Context(), a)
+
+	events, err := s.Impl.Watch(ctx, request.Path, request.Recursive)
+	if err != nil {
+		return a.failStatus(err)
+	}
+	if err := stream.Send(&hostservev1.WatchEvent{}); err != nil {
+		a.fail(err)
+		return err
+	}
+
+	for e := range events {
+		if e.Err != nil {
+			return a.failStatus(e.Err)
+		}
+		if err := stream.Send(&hostservev1.WatchEvent{
+			Path: e.Path,
+			Op:   uint32(e.Op),
+		}); err != nil {
+			a.fail(err)
+			return err
+		}
+	}
+	return nil
+}
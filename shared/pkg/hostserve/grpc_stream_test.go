@@ -0,0 +1,277 @@
+package hostserve
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/bmj2728/hst/shared/protogen/hostserve/v1"
+	"google.golang.org/grpc"
+)
+
+// streamData returns n bytes of data that differ from chunk to chunk, so that misplaced chunks are noticed.
+func streamData(n int) []byte {
+	data := make([]byte, n)
+	for i := range data {
+		data[i] = byte(i / 7)
+	}
+	return data
+}
+
+// assertEmptyDir fails the test if dir holds anything, such as a file or temporary file left by a refused write.
+func assertEmptyDir(t *testing.T, dir string) {
+	t.Helper()
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, e := range entries {
+		t.Errorf("%s left behind", e.Name())
+	}
+}
+
+func TestFileStreamRoundTrip(t *testing.T) {
+	tests := []struct {
+		name string
+		size int
+	}{
+		{name: "empty", size: 0},
+		{name: "single byte", size: 1},
+		{name: "exactly one chunk", size: StreamChunkSize},
+		{name: "one byte past a chunk", size: StreamChunkSize + 1},
+		{name: "several chunks", size: 3*StreamChunkSize + 17},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			conn := grpcConn(t, NewHostServices(NewHostFS(), NewHostEnv()), dir)
+			client := NewHostServiceGRPCClient(conn)
+			ctx := context.Background()
+			data := streamData(tt.size)
+
+			w, err := client.WriteFileStream(ctx, "a.bin", 0o644)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if n, err := w.Write(data); err != nil || n != len(data) {
+				t.Fatalf("Write = %d, %v, want %d", n, err, len(data))
+			}
+			if err := w.Close(); err != nil {
+				t.Fatal(err)
+			}
+			if got, err := os.ReadFile(filepath.Join(dir, "a.bin")); err != nil || !bytes.Equal(got, data) {
+				t.Fatalf("written file has %d bytes, %v, want %d", len(got), err, len(data))
+			}
+
+			r, err := client.ReadFileStream(ctx, "a.bin")
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := io.ReadAll(r)
+			_ = r.Close()
+			if err != nil || !bytes.Equal(got, data) {
+				t.Errorf("read back %d bytes, %v, want %d", len(got), err, len(data))
+			}
+
+			// Every chunk the host sends starts where the last ended, holds at most StreamChunkSize bytes, and only
+			// the last is final
+			stream, err := conn.ReadFileStream(ctx, &hostservev1.ReadFileRequest{Path: "a.bin"})
+			if err != nil {
+				t.Fatal(err)
+			}
+			var offset uint64
+			for final := false; !final; {
+				msg, err := stream.Recv()
+				if err != nil {
+					t.Fatalf("stream ended at offset %d: %v", offset, err)
+				}
+				chunk := msg.GetChunk()
+				if chunk.GetOffset() != offset || len(chunk.GetData()) > StreamChunkSize {
+					t.Fatalf("chunk of %d bytes at offset %d, want offset %d", len(chunk.GetData()),
+						chunk.GetOffset(), offset)
+				}
+				offset += uint64(len(chunk.GetData()))
+				final = chunk.GetIsFinal()
+			}
+			if offset != uint64(len(data)) {
+				t.Errorf("chunks held %d bytes, want %d", offset, len(data))
+			}
+		})
+	}
+}
+
+func TestWriteFileStreamRefusesBrokenStreams(t *testing.T) {
+	tests := []struct {
+		name   string
+		chunks []*hostservev1.FileChunk
+		want   error
+	}{
+		{name: "chunk out of order", want: ErrChunkOutOfOrder, chunks: []*hostservev1.FileChunk{
+			{Data: []byte("abc"), Offset: 0},
+			{Data: []byte("def"), Offset: 5, IsFinal: true},
+		}},
+		{name: "first chunk not at start", want: ErrChunkOutOfOrder, chunks: []*hostservev1.FileChunk{
+			{Data: []byte("abc"), Offset: 1, IsFinal: true},
+		}},
+		{name: "no final chunk", want: ErrIncompleteStream, chunks: []*hostservev1.FileChunk{
+			{Data: []byte("abc"), Offset: 0},
+			{Data: streamData(StreamChunkSize), Offset: 3},
+		}},
+		{name: "no chunks", want: ErrIncompleteStream},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			conn := grpcConn(t, NewHostServices(NewHostFS(), NewHostEnv()), dir)
+			stream, err := conn.WriteFileStream(context.Background())
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, chunk := range tt.chunks {
+				// The host may refuse the stream before every chunk is sent, which CloseAndRecv reports
+				if err := stream.Send(&hostservev1.WriteFileChunk{Path: "a.txt", Chunk: chunk}); err != nil {
+					break
+				}
+			}
+			_, err = stream.CloseAndRecv()
+			if !errors.Is(ErrorFromStatus(err), tt.want) {
+				t.Errorf("CloseAndRecv = %v, want %v", err, tt.want)
+			}
+			assertEmptyDir(t, dir)
+		})
+	}
+}
+
+// chunkStream is a ReadFileStream client stream that returns chunks and then io.EOF, standing in for a host that
+// sends them.
+type chunkStream struct {
+	grpc.ClientStream
+	chunks []*hostservev1.FileChunk
+}
+
+// Recv returns the next chunk, or io.EOF once there are none left.
+func (s *chunkStream) Recv() (*hostservev1.ReadFileChunk, error) {
+	if len(s.chunks) == 0 {
+		return nil, io.EOF
+	}
+	chunk := s.chunks[0]
+	s.chunks = s.chunks[1:]
+	return &hostservev1.ReadFileChunk{Chunk: chunk}, nil
+}
+
+func TestRemoteFileReaderRefusesBrokenStreams(t *testing.T) {
+	tests := []struct {
+		name   string
+		chunks []*hostservev1.FileChunk
+		want   error
+	}{
+		{name: "chunk out of order", want: ErrChunkOutOfOrder, chunks: []*hostservev1.FileChunk{
+			{Data: []byte("abc"), Offset: 0},
+			{Data: []byte("def"), Offset: 2, IsFinal: true},
+		}},
+		{name: "no final chunk", want: ErrIncompleteStream, chunks: []*hostservev1.FileChunk{
+			{Data: []byte("abc"), Offset: 0},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &remoteFileReader{stream: &chunkStream{chunks: tt.chunks}, cancel: func() {}}
+			if _, err := io.ReadAll(r); !errors.Is(err, tt.want) {
+				t.Errorf("ReadAll = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestReadDirStreamBatches(t *testing.T) {
+	tests := []struct {
+		name    string
+		entries int
+		// n is the number of entries asked for by each ReadDir call
+		n int
+	}{
+		{name: "empty", entries: 0, n: 100},
+		{name: "one batch", entries: ReadDirBatchSize, n: 100},
+		{name: "several batches", entries: 2*ReadDirBatchSize + 5, n: 100},
+		{name: "reads larger than a batch", entries: 2*ReadDirBatchSize + 5, n: ReadDirBatchSize + 1},
+		{name: "all at once", entries: 2*ReadDirBatchSize + 5, n: -1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			for i := range tt.entries {
+				if err := os.WriteFile(filepath.Join(dir, fmt.Sprintf("f%05d", i)), nil, 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			conn := grpcConn(t, NewHostServices(NewHostFS(), NewHostEnv()), dir)
+			r, err := NewHostServiceGRPCClient(conn).ReadDirStream(context.Background(), ".")
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer r.Close()
+
+			seen := make(map[string]int)
+			for {
+				entries, err := r.ReadDir(tt.n)
+				if tt.n > 0 && len(entries) > tt.n {
+					t.Fatalf("ReadDir(%d) returned %d entries", tt.n, len(entries))
+				}
+				for _, e := range entries {
+					seen[e.Name()]++
+				}
+				if tt.n <= 0 {
+					if err != nil {
+						t.Fatalf("ReadDir(%d) = %v", tt.n, err)
+					}
+					break
+				}
+				if errors.Is(err, io.EOF) {
+					if len(entries) != 0 {
+						t.Errorf("ReadDir returned %d entries along with io.EOF", len(entries))
+					}
+					break
+				}
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+			if len(seen) != tt.entries {
+				t.Errorf("read %d distinct entries, want %d", len(seen), tt.entries)
+			}
+			for name, count := range seen {
+				if count != 1 {
+					t.Errorf("%s read %d times", name, count)
+				}
+			}
+
+			// The host never sends more than ReadDirBatchSize entries in one chunk
+			stream, err := conn.ReadDirStream(context.Background(), &hostservev1.ReadDirRequest{Path: "."})
+			if err != nil {
+				t.Fatal(err)
+			}
+			total := 0
+			for {
+				msg, err := stream.Recv()
+				if errors.Is(err, io.EOF) {
+					break
+				}
+				if err != nil {
+					t.Fatal(err)
+				}
+				if len(msg.GetEntries()) > ReadDirBatchSize {
+					t.Errorf("chunk of %d entries, want at most %d", len(msg.GetEntries()), ReadDirBatchSize)
+				}
+				total += len(msg.GetEntries())
+			}
+			if total != tt.entries {
+				t.Errorf("chunks held %d entries, want %d", total, tt.entries)
+			}
+		})
+	}
+}
@@ -2,136 +2,301 @@ package hostserve
 
 import (
 	"context"
+	"errors"
 	"io/fs"
+	"os"
+	"sync"
 	"time"
 
+	"github.com/bmj2728/hst/shared/pkg/audit"
 	"github.com/bmj2728/hst/shared/protogen/hostserve/v1"
-	"github.com/google/uuid"
-	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// StreamChunkSize is the maximum number of bytes carried by a single FileChunk when streaming file contents.
+// It is kept well below the default 4 MB gRPC message limit.
+const StreamChunkSize = 64 * 1024
+
+// ReadDirBatchSize is the maximum number of entries carried by a single ReadDirChunk when streaming a directory.
+const ReadDirBatchSize = 1024
+
+var (
+	// ErrChunkOutOfOrder indicates a streamed file chunk did not start at the offset the receiver expected.
+	ErrChunkOutOfOrder = errors.New("file chunk out of order")
+	// ErrIncompleteStream indicates a file stream ended before a chunk flagged as final was received.
+	ErrIncompleteStream = errors.New("file stream ended before final chunk")
 )
 
 ///////////////////////////////////////////////////////////////////////////////////////////////////////
 
 // HostServiceGRPCServer provides a gRPC server implementation for host services using the IHostServices interface.
+// ClientID is the identity the host assigned to the plugin this server was registered for. Every call is attributed
+// to it, regardless of anything the plugin sends, and it is made available to Impl via ClientIDFromContext.
+// Servers created with NewHostServiceGRPCServer also confine every call to the plugin's root directory.
+// If Audit is set, one event is recorded to it for every call.
 type HostServiceGRPCServer struct {
-	Impl IHostServices
+	Impl     IHostServices
+	ClientID string
+	Audit    audit.Sink
+	root     *os.Root
 	hostservev1.UnimplementedHostServiceServer
+
+	txMu sync.Mutex
+	// txs holds the transactions the plugin has begun and not yet ended, which are rolled back on Close.
+	txs map[string]struct{}
+
+	lockMu sync.Mutex
+	// locks holds the locks the plugin has acquired and not yet released, which are released on Close.
+	locks map[string]struct{}
+}
+
+// NewHostServiceGRPCServer creates a HostServiceGRPCServer for the plugin identified by clientID, confining all of
+// its filesystem calls to rootDir. The caller must Close the server once it stops serving.
+func NewHostServiceGRPCServer(impl IHostServices, clientID, rootDir string) (*HostServiceGRPCServer, error) {
+	root, err := getRoot(rootDir)
+	if err != nil {
+		return nil, err
+	}
+	return &HostServiceGRPCServer{
+		Impl:     impl,
+		ClientID: clientID,
+		root:     root,
+	}, nil
+}
+
+// Close rolls back any transactions the plugin left open, releases any locks it still holds and releases the root
+// directory the server confines calls to. It is called once the plugin's broker connection stops being served.
+func (s *HostServiceGRPCServer) Close() {
+	s.rollbackOpenTxs()
+	s.ReleaseLocks()
+	if s.root != nil {
+		closeRoot(s.root)
+	}
+}
+
+// callContext attaches the caller's identity, root and audit record to ctx before it is passed on to Impl.
+func (s *HostServiceGRPCServer) callContext(ctx context.Context, a *callAudit) context.Context {
+	ctx = withAudit(ctx, a)
+	ctx = WithClientID(ctx, s.ClientID)
+	if s.root != nil {
+		ctx = WithRoot(ctx, s.root)
+	}
+	return ctx
 }
 
 // HostServiceGRPCClient wraps the filesystemv1.HostServiceClient to provide higher-level client methods.
 type HostServiceGRPCClient struct {
-	client   hostservev1.HostServiceClient
-	clientID string
+	client hostservev1.HostServiceClient
 }
 
 // NewHostServiceGRPCClient creates a new instance of HostServiceGRPCClient wrapping the provided gRPC client.
 func NewHostServiceGRPCClient(client hostservev1.HostServiceClient) *HostServiceGRPCClient {
-	// Generate a unique client ID for this connection - we'll improve this later
-	clientUUID, err := uuid.NewV7()
-	if err != nil {
-		return nil
-	}
 	return &HostServiceGRPCClient{
-		client:   client,
-		clientID: clientUUID.String(),
+		client: client,
 	}
 }
 
 /////////////////////////////////////////////////////////////////////////////////////////////////////
 
-// ctxClientIDKey is the context key used to store the client identifier in a context for outgoing requests.
-const ctxClientIDKey = "client"
+// clientIDKey is the context key used to carry the host-assigned client identifier to host service implementations.
+type clientIDKey struct{}
 
-// addClientIDToContext attaches the specified clientID to the outgoing context metadata for gRPC requests.
-func addClientIDToContext(ctx context.Context, clientID string) context.Context {
-	return metadata.AppendToOutgoingContext(ctx, ctxClientIDKey, clientID)
+// WithClientID returns a copy of ctx carrying the host-assigned identity of the calling plugin.
+func WithClientID(ctx context.Context, clientID string) context.Context {
+	return context.WithValue(ctx, clientIDKey{}, clientID)
 }
 
-// getClientIDFromContext extracts the client ID from the provided gRPC context and returns it as a string.
-// Returns an empty string if no client ID is found or the metadata is unavailable.
-func getClientIDFromContext(ctx context.Context) string {
-	md, ok := metadata.FromIncomingContext(ctx)
-	if !ok {
-		return ""
-	}
-	clientID := md.Get(ctxClientIDKey)
-	if len(clientID) == 0 {
-		return ""
-	}
-	return clientID[0]
+// ClientIDFromContext returns the host-assigned identity of the plugin that made the current call.
+// Returns an empty string if the context was not created by a HostServiceGRPCServer.
+func ClientIDFromContext(ctx context.Context) string {
+	clientID, _ := ctx.Value(clientIDKey{}).(string)
+	return clientID
+}
+
+// rootKey is the context key used to carry the root directory a call is confined to.
+type rootKey struct{}
+
+// WithRoot returns a copy of ctx that confines HostFS calls made with it to root.
+func WithRoot(ctx context.Context, root *os.Root) context.Context {
+	return context.WithValue(ctx, rootKey{}, root)
+}
+
+// RootFromContext returns the root directory the current call is confined to, or nil if it is unconfined.
+func RootFromContext(ctx context.Context) *os.Root {
+	root, _ := ctx.Value(rootKey{}).(*os.Root)
+	return root
 }
 
 // RemoteDirEntry implements fs.DirEntry, this wrapper allows conversion from protobuf to fs.DirEntry
 type RemoteDirEntry struct {
-	name  string
-	isDir bool
+	info *RemoteFileInfo
 }
 
 // Name returns the name of the directory entry as a string.
 func (e *RemoteDirEntry) Name() string {
-	return e.name
+	return e.info.name
 }
 
 // IsDir reports whether the given RemoteDirEntry represents a directory.
 func (e *RemoteDirEntry) IsDir() bool {
-	return e.isDir
+	return e.info.IsDir()
 }
 
-// Type returns the fs.FileMode for the remote directory entry, indicating if it represents a directory or a file.
+// Type returns the type bits of the remote directory entry, such as fs.ModeDir or fs.ModeSymlink.
 func (e *RemoteDirEntry) Type() fs.FileMode {
-	if e.isDir {
-		return fs.ModeDir
-	}
-	return 0
+	return e.info.mode.Type()
 }
 
-// Info returns a fs.FileInfo for the remote directory entry. As full FileInfo is unavailable, it provides limited data.
+// Info returns the fs.FileInfo the host reported for the entry. As with os.ReadDir, a symbolic link is described
+// by the info of the link itself.
 func (e *RemoteDirEntry) Info() (fs.FileInfo, error) {
-	// Remote entries don't have full FileInfo available
-	return &RemoteFileInfo{
-		name:  e.name,
-		isDir: e.isDir,
-	}, nil
+	return e.info, nil
 }
 
-// RemoteFileInfo implements fs.FileInfo for remote directory entries
+// RemoteFileInfo implements fs.FileInfo for files and directory entries reported by the host.
 type RemoteFileInfo struct {
-	name  string
-	isDir bool
+	name    string
+	size    int64
+	mode    fs.FileMode
+	modTime time.Time
 }
 
-// Name returns the base name of the directory entry.
+// Name returns the base name of the file.
 func (i *RemoteFileInfo) Name() string { return i.name }
 
-// Size returns the length in bytes for the file represented by RemoteFileInfo. Always returns 0 for remote entries.
-func (i *RemoteFileInfo) Size() int64 { return 0 }
+// Size returns the length in bytes of the file as reported by the host.
+func (i *RemoteFileInfo) Size() int64 { return i.size }
 
-// Mode returns the file mode for the remote file or directory. Directories are identified with fs.ModeDir flag.
-func (i *RemoteFileInfo) Mode() fs.FileMode {
-	if i.isDir {
-		return fs.ModeDir | 0755
-	}
-	return 0644
-}
+// Mode returns the file mode bits as reported by the host.
+func (i *RemoteFileInfo) Mode() fs.FileMode { return i.mode }
 
-// ModTime returns the modification time of the file represented by RemoteFileInfo. It defaults to the zero
-// value of time.Time.
-func (i *RemoteFileInfo) ModTime() time.Time { return time.Time{} }
+// ModTime returns the modification time of the file as reported by the host.
+func (i *RemoteFileInfo) ModTime() time.Time { return i.modTime }
 
 // IsDir reports whether the file info describes a directory.
-func (i *RemoteFileInfo) IsDir() bool { return i.isDir }
+func (i *RemoteFileInfo) IsDir() bool { return i.mode.IsDir() }
 
 // Sys returns underlying data source (can be nil) for the RemoteFileInfo, typically used in os.FileInfo
 // implementations.
 func (i *RemoteFileInfo) Sys() interface{} { return nil }
 
-// HostServiceError represents an error returned by the host service.
-// Message is a description of the error.
-type HostServiceError struct {
-	Message string
+// dirEntryToProto converts a host directory entry to its protobuf form. If the entry's info cannot be read, for
+// example because the file was removed after the directory was listed, only the name and type are sent.
+func dirEntryToProto(entry fs.DirEntry) *hostservev1.DirEntry {
+	pb := &hostservev1.DirEntry{
+		Name:      entry.Name(),
+		IsDir:     entry.IsDir(),
+		Mode:      uint32(entry.Type()),
+		IsSymlink: entry.Type()&fs.ModeSymlink != 0,
+	}
+	if info, err := entry.Info(); err == nil {
+		pb.Size = info.Size()
+		pb.Mode = uint32(info.Mode())
+		pb.ModTime = timestamppb.New(info.ModTime())
+	}
+	return pb
+}
+
+// dirEntryFromProto converts a protobuf directory entry to a RemoteDirEntry.
+func dirEntryFromProto(pb *hostservev1.DirEntry) *RemoteDirEntry {
+	mode := fs.FileMode(pb.GetMode())
+	if pb.GetIsDir() {
+		mode |= fs.ModeDir
+	}
+	if pb.GetIsSymlink() {
+		mode |= fs.ModeSymlink
+	}
+	return &RemoteDirEntry{
+		info: &RemoteFileInfo{
+			name:    pb.GetName(),
+			size:    pb.GetSize(),
+			mode:    mode,
+			modTime: timeFromProto(pb.GetModTime()),
+		},
+	}
+}
+
+// fileInfoToProto converts host file info to its protobuf form.
+func fileInfoToProto(info fs.FileInfo) *hostservev1.FileInfo {
+	return &hostservev1.FileInfo{
+		Name:    info.Name(),
+		Size:    info.Size(),
+		Mode:    uint32(info.Mode()),
+		ModTime: timestamppb.New(info.ModTime()),
+		IsDir:   info.IsDir(),
+	}
 }
 
-// Error returns the error message stored in the HostServiceError as a string.
-func (e *HostServiceError) Error() string {
-	return e.Message
+// fileInfoFromProto converts protobuf file info to a RemoteFileInfo.
+func fileInfoFromProto(pb *hostservev1.FileInfo) *RemoteFileInfo {
+	mode := fs.FileMode(pb.GetMode())
+	if pb.GetIsDir() {
+		mode |= fs.ModeDir
+	}
+	return &RemoteFileInfo{
+		name:    pb.GetName(),
+		size:    pb.GetSize(),
+		mode:    mode,
+		modTime: timeFromProto(pb.GetModTime()),
+	}
+}
+
+// walkEntryToProto converts a walked entry to its protobuf form.
+func walkEntryToProto(e WalkEntry) *hostservev1.WalkEntry {
+	pb := &hostservev1.WalkEntry{
+		Path:  e.Path,
+		Depth: int32(e.Depth),
+	}
+	if e.Info != nil {
+		pb.Info = fileInfoToProto(e.Info)
+	}
+	if e.Err != nil {
+		pb.ErrorDetail = errorDetail(e.Err)
+	}
+	return pb
+}
+
+// walkEntryFromProto converts a protobuf walk entry to a WalkEntry.
+func walkEntryFromProto(pb *hostservev1.WalkEntry) WalkEntry {
+	e := WalkEntry{
+		Path:  pb.GetPath(),
+		Depth: int(pb.GetDepth()),
+	}
+	if pb.Info != nil {
+		e.Info = fileInfoFromProto(pb.Info)
+	}
+	if pb.ErrorDetail != nil {
+		e.Err = errorFromDetail(pb.ErrorDetail)
+	}
+	return e
+}
+
+// timeFromProto converts a protobuf timestamp to a time.Time, mapping a missing timestamp to the zero time.
+func timeFromProto(ts *timestamppb.Timestamp) time.Time {
+	if ts == nil {
+		return time.Time{}
+	}
+	return ts.AsTime()
+}
+
+// preconditionToProto converts a write precondition to its protobuf form, returning nil if it sets no conditions.
+func preconditionToProto(pre WritePrecondition) *hostservev1.WritePrecondition {
+	if pre.IsZero() {
+		return nil
+	}
+	pb := &hostservev1.WritePrecondition{Sha256: pre.SHA256, MustNotExist: pre.MustNotExist}
+	if !pre.ModTime.IsZero() {
+		pb.ModTime = timestamppb.New(pre.ModTime)
+	}
+	return pb
+}
+
+// preconditionFromProto converts a protobuf write precondition to a WritePrecondition, mapping a missing one to the
+// zero WritePrecondition.
+func preconditionFromProto(pb *hostservev1.WritePrecondition) WritePrecondition {
+	return WritePrecondition{
+		ModTime:      timeFromProto(pb.GetModTime()),
+		SHA256:       pb.GetSha256(),
+		MustNotExist: pb.GetMustNotExist(),
+	}
 }
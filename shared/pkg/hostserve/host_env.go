@@ -2,7 +2,9 @@ package hostserve
 
 import (
 	"context"
+	"maps"
 	"os"
+	"slices"
 )
 
 // HostEnv represents the environment configuration or context for a host system.
@@ -20,3 +22,45 @@ func NewHostEnv() *HostEnv {
 func (he *HostEnv) GetEnv(ctx context.Context, key string) string {
 	return os.Getenv(key)
 }
+
+// LookupEnv retrieves the value of the environment variable named by key, reporting whether it is set.
+func (he *HostEnv) LookupEnv(ctx context.Context, key string) (string, bool, error) {
+	val, found := os.LookupEnv(key)
+	return val, found, nil
+}
+
+// Environ returns the host process environment in "KEY=value" form.
+func (he *HostEnv) Environ(ctx context.Context) ([]string, error) {
+	return os.Environ(), nil
+}
+
+// MapEnv is a virtual environment backed by a fixed set of variables rather than the host process environment.
+// Hosts use it to give a plugin an environment that differs from their own.
+type MapEnv struct {
+	vars map[string]string
+}
+
+// NewMapEnv creates a MapEnv holding a copy of vars.
+func NewMapEnv(vars map[string]string) *MapEnv {
+	return &MapEnv{vars: maps.Clone(vars)}
+}
+
+// GetEnv retrieves the value of the variable named by key, or "" if it is not set.
+func (me *MapEnv) GetEnv(ctx context.Context, key string) string {
+	return me.vars[key]
+}
+
+// LookupEnv retrieves the value of the variable named by key, reporting whether it is set.
+func (me *MapEnv) LookupEnv(ctx context.Context, key string) (string, bool, error) {
+	val, found := me.vars[key]
+	return val, found, nil
+}
+
+// Environ returns the variables in "KEY=value" form, sorted by key.
+func (me *MapEnv) Environ(ctx context.Context) ([]string, error) {
+	vars := make([]string, 0, len(me.vars))
+	for _, key := range slices.Sorted(maps.Keys(me.vars)) {
+		vars = append(vars, key+"="+me.vars[key])
+	}
+	return vars, nil
+}
@@ -3,9 +3,14 @@ package hostserve
 import (
 	"context"
 	"errors"
+	"fmt"
+	"io"
 	"io/fs"
 	"os"
 	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
 
 	"github.com/hashicorp/go-hclog"
 )
@@ -13,16 +18,33 @@ import (
 const (
 	PermissionsMask     = fs.FileMode(0777)
 	StandardPermissions = fs.FileMode(0644)
+	DirPermissions      = fs.FileMode(0755)
 )
 
-// ErrInvalidPath represents an error indicating the provided path is invalid or not a directory.
+// ErrInvalidPath represents an error indicating the provided path is invalid, not a directory, or outside the
+// root the calling plugin is confined to.
 var (
 	ErrInvalidPath = errors.New("invalid path")
 )
 
 // HostFS is a file system abstraction that provides methods to interact with a host's file system.
+//
+// When a call's context carries a root (see WithRoot), every path is resolved relative to that root and paths
+// that would leave it are rejected with ErrInvalidPath. Calls without a root, which only the host itself can make,
+// open a root at the parent directory of each path instead.
+//
+// HostFS also tracks the transactions begun and the advisory locks held on it, and serializes conditional writes, so
+// a single instance should be shared by every plugin.
 type HostFS struct {
-	//TBD fields
+	// writeMu serializes checking a write's precondition with putting the write in place
+	writeMu sync.Mutex
+
+	txMu sync.Mutex
+	txs  map[string]*fsTx
+	// swept holds the root directories Sweep has swept, which are not swept again
+	swept map[string]struct{}
+
+	locks lockTable
 }
 
 // NewHostFS creates and returns a new instance of HostFS.
@@ -59,35 +81,117 @@ func closeRoot(r *os.Root) {
 	}
 }
 
+// confine converts path into a name relative to root. Absolute paths are accepted only if they lie within the
+// root, and relative paths may not use ".." to climb above it. The returned name is always local, with "."
+// naming the root itself.
+func confine(root *os.Root, path string) (string, error) {
+	name := path
+	if filepath.IsAbs(path) {
+		rel, err := filepath.Rel(root.Name(), path)
+		if err != nil {
+			return "", fmt.Errorf("%w: %s", ErrInvalidPath, path)
+		}
+		name = rel
+	}
+	name = filepath.Clean(name)
+	if name == ".." || strings.HasPrefix(name, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("%w: %s escapes root", ErrInvalidPath, path)
+	}
+	return name, nil
+}
+
+// internalName reports whether name is that of a file HostFS creates within a root for its own use, such as the
+// temporary file a write is made to before it is renamed into place, or a transaction's staging directory. Such
+// files are left out of listings, walks, globs and watch events, and CapabilityChecker refuses calls that name them.
+func internalName(name string) bool {
+	return strings.HasPrefix(name, tempPrefix) || strings.HasPrefix(name, TxDirPrefix)
+}
+
+// internalPath reports whether any element of path is an internal name.
+func internalPath(path string) bool {
+	return slices.ContainsFunc(strings.Split(filepath.ToSlash(path), "/"), internalName)
+}
+
+// resolve returns the root a call should operate on and the name of path within it. The release function must
+// be called once the root is no longer needed; it only closes roots that resolve opened itself.
+func resolve(ctx context.Context, path string) (*os.Root, string, func(), error) {
+	if r := RootFromContext(ctx); r != nil {
+		name, err := confine(r, path)
+		if err != nil {
+			hclog.Default().Warn("Rejected path outside root", "path", path, "root", r.Name())
+			return nil, "", nil, err
+		}
+		return r, name, func() {}, nil
+	}
+	dir, file := filepath.Split(path)
+	if file == "" {
+		file = "."
+	}
+	r, err := getRoot(dir)
+	if err != nil {
+		hclog.Default().Error("Failed to open root", "path", dir, "err", err)
+		return nil, "", nil, err
+	}
+	return r, file, func() { closeRoot(r) }, nil
+}
+
+// resolvePair resolves two paths that must be operated on within the same root, such as the source and destination
+// of a rename. Calls without a root in their context resolve both paths against the filesystem root.
+func resolvePair(ctx context.Context, a, b string) (*os.Root, string, string, func(), error) {
+	if r := RootFromContext(ctx); r != nil {
+		nameA, err := confine(r, a)
+		if err != nil {
+			return nil, "", "", nil, err
+		}
+		nameB, err := confine(r, b)
+		if err != nil {
+			return nil, "", "", nil, err
+		}
+		return r, nameA, nameB, func() {}, nil
+	}
+	absA, err := filepath.Abs(a)
+	if err != nil {
+		return nil, "", "", nil, err
+	}
+	absB, err := filepath.Abs(b)
+	if err != nil {
+		return nil, "", "", nil, err
+	}
+	r, err := os.OpenRoot(string(filepath.Separator))
+	if err != nil {
+		return nil, "", "", nil, err
+	}
+	nameA, _ := confine(r, absA)
+	nameB, _ := confine(r, absB)
+	return r, nameA, nameB, func() { closeRoot(r) }, nil
+}
+
 // ReadDir reads the contents of the specified directory path and returns a slice of directory entries or an error.
 func (hf *HostFS) ReadDir(ctx context.Context, path string) ([]fs.DirEntry, error) {
-	r, err := getRoot(path)
+	r, name, release, err := resolve(ctx, path)
 	if err != nil {
-		hclog.Default().Error("Failed to open root", "path", path, "err", err)
 		return nil, err
 	}
-	defer closeRoot(r)
-	entries, err := fs.ReadDir(r.FS(), ".")
+	defer release()
+	entries, err := fs.ReadDir(r.FS(), filepath.ToSlash(name))
 	if err != nil {
-		hclog.Default().Error("Failed to read directory", "path", path, "err", err)
+		logReadError("Failed to read directory", path, err)
 		return nil, err
 	}
 
-	return entries, nil
+	return slices.DeleteFunc(entries, func(e fs.DirEntry) bool { return internalName(e.Name()) }), nil
 }
 
 // ReadFile reads the specified file from the given directory and returns its contents as a byte slice or an error.
 func (hf *HostFS) ReadFile(ctx context.Context, path string) ([]byte, error) {
-	dir, file := filepath.Split(path)
-	r, err := getRoot(dir)
+	r, name, release, err := resolve(ctx, path)
 	if err != nil {
-		hclog.Default().Error("Failed to open root", "path", dir, "err", err)
 		return nil, err
 	}
-	defer closeRoot(r)
-	data, err := fs.ReadFile(r.FS(), file)
+	defer release()
+	data, err := r.ReadFile(name)
 	if err != nil {
-		hclog.Default().Error("Failed to read file", "path", path, "err", err)
+		logReadError("Failed to read file", path, err)
 		return nil, err
 	}
 	return data, nil
@@ -95,20 +199,155 @@ func (hf *HostFS) ReadFile(ctx context.Context, path string) ([]byte, error) {
 
 // WriteFile writes the specified data to a file within the given directory using the provided permissions.
 // If the provided permissions are zero, it defaults to StandardPermissions. Returns an error if the operation fails.
+// The file is written to a temporary file that is then renamed over it, so readers never see a partial write, and a
+// file being replaced keeps its permissions. Within a transaction (see WithTx) the write is staged until the
+// transaction is committed.
 func (hf *HostFS) WriteFile(ctx context.Context, path string, data []byte, perm os.FileMode) error {
+	return hf.WriteFileIf(ctx, path, data, perm, WritePrecondition{})
+}
+
+// WriteFileIf writes data to the specified file like WriteFile, provided the file satisfies pre when the new
+// contents are put in place. Otherwise nothing is written and a *ConflictError is returned. Within a transaction pre
+// is checked when the transaction is committed.
+func (hf *HostFS) WriteFileIf(ctx context.Context, path string, data []byte, perm os.FileMode,
+	pre WritePrecondition,
+) error {
 	if perm&PermissionsMask == 0 {
 		perm = StandardPermissions
 	}
-	dir, file := filepath.Split(path)
-	r, err := getRoot(dir)
+	if txID := TxFromContext(ctx); txID != "" {
+		return hf.writeTx(ctx, txID, path, data, perm, pre)
+	}
+	r, name, release, err := resolve(ctx, path)
 	if err != nil {
-		hclog.Default().Error("Failed to open root", "path", dir, "err", err)
 		return err
 	}
-	defer closeRoot(r)
-	err = r.WriteFile(file, data, perm)
+	defer release()
+	err = hf.writeAtomic(r, name, path, data, perm, pre)
 	if err != nil {
-		hclog.Default().Error("Failed to write file", "path", path, "err", err)
+		logWriteError("Failed to write file", path, err)
 	}
 	return err
 }
+
+// Stat returns the file info for the specified path, following symbolic links within the root.
+func (hf *HostFS) Stat(ctx context.Context, path string) (fs.FileInfo, error) {
+	r, name, release, err := resolve(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	info, err := r.Stat(name)
+	if err != nil {
+		logReadError("Failed to stat file", path, err)
+		return nil, err
+	}
+	return info, nil
+}
+
+// Lstat returns the file info for the specified path. If the path names a symbolic link, the info describes the
+// link itself.
+func (hf *HostFS) Lstat(ctx context.Context, path string) (fs.FileInfo, error) {
+	r, name, release, err := resolve(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	info, err := r.Lstat(name)
+	if err != nil {
+		logReadError("Failed to lstat file", path, err)
+		return nil, err
+	}
+	return info, nil
+}
+
+// rootFile is an open file that also holds on to the root it was opened from, so that both are released together.
+type rootFile struct {
+	*os.File
+	release func()
+}
+
+// Close closes the file and then releases the root it was opened from, returning the file's close error if any.
+func (rf *rootFile) Close() error {
+	err := rf.File.Close()
+	rf.release()
+	return err
+}
+
+// ReadDir reads the directory's entries like (*os.File).ReadDir, leaving out internal files (see internalName).
+func (rf *rootFile) ReadDir(n int) ([]fs.DirEntry, error) {
+	for {
+		entries, err := rf.File.ReadDir(n)
+		entries = slices.DeleteFunc(entries, func(e fs.DirEntry) bool { return internalName(e.Name()) })
+		// A batch of nothing but internal files is skipped, as a reader expects at least one entry or an error
+		if len(entries) > 0 || err != nil || n <= 0 {
+			return entries, err
+		}
+	}
+}
+
+// ReadFileStream opens the specified file for reading and returns it as an io.ReadCloser. Closing the reader also
+// releases the root the file was opened from.
+func (hf *HostFS) ReadFileStream(ctx context.Context, path string) (io.ReadCloser, error) {
+	r, name, release, err := resolve(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	f, err := r.Open(name)
+	if err != nil {
+		release()
+		logReadError("Failed to open file", path, err)
+		return nil, err
+	}
+	return &rootFile{File: f, release: release}, nil
+}
+
+// ReadDirStream opens the specified directory and returns it as a DirReader. Closing the reader also releases the
+// root the directory was opened from.
+func (hf *HostFS) ReadDirStream(ctx context.Context, path string) (DirReader, error) {
+	r, name, release, err := resolve(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	f, err := r.Open(name)
+	if err != nil {
+		release()
+		logReadError("Failed to open directory", path, err)
+		return nil, err
+	}
+	return &rootFile{File: f, release: release}, nil
+}
+
+// WriteFileStream returns an io.WriteCloser for new contents of the specified file. If the provided permissions are
+// zero, it defaults to StandardPermissions. The contents are written to a temporary file that replaces the file when
+// the writer is closed, which also releases the root the file was opened from. Within a transaction the file is
+// staged until the transaction is committed.
+func (hf *HostFS) WriteFileStream(ctx context.Context, path string, perm os.FileMode) (io.WriteCloser, error) {
+	if perm&PermissionsMask == 0 {
+		perm = StandardPermissions
+	}
+	if txID := TxFromContext(ctx); txID != "" {
+		return hf.openTx(ctx, txID, path, perm, WritePrecondition{})
+	}
+	r, name, release, err := resolve(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	af, err := hf.openAtomic(r, name, path, perm, WritePrecondition{}, release)
+	if err != nil {
+		release()
+		hclog.Default().Error("Failed to open file for writing", "path", path, "err", err)
+		return nil, err
+	}
+	return af, nil
+}
+
+// logReadError logs a failed read of path. A path that does not exist is logged at debug level, as callers such as
+// OverlayFS probe for paths that are expected to be missing and the miss is reported to the caller in any case.
+func logReadError(msg, path string, err error) {
+	if errors.Is(err, fs.ErrNotExist) {
+		hclog.Default().Debug(msg, "path", path, "err", err)
+		return
+	}
+	hclog.Default().Error(msg, "path", path, "err", err)
+}
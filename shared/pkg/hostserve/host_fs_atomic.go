@@ -0,0 +1,201 @@
+package hostserve
+
+import (
+	"crypto/rand"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"syscall"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+)
+
+// tempPrefix begins the name of the temporary file a write is made to before it is renamed over its target.
+const tempPrefix = ".hostserve-tmp-"
+
+// staleAfter is how long an internal file must have gone unmodified before Sweep takes it to be left over from a
+// write or transaction that never finished, rather than one still in progress in another process sharing the root.
+const staleAfter = time.Minute
+
+// aborter is implemented by writers that can be abandoned without their contents taking effect.
+type aborter interface {
+	abort()
+}
+
+// abortWriter abandons w, discarding what was written to it if it supports that and closing it otherwise.
+func abortWriter(w io.WriteCloser) {
+	if a, ok := w.(aborter); ok {
+		a.abort()
+		return
+	}
+	_ = w.Close()
+}
+
+// atomicFile is a temporary file that is renamed over its target when closed, so that readers of the target see
+// either its old contents or its new ones and never a partial write.
+type atomicFile struct {
+	*os.File
+	hf      *HostFS
+	root    *os.Root
+	tmp     string
+	name    string
+	path    string
+	pre     WritePrecondition
+	release func()
+}
+
+// Close closes the temporary file and renames it over the target, provided the target satisfies the write's
+// precondition, and then releases the root. The temporary file is removed if it cannot be put in place.
+func (af *atomicFile) Close() error {
+	defer af.release()
+	if err := af.File.Close(); err != nil {
+		_ = af.root.Remove(af.tmp)
+		return err
+	}
+	return af.hf.replace(af.root, af.tmp, af.name, af.path, af.pre)
+}
+
+// abort closes and removes the temporary file, leaving the target untouched, and releases the root.
+func (af *atomicFile) abort() {
+	_ = af.File.Close()
+	_ = af.root.Remove(af.tmp)
+	af.release()
+}
+
+// openAtomic creates a temporary file beside name, which is path relative to r, and returns it as an atomicFile
+// that replaces name when closed. A file being replaced keeps its permissions rather than taking perm.
+func (hf *HostFS) openAtomic(r *os.Root, name, path string, perm os.FileMode, pre WritePrecondition,
+	release func(),
+) (*atomicFile, error) {
+	info, statErr := r.Lstat(name)
+	if statErr == nil && info.IsDir() {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: syscall.EISDIR}
+	}
+	tmp := filepath.Join(filepath.Dir(name), tempPrefix+rand.Text()[:10]+"-"+filepath.Base(name))
+	f, err := r.OpenFile(tmp, os.O_WRONLY|os.O_CREATE|os.O_EXCL, perm)
+	if err != nil {
+		return nil, err
+	}
+	if statErr == nil && info.Mode().IsRegular() {
+		if err := f.Chmod(info.Mode().Perm()); err != nil {
+			_ = f.Close()
+			_ = r.Remove(tmp)
+			return nil, err
+		}
+	}
+	return &atomicFile{File: f, hf: hf, root: r, tmp: tmp, name: name, path: path, pre: pre, release: release}, nil
+}
+
+// writeAtomic writes data to name, which is path relative to r, by way of a temporary file renamed over it. A
+// symbolic link is written through in place instead, since renaming over it would replace the link itself.
+func (hf *HostFS) writeAtomic(r *os.Root, name, path string, data []byte, perm os.FileMode,
+	pre WritePrecondition,
+) error {
+	if info, err := r.Lstat(name); err == nil && info.Mode()&fs.ModeSymlink != 0 {
+		hf.writeMu.Lock()
+		defer hf.writeMu.Unlock()
+		if err := checkRoot(r, name, path, pre); err != nil {
+			return err
+		}
+		return r.WriteFile(name, data, perm)
+	}
+	af, err := hf.openAtomic(r, name, path, perm, pre, func() {})
+	if err != nil {
+		return err
+	}
+	if _, err := af.Write(data); err != nil {
+		af.abort()
+		return err
+	}
+	return af.Close()
+}
+
+// replace renames tmp over name, provided name satisfies pre, removing tmp if it is not put in place. Checking the
+// precondition and renaming happen under the HostFS write lock, so no other write through the HostFS can come
+// between them.
+func (hf *HostFS) replace(r *os.Root, tmp, name, path string, pre WritePrecondition) error {
+	hf.writeMu.Lock()
+	defer hf.writeMu.Unlock()
+	err := checkRoot(r, name, path, pre)
+	if err == nil {
+		err = r.Rename(tmp, name)
+	}
+	if err != nil {
+		_ = r.Remove(tmp)
+	}
+	return err
+}
+
+// checkRoot returns a *ConflictError if the file at name, which is path relative to r, does not satisfy pre.
+func checkRoot(r *os.Root, name, path string, pre WritePrecondition) error {
+	return pre.check(path,
+		func() (fs.FileInfo, error) { return r.Stat(name) },
+		func() (io.ReadCloser, error) { return r.Open(name) },
+	)
+}
+
+// Sweeper is implemented by filesystems that leave internal files in the roots they are used in, so that a host can
+// have those left behind by a previous run removed as it starts.
+type Sweeper interface {
+	// Sweep removes the internal files left in dir by writes and transactions that never finished.
+	Sweep(dir string) error
+}
+
+// Sweep removes the internal files left throughout dir by writes and transactions that never finished, such as
+// those in progress when the host stopped. Hosts call it once for each plugin root as they start, before any plugin
+// connects; each directory is only swept the first time, so plugins sharing a root do not sweep it again. Files
+// modified within staleAfter and the staging directories of transactions still open on hf are kept.
+func (hf *HostFS) Sweep(dir string) error {
+	r, err := getRoot(dir)
+	if err != nil {
+		return err
+	}
+	defer closeRoot(r)
+	hf.txMu.Lock()
+	if hf.swept == nil {
+		hf.swept = make(map[string]struct{})
+	}
+	_, done := hf.swept[r.Name()]
+	hf.swept[r.Name()] = struct{}{}
+	hf.txMu.Unlock()
+	if !done {
+		hf.sweepRoot(r)
+	}
+	return nil
+}
+
+// sweepRoot removes the stale internal files throughout r, as described by Sweep.
+func (hf *HostFS) sweepRoot(r *os.Root) {
+	_ = fs.WalkDir(r.FS(), ".", func(p string, d fs.DirEntry, err error) error {
+		if err != nil || p == "." || !internalName(d.Name()) {
+			return nil
+		}
+		if hf.openTxDir(d.Name()) {
+			return fs.SkipDir
+		}
+		if info, err := d.Info(); err == nil && time.Since(info.ModTime()) > staleAfter {
+			if err := r.RemoveAll(filepath.FromSlash(p)); err != nil {
+				hclog.Default().Warn("Failed to remove stale internal file", "path", p, "root", r.Name(), "err", err)
+			} else {
+				hclog.Default().Info("Removed stale internal file", "path", p, "root", r.Name())
+			}
+		}
+		if d.IsDir() {
+			return fs.SkipDir
+		}
+		return nil
+	})
+}
+
+// logWriteError logs a failed write of path. Refusals by a precondition are an expected outcome of optimistic
+// concurrency and are logged at a lower level than other failures.
+func logWriteError(msg, path string, err error) {
+	if errors.Is(err, ErrConflict) {
+		hclog.Default().Info("Write refused by precondition", "path", path, "err", err)
+		return
+	}
+	hclog.Default().Error(msg, "path", path, "err", err)
+}
@@ -0,0 +1,117 @@
+package hostserve
+
+import (
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestHostFSHidesTempFiles(t *testing.T) {
+	ctx, dir := rootContext(t)
+	files := []string{"a.txt", tempPrefix + "0123456789-a.txt", "sub/b.txt", "sub/" + tempPrefix + "x-b.txt"}
+	for _, name := range files {
+		p := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, nil, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	hf := NewHostFS()
+
+	entries, err := hf.ReadDir(ctx, ".")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, e := range entries {
+		names = append(names, e.Name())
+	}
+	if want := []string{"a.txt", "sub"}; !slices.Equal(names, want) {
+		t.Errorf("ReadDir = %v, want %v", names, want)
+	}
+
+	dr, err := hf.ReadDirStream(ctx, "sub")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = dr.Close() }()
+	names = nil
+	for {
+		batch, err := dr.ReadDir(1)
+		for _, e := range batch {
+			names = append(names, e.Name())
+		}
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	if want := []string{"b.txt"}; !slices.Equal(names, want) {
+		t.Errorf("ReadDirStream = %v, want %v", names, want)
+	}
+
+	walk, err := hf.Walk(ctx, ".", WalkOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var walked []string
+	for e := range walk {
+		walked = append(walked, e.Path)
+	}
+	if want := []string{".", "a.txt", "sub", filepath.Join("sub", "b.txt")}; !slices.Equal(walked, want) {
+		t.Errorf("Walk = %v, want %v", walked, want)
+	}
+
+	matches, err := hf.Glob(ctx, "**/*.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	slices.Sort(matches)
+	if want := []string{"a.txt", filepath.Join("sub", "b.txt")}; !slices.Equal(matches, want) {
+		t.Errorf("Glob = %v, want %v", matches, want)
+	}
+}
+
+func TestSweepRootRemovesStaleTempFiles(t *testing.T) {
+	_, dir := rootContext(t)
+	stale := filepath.Join(dir, "sub", tempPrefix+"stale-a.txt")
+	fresh := filepath.Join(dir, "sub", tempPrefix+"fresh-a.txt")
+	kept := filepath.Join(dir, "sub", "a.txt")
+	if err := os.Mkdir(filepath.Dir(stale), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	for _, p := range []string{stale, fresh, kept} {
+		if err := os.WriteFile(p, nil, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	old := time.Now().Add(-2 * staleAfter)
+	if err := os.Chtimes(stale, old, old); err != nil {
+		t.Fatal(err)
+	}
+	// The kept file is as old as the stale one, but is not an internal file
+	if err := os.Chtimes(kept, old, old); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := NewHostFS().Sweep(dir); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(stale); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("stale temporary file was not removed: %v", err)
+	}
+	for _, p := range []string{fresh, kept} {
+		if _, err := os.Stat(p); err != nil {
+			t.Errorf("%s was removed: %v", filepath.Base(p), err)
+		}
+	}
+}
@@ -0,0 +1,68 @@
+package hostserve
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+)
+
+// Lock acquires an advisory lock on path in the given mode, waiting until no conflicting lock is held or ctx is
+// done, and returns its ID and expiry. The lock is released by Unlock, or once lease has passed; a zero lease
+// defaults to DefaultLockLease, and leases are cut to the maximum set by SetMaxLockLease. Locks are keyed by the
+// absolute path they name, so plugins confined to different roots contend for the same file, and the path need not
+// exist.
+func (hf *HostFS) Lock(ctx context.Context, path string, mode LockMode,
+	lease time.Duration,
+) (string, time.Time, error) {
+	return hf.lock(ctx, path, mode, lease, true)
+}
+
+// TryLock acquires an advisory lock on path like Lock, but fails with ErrLocked instead of waiting if a conflicting
+// lock is held.
+func (hf *HostFS) TryLock(ctx context.Context, path string, mode LockMode,
+	lease time.Duration,
+) (string, time.Time, error) {
+	return hf.lock(ctx, path, mode, lease, false)
+}
+
+// SetMaxLockLease sets the longest lease a lock is given, so that a plugin cannot hold a lock indefinitely. Longer
+// leases are cut to max, which the expiry returned by Lock reflects. A max of zero restores DefaultMaxLockLease.
+func (hf *HostFS) SetMaxLockLease(max time.Duration) {
+	hf.locks.setMaxLease(max)
+}
+
+// Unlock releases the lock lockID, provided it belongs to the caller and its lease has not run out.
+func (hf *HostFS) Unlock(ctx context.Context, lockID string) error {
+	return hf.locks.release(lockID, ClientIDFromContext(ctx))
+}
+
+// lock acquires a lock on path, waiting for conflicting locks to be released if wait is set.
+func (hf *HostFS) lock(ctx context.Context, path string, mode LockMode, lease time.Duration,
+	wait bool,
+) (string, time.Time, error) {
+	key, err := lockKey(ctx, path)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	id, expires, err := hf.locks.acquire(ctx, key, path, ClientIDFromContext(ctx), mode, lease, wait)
+	if err != nil && !errors.Is(err, ErrLocked) && ctx.Err() == nil {
+		hclog.Default().Error("Failed to acquire lock", "path", path, "mode", mode, "err", err)
+	}
+	return id, expires, err
+}
+
+// lockKey returns the absolute path a lock on path is keyed by, confining path to the root carried by ctx if any.
+func lockKey(ctx context.Context, path string) (string, error) {
+	r := RootFromContext(ctx)
+	if r == nil {
+		return filepath.Abs(path)
+	}
+	name, err := confine(r, path)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(r.Name(), name), nil
+}
@@ -0,0 +1,163 @@
+package hostserve
+
+import (
+	"context"
+	"io"
+	"os"
+
+	"github.com/hashicorp/go-hclog"
+)
+
+// MkdirAll creates the directory at path along with any missing parents. If the provided permissions are zero,
+// it defaults to DirPermissions.
+func (hf *HostFS) MkdirAll(ctx context.Context, path string, perm os.FileMode) error {
+	if perm&PermissionsMask == 0 {
+		perm = DirPermissions
+	}
+	r, name, release, err := resolve(ctx, path)
+	if err != nil {
+		return err
+	}
+	defer release()
+	if err := r.MkdirAll(name, perm); err != nil {
+		hclog.Default().Error("Failed to create directory", "path", path, "err", err)
+		return err
+	}
+	return nil
+}
+
+// Remove removes the file or empty directory at path.
+func (hf *HostFS) Remove(ctx context.Context, path string) error {
+	r, name, release, err := resolve(ctx, path)
+	if err != nil {
+		return err
+	}
+	defer release()
+	if err := r.Remove(name); err != nil {
+		hclog.Default().Error("Failed to remove file", "path", path, "err", err)
+		return err
+	}
+	return nil
+}
+
+// RemoveAll removes path and any children it contains. It refuses to remove the root itself.
+func (hf *HostFS) RemoveAll(ctx context.Context, path string) error {
+	r, name, release, err := resolve(ctx, path)
+	if err != nil {
+		return err
+	}
+	defer release()
+	if name == "." {
+		return ErrInvalidPath
+	}
+	if err := r.RemoveAll(name); err != nil {
+		hclog.Default().Error("Failed to remove path", "path", path, "err", err)
+		return err
+	}
+	return nil
+}
+
+// Rename moves oldPath to newPath. Both paths must lie within the same root.
+func (hf *HostFS) Rename(ctx context.Context, oldPath, newPath string) error {
+	r, oldName, newName, release, err := resolvePair(ctx, oldPath, newPath)
+	if err != nil {
+		return err
+	}
+	defer release()
+	if err := r.Rename(oldName, newName); err != nil {
+		hclog.Default().Error("Failed to rename file", "old", oldPath, "new", newPath, "err", err)
+		return err
+	}
+	return nil
+}
+
+// Copy copies the contents of the file at src to dst, creating or truncating dst with the permissions of src.
+// It returns the number of bytes copied. Within a transaction the write of dst is staged.
+func (hf *HostFS) Copy(ctx context.Context, src, dst string) (int64, error) {
+	in, err := hf.ReadFileStream(ctx, src)
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = in.Close() }()
+
+	perm := StandardPermissions
+	if f, ok := in.(*rootFile); ok {
+		if info, err := f.Stat(); err == nil {
+			perm = info.Mode().Perm()
+		}
+	}
+	out, err := hf.WriteFileStream(ctx, dst, perm)
+	if err != nil {
+		return 0, err
+	}
+	n, err := io.Copy(out, in)
+	if err != nil {
+		abortWriter(out)
+		hclog.Default().Error("Failed to copy file", "src", src, "dst", dst, "err", err)
+		return n, err
+	}
+	return n, out.Close()
+}
+
+// Chmod changes the permissions of the file at path. Only the permission bits of mode are applied, so a plugin cannot
+// set the setuid, setgid or sticky bits on host files.
+func (hf *HostFS) Chmod(ctx context.Context, path string, mode os.FileMode) error {
+	r, name, release, err := resolve(ctx, path)
+	if err != nil {
+		return err
+	}
+	defer release()
+	if err := r.Chmod(name, mode.Perm()); err != nil {
+		hclog.Default().Error("Failed to change file mode", "path", path, "err", err)
+		return err
+	}
+	return nil
+}
+
+// Truncate changes the size of the file at path, extending it with zero bytes if size is larger than the file.
+func (hf *HostFS) Truncate(ctx context.Context, path string, size int64) error {
+	r, name, release, err := resolve(ctx, path)
+	if err != nil {
+		return err
+	}
+	defer release()
+	f, err := r.OpenFile(name, os.O_WRONLY, 0)
+	if err != nil {
+		hclog.Default().Error("Failed to open file for truncation", "path", path, "err", err)
+		return err
+	}
+	err = f.Truncate(size)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		hclog.Default().Error("Failed to truncate file", "path", path, "err", err)
+	}
+	return err
+}
+
+// Append appends data to the file at path, creating it if it does not exist. If the provided permissions are zero,
+// it defaults to StandardPermissions.
+func (hf *HostFS) Append(ctx context.Context, path string, data []byte, perm os.FileMode) error {
+	if perm&PermissionsMask == 0 {
+		perm = StandardPermissions
+	}
+	r, name, release, err := resolve(ctx, path)
+	if err != nil {
+		return err
+	}
+	defer release()
+	f, err := r.OpenFile(name, os.O_WRONLY|os.O_CREATE|os.O_APPEND, perm)
+	if err != nil {
+		hclog.Default().Error("Failed to open file for appending", "path", path, "err", err)
+		return err
+	}
+	_, err = f.Write(data)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		hclog.Default().Error("Failed to append to file", "path", path, "err", err)
+	}
+	return err
+}
@@ -0,0 +1,25 @@
+package hostserve
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestHostFSChmodAppliesOnlyPermissionBits(t *testing.T) {
+	ctx, dir := rootContext(t)
+	hf := NewHostFS()
+	if err := hf.WriteFile(ctx, "tool", []byte("#!/bin/sh\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := hf.Chmod(ctx, "tool", 0o755|os.ModeSetuid|os.ModeSetgid|os.ModeSticky); err != nil {
+		t.Fatal(err)
+	}
+	info, err := os.Stat(filepath.Join(dir, "tool"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := info.Mode(); got != 0o755 {
+		t.Errorf("mode = %v, want %v", got, os.FileMode(0o755))
+	}
+}
@@ -0,0 +1,140 @@
+package hostserve
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// rootContext returns a context confining host service calls to a new temporary directory, along with the directory.
+func rootContext(t *testing.T) (context.Context, string) {
+	t.Helper()
+	dir := t.TempDir()
+	root, err := os.OpenRoot(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = root.Close() })
+	return WithClientID(WithRoot(context.Background(), root), "test"), dir
+}
+
+func TestHostFSConfinesPathsToRoot(t *testing.T) {
+	ctx, dir := rootContext(t)
+	outside := t.TempDir()
+	secret := filepath.Join(outside, "secret.txt")
+	if err := os.WriteFile(secret, []byte("secret"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Mkdir(filepath.Join(dir, "sub"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(outside, filepath.Join(dir, "dirlink")); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(secret, filepath.Join(dir, "filelink")); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink("../../"+filepath.Base(outside), filepath.Join(dir, "sub", "rellink")); err != nil {
+		t.Fatal(err)
+	}
+	rel, err := filepath.Rel(dir, secret)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		path string
+		// invalid is set for paths rejected by name, before the filesystem is consulted
+		invalid bool
+	}{
+		{name: "parent", path: rel, invalid: true},
+		{name: "parent after subdirectory", path: filepath.Join("sub", "..", rel), invalid: true},
+		{name: "absolute outside root", path: secret, invalid: true},
+		{name: "symlink to directory outside", path: filepath.Join("dirlink", "secret.txt")},
+		{name: "symlink to file outside", path: "filelink"},
+		{name: "relative symlink climbing out", path: filepath.Join("sub", "rellink", "secret.txt")},
+	}
+	hf := NewHostFS()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			check := func(op string, err error) {
+				t.Helper()
+				if err == nil {
+					t.Errorf("%s(%q) succeeded, want it confined to the root", op, tt.path)
+				} else if tt.invalid && !errors.Is(err, ErrInvalidPath) {
+					t.Errorf("%s(%q) = %v, want ErrInvalidPath", op, tt.path, err)
+				}
+			}
+			_, err := hf.ReadFile(ctx, tt.path)
+			check("ReadFile", err)
+			_, err = hf.Stat(ctx, tt.path)
+			check("Stat", err)
+			check("WriteFile", hf.WriteFile(ctx, tt.path, []byte("overwritten"), 0))
+			check("Append", hf.Append(ctx, tt.path, []byte("appended"), 0))
+			check("Chmod", hf.Chmod(ctx, tt.path, 0o777))
+		})
+	}
+
+	data, err := os.ReadFile(secret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(data, []byte("secret")) {
+		t.Errorf("file outside root was changed to %q", data)
+	}
+	if info, err := os.Stat(secret); err != nil || info.Mode().Perm() != 0o644 {
+		t.Errorf("file outside root has mode %v, err %v", info.Mode(), err)
+	}
+}
+
+func TestHostFSAllowsPathsWithinRoot(t *testing.T) {
+	ctx, dir := rootContext(t)
+	if err := os.Mkdir(filepath.Join(dir, "sub"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "file.txt"), []byte("inside"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink("../file.txt", filepath.Join(dir, "sub", "link")); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		path string
+	}{
+		{name: "relative", path: "file.txt"},
+		{name: "parent within root", path: filepath.Join("sub", "..", "file.txt")},
+		{name: "absolute within root", path: filepath.Join(dir, "file.txt")},
+		{name: "symlink within root", path: filepath.Join("sub", "link")},
+	}
+	hf := NewHostFS()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := hf.ReadFile(ctx, tt.path)
+			if err != nil {
+				t.Fatalf("ReadFile(%q) = %v", tt.path, err)
+			}
+			if string(data) != "inside" {
+				t.Errorf("ReadFile(%q) = %q, want %q", tt.path, data, "inside")
+			}
+		})
+	}
+}
+
+func TestHostFSRemoveAllRefusesRoot(t *testing.T) {
+	ctx, dir := rootContext(t)
+	hf := NewHostFS()
+	for _, path := range []string{".", dir, "sub/.."} {
+		if err := hf.RemoveAll(ctx, path); !errors.Is(err, ErrInvalidPath) {
+			t.Errorf("RemoveAll(%q) = %v, want ErrInvalidPath", path, err)
+		}
+	}
+	if _, err := os.Stat(dir); err != nil {
+		t.Errorf("root was removed: %v", err)
+	}
+}
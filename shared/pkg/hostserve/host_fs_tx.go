@@ -0,0 +1,327 @@
+package hostserve
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+
+	"github.com/google/uuid"
+	"github.com/hashicorp/go-hclog"
+)
+
+// TxDirPrefix begins the name of the directory, at the top of a plugin's root, in which a transaction's writes are
+// staged. Staging within the root keeps each staged file on the same filesystem as its target, so that it can be
+// renamed into place atomically. Staging directories are internal files (see internalName): plugins cannot see or
+// name them, and those left behind by a host that stopped are removed when the host next sweeps the root.
+const TxDirPrefix = ".hostserve-tx-"
+
+// fsTx is an open HostFS transaction.
+type fsTx struct {
+	root   *os.Root
+	client string
+	dir    string
+
+	// mu guards the fields below, and is held throughout a commit or rollback so that no write is staged during one
+	mu sync.Mutex
+	// staged maps the name of each file written in the transaction, relative to the root, to its staged write.
+	staged map[string]*stagedWrite
+	// files counts the files created in the staging directory, giving each a name of its own
+	files int
+	// ended is set once the transaction has been committed or rolled back, after which nothing more can be staged
+	ended bool
+}
+
+// stagedWrite is a write staged in an fsTx: the staged file holding the new contents, and the path and precondition
+// the write was requested with.
+type stagedWrite struct {
+	file string
+	path string
+	pre  WritePrecondition
+}
+
+// stagingFile is the writer for a write being staged in an fsTx. The contents are written to a file of their own
+// that only becomes the staged write for its path once closed, so a commit never sees a write still in progress.
+type stagingFile struct {
+	*os.File
+	tx    *fsTx
+	write stagedWrite
+	name  string
+}
+
+// Close closes the file and stages it as the write of its path, replacing any earlier write of the same path. It
+// returns ErrTxNotFound if the transaction ended while the file was open, in which case nothing is staged.
+func (sf *stagingFile) Close() error {
+	if err := sf.File.Close(); err != nil {
+		_ = sf.tx.root.Remove(sf.write.file)
+		return err
+	}
+	return sf.tx.stage(sf.name, sf.write)
+}
+
+// abort closes and removes the file without staging it.
+func (sf *stagingFile) abort() {
+	_ = sf.File.Close()
+	_ = sf.tx.root.Remove(sf.write.file)
+}
+
+// BeginTx starts a transaction confined to the root carried by ctx, creating the directory its writes are staged
+// in. Transactions are only available to calls confined to a root, and belong to the plugin that began them.
+func (hf *HostFS) BeginTx(ctx context.Context) (string, error) {
+	r := RootFromContext(ctx)
+	if r == nil {
+		return "", fmt.Errorf("%w: transactions require a root", ErrInvalidPath)
+	}
+	id, err := uuid.NewV7()
+	if err != nil {
+		return "", err
+	}
+	tx := &fsTx{
+		root:   r,
+		client: ClientIDFromContext(ctx),
+		dir:    TxDirPrefix + id.String(),
+		staged: make(map[string]*stagedWrite),
+	}
+	if err := r.Mkdir(tx.dir, 0700); err != nil {
+		hclog.Default().Error("Failed to create transaction directory", "path", tx.dir, "err", err)
+		return "", err
+	}
+	hf.txMu.Lock()
+	defer hf.txMu.Unlock()
+	if hf.txs == nil {
+		hf.txs = make(map[string]*fsTx)
+	}
+	hf.txs[id.String()] = tx
+	return id.String(), nil
+}
+
+// CommitTx renames every file staged in the transaction over its target and removes the staging directory. Every
+// target, and every precondition the writes were staged with, is checked before any file is moved, so a commit that
+// fails those checks changes nothing. The files being replaced are kept until every rename has succeeded, and are
+// put back if one fails. Only if putting them back fails too is the commit left partial, and the error then lists
+// the paths that remain committed. Writers staging into the transaction that are still open when it is committed
+// are left out of it, and fail when closed. The transaction is ended whether or not the commit succeeds.
+func (hf *HostFS) CommitTx(ctx context.Context, txID string) error {
+	tx, err := hf.lookupTx(ctx, txID, true)
+	if err != nil {
+		return err
+	}
+	tx.mu.Lock()
+	defer tx.mu.Unlock()
+	defer tx.discard()
+	hf.writeMu.Lock()
+	defer hf.writeMu.Unlock()
+
+	names := slices.Sorted(maps.Keys(tx.staged))
+	for _, name := range names {
+		err := tx.check(name)
+		if err == nil {
+			err = checkRoot(tx.root, name, tx.staged[name].path, tx.staged[name].pre)
+		}
+		if err != nil {
+			logWriteError("Failed to commit transaction", name, err)
+			return err
+		}
+	}
+	backups, err := tx.backup(names)
+	if err != nil {
+		hclog.Default().Error("Failed to back up files replaced by transaction", "tx", txID, "err", err)
+		return err
+	}
+	for i, name := range names {
+		if err := tx.root.Rename(tx.staged[name].file, name); err != nil {
+			hclog.Default().Error("Failed to commit transaction", "tx", txID, "path", name, "err", err)
+			return tx.restore(names[:i], backups, err)
+		}
+	}
+	return nil
+}
+
+// RollbackTx removes the transaction's staging directory along with every write staged in it. Writers staging into
+// the transaction that are still open fail when closed.
+func (hf *HostFS) RollbackTx(ctx context.Context, txID string) error {
+	tx, err := hf.lookupTx(ctx, txID, true)
+	if err != nil {
+		return err
+	}
+	tx.mu.Lock()
+	defer tx.mu.Unlock()
+	tx.discard()
+	return nil
+}
+
+// openTxDir reports whether name is the staging directory of a transaction still open on hf.
+func (hf *HostFS) openTxDir(name string) bool {
+	id, ok := strings.CutPrefix(name, TxDirPrefix)
+	if !ok {
+		return false
+	}
+	hf.txMu.Lock()
+	defer hf.txMu.Unlock()
+	_, open := hf.txs[id]
+	return open
+}
+
+// lookupTx returns the open transaction txID, provided it belongs to the caller. If end is set the transaction is
+// also removed from the open transactions.
+func (hf *HostFS) lookupTx(ctx context.Context, txID string, end bool) (*fsTx, error) {
+	hf.txMu.Lock()
+	defer hf.txMu.Unlock()
+	tx, ok := hf.txs[txID]
+	if !ok || tx.root != RootFromContext(ctx) || tx.client != ClientIDFromContext(ctx) {
+		return nil, fmt.Errorf("%w: %s", ErrTxNotFound, txID)
+	}
+	if end {
+		delete(hf.txs, txID)
+	}
+	return tx, nil
+}
+
+// writeTx stages a write of data to path, conditional on pre, in the transaction txID.
+func (hf *HostFS) writeTx(ctx context.Context, txID, path string, data []byte, perm os.FileMode,
+	pre WritePrecondition,
+) error {
+	f, err := hf.openTx(ctx, txID, path, perm, pre)
+	if err != nil {
+		return err
+	}
+	if _, err = f.Write(data); err != nil {
+		abortWriter(f)
+	} else {
+		err = f.Close()
+	}
+	if err != nil {
+		hclog.Default().Error("Failed to stage file", "path", path, "tx", txID, "err", err)
+	}
+	return err
+}
+
+// openTx opens a writer staging a write of path, conditional on pre, in the transaction txID. Once closed, the write
+// replaces any earlier write of the same path along with its precondition.
+func (hf *HostFS) openTx(ctx context.Context, txID, path string, perm os.FileMode,
+	pre WritePrecondition,
+) (io.WriteCloser, error) {
+	tx, err := hf.lookupTx(ctx, txID, false)
+	if err != nil {
+		return nil, err
+	}
+	name, err := confine(tx.root, path)
+	if err != nil {
+		return nil, err
+	}
+	if name == "." {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidPath, path)
+	}
+	tx.mu.Lock()
+	if tx.ended {
+		tx.mu.Unlock()
+		return nil, fmt.Errorf("%w: %s", ErrTxNotFound, txID)
+	}
+	file := filepath.Join(tx.dir, strconv.Itoa(tx.files))
+	tx.files++
+	tx.mu.Unlock()
+
+	f, err := tx.root.OpenFile(file, os.O_WRONLY|os.O_CREATE|os.O_EXCL, perm)
+	if err != nil {
+		hclog.Default().Error("Failed to open staged file", "path", path, "tx", txID, "err", err)
+		return nil, err
+	}
+	return &stagingFile{File: f, tx: tx, write: stagedWrite{file: file, path: path, pre: pre}, name: name}, nil
+}
+
+// stage records w as the write of name, replacing and removing any earlier write of it. It returns ErrTxNotFound,
+// and removes w's file, if the transaction has ended.
+func (tx *fsTx) stage(name string, w stagedWrite) error {
+	tx.mu.Lock()
+	defer tx.mu.Unlock()
+	if tx.ended {
+		_ = tx.root.Remove(w.file)
+		return fmt.Errorf("%w: ended before %s was staged", ErrTxNotFound, w.path)
+	}
+	if prev, ok := tx.staged[name]; ok {
+		_ = tx.root.Remove(prev.file)
+	}
+	tx.staged[name] = &w
+	return nil
+}
+
+// backup keeps a hard link to each of the named files that exists in the staging directory, so that it survives
+// being renamed over. It returns the backups by the name of the file they keep, with an empty backup for a file that
+// could not be linked, such as one on a filesystem without hard links.
+func (tx *fsTx) backup(names []string) (map[string]string, error) {
+	backups := make(map[string]string)
+	for i, name := range names {
+		if _, err := tx.root.Lstat(name); errors.Is(err, fs.ErrNotExist) {
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+		backup := filepath.Join(tx.dir, "backup-"+strconv.Itoa(i))
+		if err := tx.root.Link(name, backup); err != nil {
+			hclog.Default().Warn("Failed to back up file replaced by transaction", "path", name, "err", err)
+			backup = ""
+		}
+		backups[name] = backup
+	}
+	return backups, nil
+}
+
+// restore undoes the renames of the committed names after a commit failed with err, putting back the files they
+// replaced from backups and removing those they created. It returns err, extended with the paths that could not be
+// restored if there are any.
+func (tx *fsTx) restore(committed []string, backups map[string]string, err error) error {
+	var left []string
+	for _, name := range slices.Backward(committed) {
+		backup, replaced := backups[name]
+		var restoreErr error
+		switch {
+		case !replaced:
+			restoreErr = tx.root.Remove(name)
+		case backup != "":
+			restoreErr = tx.root.Rename(backup, name)
+		default:
+			restoreErr = errors.New("no backup of the replaced file")
+		}
+		if restoreErr != nil {
+			hclog.Default().Error("Failed to restore file after failed commit", "path", name, "err", restoreErr)
+			left = append(left, tx.staged[name].path)
+		}
+	}
+	if len(left) > 0 {
+		slices.Sort(left)
+		return fmt.Errorf("%w; commit left partial, with %s committed", err, strings.Join(left, ", "))
+	}
+	return err
+}
+
+// check returns an error if the staged file for name could not be renamed into place.
+func (tx *fsTx) check(name string) error {
+	info, err := tx.root.Stat(filepath.Dir(name))
+	if err != nil {
+		return err
+	}
+	if !info.IsDir() {
+		return &fs.PathError{Op: "commit", Path: filepath.Dir(name), Err: syscall.ENOTDIR}
+	}
+	if info, err := tx.root.Lstat(name); err == nil && info.IsDir() {
+		return &fs.PathError{Op: "commit", Path: name, Err: syscall.EISDIR}
+	}
+	return nil
+}
+
+// discard ends the transaction and removes its staging directory. It must be called with mu held.
+func (tx *fsTx) discard() {
+	tx.ended = true
+	if err := tx.root.RemoveAll(tx.dir); err != nil {
+		hclog.Default().Error("Failed to remove transaction directory", "path", tx.dir, "err", err)
+	}
+}
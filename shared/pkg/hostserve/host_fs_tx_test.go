@@ -0,0 +1,209 @@
+package hostserve
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// readString returns the contents of the file at name within dir, or the error reading it.
+func readString(dir, name string) (string, error) {
+	data, err := os.ReadFile(filepath.Join(dir, name))
+	return string(data), err
+}
+
+func TestHostFSTxCommit(t *testing.T) {
+	ctx, dir := rootContext(t)
+	hf := NewHostFS()
+	if err := os.WriteFile(filepath.Join(dir, "a.txt"), []byte("old"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	txID, err := hf.BeginTx(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	txCtx := WithTx(ctx, txID)
+	for _, name := range []string{"a.txt", "b.txt"} {
+		if err := hf.WriteFile(txCtx, name, []byte("new "+name), 0); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	entries, err := hf.ReadDir(ctx, ".")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, e := range entries {
+		if strings.HasPrefix(e.Name(), TxDirPrefix) {
+			t.Errorf("ReadDir listed the staging directory %s", e.Name())
+		}
+	}
+	if got, _ := readString(dir, "a.txt"); got != "old" {
+		t.Errorf("a.txt = %q before commit, want %q", got, "old")
+	}
+
+	if err := hf.CommitTx(ctx, txID); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"a.txt", "b.txt"} {
+		if got, err := readString(dir, name); got != "new "+name {
+			t.Errorf("%s = %q, %v after commit, want %q", name, got, err, "new "+name)
+		}
+	}
+	if staging, _ := filepath.Glob(filepath.Join(dir, TxDirPrefix+"*")); len(staging) > 0 {
+		t.Errorf("staging directories left after commit: %v", staging)
+	}
+}
+
+func TestHostFSTxCommitRestoresOnFailure(t *testing.T) {
+	ctx, dir := rootContext(t)
+	hf := NewHostFS()
+	if err := os.WriteFile(filepath.Join(dir, "a.txt"), []byte("old"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	txID, err := hf.BeginTx(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	txCtx := WithTx(ctx, txID)
+	for _, name := range []string{"a.txt", "b.txt", "c.txt"} {
+		if err := hf.WriteFile(txCtx, name, []byte("new"), 0); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Losing the last staged file makes its rename fail after a.txt and b.txt have been renamed into place
+	if err := os.Remove(filepath.Join(dir, TxDirPrefix+txID, "2")); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := hf.CommitTx(ctx, txID); err == nil {
+		t.Fatal("CommitTx succeeded without one of its staged files")
+	}
+	if got, err := readString(dir, "a.txt"); got != "old" {
+		t.Errorf("a.txt = %q, %v after failed commit, want %q restored", got, err, "old")
+	}
+	for _, name := range []string{"b.txt", "c.txt"} {
+		if _, err := os.Stat(filepath.Join(dir, name)); !errors.Is(err, fs.ErrNotExist) {
+			t.Errorf("%s exists after failed commit: %v", name, err)
+		}
+	}
+}
+
+func TestHostFSTxLeavesOpenWritersOut(t *testing.T) {
+	ctx, dir := rootContext(t)
+	hf := NewHostFS()
+	txID, err := hf.BeginTx(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w, err := hf.WriteFileStream(WithTx(ctx, txID), "a.txt", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := w.Write([]byte("partial")); err != nil {
+		t.Fatal(err)
+	}
+	if err := hf.CommitTx(ctx, txID); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); !errors.Is(err, ErrTxNotFound) {
+		t.Errorf("Close after commit = %v, want ErrTxNotFound", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "a.txt")); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("write still open at commit was committed: %v", err)
+	}
+}
+
+func TestSweepRootRemovesStaleTxDirs(t *testing.T) {
+	ctx, dir := rootContext(t)
+	stale := filepath.Join(dir, TxDirPrefix+"stale")
+	if err := os.MkdirAll(stale, 0o700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(stale, "0"), []byte("staged"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	old := time.Now().Add(-2 * staleAfter)
+	if err := os.Chtimes(stale, old, old); err != nil {
+		t.Fatal(err)
+	}
+	hf := NewHostFS()
+	txID, err := hf.BeginTx(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	hf.sweepRoot(RootFromContext(ctx))
+	if _, err := os.Stat(stale); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("stale staging directory was not removed: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, TxDirPrefix+txID)); err != nil {
+		t.Errorf("open transaction's staging directory was removed: %v", err)
+	}
+}
+
+func TestSweepKeepsLongOpenTx(t *testing.T) {
+	ctx, dir := rootContext(t)
+	hf := NewHostFS()
+	txID, err := hf.BeginTx(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := hf.WriteFile(WithTx(ctx, txID), "a.txt", []byte("staged"), 0); err != nil {
+		t.Fatal(err)
+	}
+	// Age the staging directory and everything in it past staleAfter, as for a transaction left open that long
+	old := time.Now().Add(-2 * staleAfter)
+	staging := filepath.Join(dir, TxDirPrefix+txID)
+	if err := filepath.WalkDir(staging, func(p string, _ fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		return os.Chtimes(p, old, old)
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	// A second plugin connecting to the same root, and the host sweeping it, must leave the transaction alone
+	server, err := NewHostServiceGRPCServer(NewHostServices(hf, NewHostEnv()), "other", dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	server.Close()
+	if err := hf.Sweep(dir); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := hf.CommitTx(ctx, txID); err != nil {
+		t.Fatalf("CommitTx after sweep = %v", err)
+	}
+	if got, err := readString(dir, "a.txt"); got != "staged" {
+		t.Errorf("a.txt = %q, %v after commit, want %q", got, err, "staged")
+	}
+}
+
+func TestSweepSweepsEachRootOnce(t *testing.T) {
+	_, dir := rootContext(t)
+	hf := NewHostFS()
+	if err := hf.Sweep(dir); err != nil {
+		t.Fatal(err)
+	}
+	stale := filepath.Join(dir, TxDirPrefix+"stale")
+	if err := os.Mkdir(stale, 0o700); err != nil {
+		t.Fatal(err)
+	}
+	old := time.Now().Add(-2 * staleAfter)
+	if err := os.Chtimes(stale, old, old); err != nil {
+		t.Fatal(err)
+	}
+	if err := hf.Sweep(dir); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(stale); err != nil {
+		t.Errorf("root was swept a second time: %v", err)
+	}
+}
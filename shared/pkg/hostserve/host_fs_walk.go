@@ -0,0 +1,145 @@
+package hostserve
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+
+	"github.com/bmatcuk/doublestar/v4"
+	"github.com/hashicorp/go-hclog"
+)
+
+// walker walks a tree within a root, delivering every entry it visits.
+type walker struct {
+	ctx  context.Context
+	root *os.Root
+	opts WalkOptions
+	out  chan<- WalkEntry
+	// ancestors holds the directories from the walked path down to the one being read, so that symbolic links
+	// leading back to one of them are not followed forever.
+	ancestors []fs.FileInfo
+}
+
+// Walk walks the tree rooted at path, reporting every entry other than internal files on the returned channel in
+// lexical order within each directory. Entries that cannot be read are reported with an error and the walk
+// continues past them. The channel is closed once the walk is complete or ctx is done.
+func (hf *HostFS) Walk(ctx context.Context, path string, opts WalkOptions) (<-chan WalkEntry, error) {
+	if err := opts.validate(); err != nil {
+		return nil, err
+	}
+	r, name, release, err := resolve(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	info, err := r.Lstat(name)
+	if err != nil {
+		release()
+		hclog.Default().Error("Failed to stat walked path", "path", path, "err", err)
+		return nil, err
+	}
+
+	entries := make(chan WalkEntry)
+	w := &walker{ctx: ctx, root: r, opts: opts, out: entries}
+	go func() {
+		defer release()
+		defer close(entries)
+		w.visit(name, path, ".", 0, info)
+	}()
+	return entries, nil
+}
+
+// send delivers e, reporting false once ctx is done.
+func (w *walker) send(e WalkEntry) bool {
+	select {
+	case w.out <- e:
+		return true
+	case <-w.ctx.Done():
+		return false
+	}
+}
+
+// visit reports the entry at name, described by its Lstat info, and walks beneath it if it is a directory. display
+// is the path the entry is reported as and rel its slash-separated path relative to the walked path. It reports
+// false once the walk should stop.
+func (w *walker) visit(name, display, rel string, depth int, info fs.FileInfo) bool {
+	if info.Mode()&fs.ModeSymlink != 0 && w.opts.FollowSymlinks {
+		target, err := w.root.Stat(name)
+		if err != nil {
+			return w.send(WalkEntry{Path: display, Depth: depth, Info: info, Err: err})
+		}
+		info = target
+	}
+	if !info.IsDir() || (w.opts.MaxDepth > 0 && depth >= w.opts.MaxDepth) {
+		return w.send(WalkEntry{Path: display, Depth: depth, Info: info})
+	}
+	for _, ancestor := range w.ancestors {
+		if os.SameFile(ancestor, info) {
+			return w.send(WalkEntry{Path: display, Depth: depth, Info: info, Err: ErrWalkCycle})
+		}
+	}
+
+	children, err := fs.ReadDir(w.root.FS(), filepath.ToSlash(name))
+	if err != nil {
+		hclog.Default().Warn("Failed to read directory during walk", "path", display, "err", err)
+	}
+	if !w.send(WalkEntry{Path: display, Depth: depth, Info: info, Err: err}) {
+		return false
+	}
+
+	w.ancestors = append(w.ancestors, info)
+	defer func() { w.ancestors = w.ancestors[:len(w.ancestors)-1] }()
+	for _, child := range children {
+		childRel := path.Join(rel, child.Name())
+		if internalName(child.Name()) || w.opts.skipped(childRel) {
+			continue
+		}
+		childDisplay := filepath.Join(display, child.Name())
+		childInfo, err := child.Info()
+		if err != nil {
+			// The entry was removed after its directory was read
+			if !w.send(WalkEntry{Path: childDisplay, Depth: depth + 1, Err: err}) {
+				return false
+			}
+			continue
+		}
+		if !w.visit(filepath.Join(name, child.Name()), childDisplay, childRel, depth+1, childInfo) {
+			return false
+		}
+	}
+	return true
+}
+
+// Glob returns the paths matching a doublestar pattern, such as "src/**/*.go". Matches are reported in the same form
+// as the pattern, so a relative pattern yields relative paths. Symbolic links are not followed while matching,
+// directories that cannot be read are skipped, and internal files are never matched.
+func (hf *HostFS) Glob(ctx context.Context, pattern string) ([]string, error) {
+	base, rest := splitGlob(pattern)
+	if !doublestar.ValidatePattern(rest) {
+		return nil, fmt.Errorf("%w: %s", doublestar.ErrBadPattern, pattern)
+	}
+	r, name, release, err := resolve(ctx, base)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	sub, err := fs.Sub(r.FS(), filepath.ToSlash(name))
+	if err != nil {
+		return nil, err
+	}
+	found, err := doublestar.Glob(sub, rest, doublestar.WithNoFollow())
+	if err != nil {
+		hclog.Default().Error("Failed to glob", "pattern", pattern, "err", err)
+		return nil, err
+	}
+	matches := make([]string, 0, len(found))
+	for _, match := range found {
+		if internalPath(match) {
+			continue
+		}
+		matches = append(matches, filepath.Join(base, filepath.FromSlash(match)))
+	}
+	return matches, nil
+}
@@ -0,0 +1,314 @@
+//go:build linux
+
+package hostserve
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"golang.org/x/sys/unix"
+)
+
+// inotifyMask is the set of inotify events subscribed to for every watched directory. Watches are added through
+// /proc/self/fd, whose links must be followed, so symbolic links are kept from redirecting a watch outside the root
+// by opening the watched path through the root instead (see addOne).
+const inotifyMask = unix.IN_CREATE | unix.IN_MODIFY | unix.IN_ATTRIB | unix.IN_DELETE | unix.IN_DELETE_SELF |
+	unix.IN_MOVED_FROM | unix.IN_MOVED_TO | unix.IN_MOVE_SELF
+
+// inotifyWatcher watches a path within a root through a single inotify instance.
+type inotifyWatcher struct {
+	file      *os.File
+	fd        int
+	root      *os.Root
+	name      string
+	path      string
+	recursive bool
+	// watches maps each watch descriptor to the name it watches within the root. It is only touched before the
+	// reader starts and by the reader itself.
+	watches map[int]string
+}
+
+// Watch reports changes to path, and to everything beneath it when recursive is set, on the returned channel.
+// Changes are coalesced over WatchCoalesceWindow, and changes to internal files are not reported. Directories are
+// discovered by walking the root, so symbolic links are never followed. The channel is closed once ctx is done, the
+// watched path is removed, or watching fails, in which case the final event carries the error.
+func (hf *HostFS) Watch(ctx context.Context, path string, recursive bool) (<-chan WatchEvent, error) {
+	r, name, release, err := resolve(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	info, err := r.Lstat(name)
+	if err != nil {
+		release()
+		hclog.Default().Error("Failed to stat watched path", "path", path, "err", err)
+		return nil, err
+	}
+	fd, err := unix.InotifyInit1(unix.IN_CLOEXEC | unix.IN_NONBLOCK)
+	if err != nil {
+		release()
+		hclog.Default().Error("Failed to create inotify instance", "path", path, "err", err)
+		return nil, os.NewSyscallError("inotify_init1", err)
+	}
+	w := &inotifyWatcher{
+		// A non-blocking descriptor lets the runtime poller interrupt reads when the file is closed.
+		file:      os.NewFile(uintptr(fd), "inotify"),
+		fd:        fd,
+		root:      r,
+		name:      name,
+		path:      path,
+		recursive: recursive && info.IsDir(),
+		watches:   make(map[int]string),
+	}
+	if _, err := w.add(name); err != nil {
+		_ = w.file.Close()
+		release()
+		hclog.Default().Error("Failed to watch path", "path", path, "err", err)
+		return nil, err
+	}
+
+	events := make(chan WatchEvent)
+	go w.run(ctx, events, release)
+	return events, nil
+}
+
+// add watches name and, for recursive watchers, every directory beneath it. It returns the names found beneath
+// name so that entries created before their directory was watched can still be reported.
+func (w *inotifyWatcher) add(name string) ([]string, error) {
+	if !w.recursive {
+		return nil, w.addOne(name)
+	}
+	var found []string
+	err := fs.WalkDir(w.root.FS(), filepath.ToSlash(name), func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		n := filepath.FromSlash(p)
+		if n != name && internalName(d.Name()) {
+			if d.IsDir() {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		if n != name {
+			found = append(found, n)
+		}
+		if d.IsDir() {
+			return w.addOne(n)
+		}
+		return nil
+	})
+	return found, err
+}
+
+// addOne adds an inotify watch for a single name within the root. The name is opened through the root, which never
+// resolves it to anything outside, and the watch is added to the open file by way of /proc/self/fd. A symbolic link
+// swapped into the path after it was discovered therefore cannot redirect the watch outside the root. A final
+// symbolic link is opened, and watched, as the link itself.
+func (w *inotifyWatcher) addOne(name string) error {
+	f, err := w.root.OpenFile(name, unix.O_PATH, 0)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = f.Close() }()
+	conn, err := f.SyscallConn()
+	if err != nil {
+		return err
+	}
+	var wd int
+	ctrlErr := conn.Control(func(fd uintptr) {
+		wd, err = unix.InotifyAddWatch(w.fd, "/proc/self/fd/"+strconv.FormatUint(uint64(fd), 10), inotifyMask)
+	})
+	if ctrlErr != nil {
+		return ctrlErr
+	}
+	if err != nil {
+		return &os.PathError{Op: "inotify_add_watch", Path: name, Err: err}
+	}
+	w.watches[wd] = name
+	return nil
+}
+
+// removeTree drops the watches for name and every directory beneath it, used when a directory leaves its parent.
+func (w *inotifyWatcher) removeTree(name string) {
+	prefix := name + string(filepath.Separator)
+	for wd, n := range w.watches {
+		if n == name || strings.HasPrefix(n, prefix) {
+			_, _ = unix.InotifyRmWatch(w.fd, uint32(wd))
+			delete(w.watches, wd)
+		}
+	}
+}
+
+// display converts a name within the root into the form the watch was requested in.
+func (w *inotifyWatcher) display(name string) string {
+	rel, err := filepath.Rel(w.name, name)
+	if err != nil || rel == "." {
+		return w.path
+	}
+	return filepath.Join(w.path, rel)
+}
+
+// run coalesces events from the reader and delivers them until ctx is done or the reader stops, then releases the
+// inotify instance and root and closes events.
+func (w *inotifyWatcher) run(ctx context.Context, events chan<- WatchEvent, release func()) {
+	raw := make(chan WatchEvent)
+	go w.read(raw)
+	defer func() {
+		_ = w.file.Close()
+		for range raw {
+		}
+		release()
+		close(events)
+	}()
+
+	pending := make(map[string]WatchOp)
+	var order []string
+	var flush <-chan time.Time
+	deliver := func() bool {
+		for _, p := range order {
+			select {
+			case events <- WatchEvent{Path: p, Op: pending[p]}:
+			case <-ctx.Done():
+				return false
+			}
+		}
+		clear(pending)
+		order = order[:0]
+		flush = nil
+		return true
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case e, ok := <-raw:
+			if !ok {
+				deliver()
+				return
+			}
+			if e.Err != nil {
+				if deliver() {
+					select {
+					case events <- e:
+					case <-ctx.Done():
+					}
+				}
+				return
+			}
+			if _, seen := pending[e.Path]; !seen {
+				order = append(order, e.Path)
+			}
+			pending[e.Path] |= e.Op
+			if flush == nil {
+				flush = time.After(WatchCoalesceWindow)
+			}
+		case <-flush:
+			if !deliver() {
+				return
+			}
+		}
+	}
+}
+
+// read decodes inotify events and sends them on raw until the inotify file is closed, the watched path goes away
+// or a read fails. It closes raw when it stops.
+func (w *inotifyWatcher) read(raw chan<- WatchEvent) {
+	defer close(raw)
+	buf := make([]byte, 64*(unix.SizeofInotifyEvent+unix.NAME_MAX+1))
+	for {
+		n, err := w.file.Read(buf)
+		if err != nil {
+			if !errors.Is(err, os.ErrClosed) {
+				raw <- WatchEvent{Err: err}
+			}
+			return
+		}
+		for off := 0; off+unix.SizeofInotifyEvent <= n; {
+			wd := int(int32(binary.NativeEndian.Uint32(buf[off:])))
+			mask := binary.NativeEndian.Uint32(buf[off+4:])
+			size := int(binary.NativeEndian.Uint32(buf[off+12:]))
+			start := off + unix.SizeofInotifyEvent
+			name := strings.TrimRight(string(buf[start:start+size]), "\x00")
+			off = start + size
+
+			for _, e := range w.translate(wd, mask, name) {
+				raw <- e
+			}
+			if len(w.watches) == 0 {
+				return
+			}
+		}
+	}
+}
+
+// translate converts a single inotify event into watch events, keeping the set of watched directories in step
+// with directories created, moved and removed beneath a recursive watch.
+func (w *inotifyWatcher) translate(wd int, mask uint32, name string) []WatchEvent {
+	if mask&unix.IN_Q_OVERFLOW != 0 {
+		return []WatchEvent{{Path: w.path, Op: WatchOverflow}}
+	}
+	dir, ok := w.watches[wd]
+	if !ok {
+		return nil
+	}
+	if mask&unix.IN_IGNORED != 0 {
+		delete(w.watches, wd)
+		return nil
+	}
+	// Internal files, such as those writes are made to before being renamed into place, are not reported.
+	if internalName(name) {
+		return nil
+	}
+	target := dir
+	if name != "" {
+		target = filepath.Join(dir, name)
+	}
+	// Subdirectories report their own removal and moves through their parent.
+	self := mask&(unix.IN_DELETE_SELF|unix.IN_MOVE_SELF) != 0
+	if self && dir != w.name {
+		return nil
+	}
+
+	var op WatchOp
+	if mask&(unix.IN_CREATE|unix.IN_MOVED_TO) != 0 {
+		op |= WatchCreate
+	}
+	if mask&unix.IN_MODIFY != 0 {
+		op |= WatchWrite
+	}
+	if mask&(unix.IN_DELETE|unix.IN_DELETE_SELF) != 0 {
+		op |= WatchRemove
+	}
+	if mask&(unix.IN_MOVED_FROM|unix.IN_MOVE_SELF) != 0 {
+		op |= WatchRename
+	}
+	if mask&unix.IN_ATTRIB != 0 {
+		op |= WatchChmod
+	}
+	events := []WatchEvent{{Path: w.display(target), Op: op}}
+
+	if w.recursive && mask&unix.IN_ISDIR != 0 && target != dir {
+		switch {
+		case mask&unix.IN_MOVED_FROM != 0:
+			w.removeTree(target)
+		case mask&(unix.IN_CREATE|unix.IN_MOVED_TO) != 0:
+			found, err := w.add(target)
+			if err != nil {
+				hclog.Default().Debug("Failed to watch new directory", "path", w.display(target), "err", err)
+			}
+			for _, f := range found {
+				events = append(events, WatchEvent{Path: w.display(f), Op: WatchCreate})
+			}
+		}
+	}
+	return events
+}
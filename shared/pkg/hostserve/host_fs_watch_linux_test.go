@@ -0,0 +1,91 @@
+//go:build linux
+
+package hostserve
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"golang.org/x/sys/unix"
+)
+
+// nextEvent returns the next event from events, failing the test if none arrives in time.
+func nextEvent(t *testing.T, events <-chan WatchEvent) WatchEvent {
+	t.Helper()
+	select {
+	case e, ok := <-events:
+		if !ok {
+			t.Fatal("watch ended early")
+		}
+		return e
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for watch event")
+		return WatchEvent{}
+	}
+}
+
+func TestHostFSWatchReportsChanges(t *testing.T) {
+	ctx, _ := rootContext(t)
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	hf := NewHostFS()
+	if err := hf.MkdirAll(ctx, "dir", 0); err != nil {
+		t.Fatal(err)
+	}
+	events, err := hf.Watch(ctx, "dir", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := hf.MkdirAll(ctx, "dir/sub", 0); err != nil {
+		t.Fatal(err)
+	}
+	e := nextEvent(t, events)
+	if e.Path != filepath.Join("dir", "sub") || !e.Op.Has(WatchCreate) {
+		t.Errorf("got event %v on %q, want CREATE on dir/sub", e.Op, e.Path)
+	}
+}
+
+func TestHostFSWatchHidesTempFiles(t *testing.T) {
+	ctx, _ := rootContext(t)
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	hf := NewHostFS()
+	events, err := hf.Watch(ctx, ".", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := hf.WriteFile(ctx, "a.txt", []byte("a"), 0); err != nil {
+		t.Fatal(err)
+	}
+	// The write is made to a temporary file renamed over a.txt, but only a.txt is reported
+	e := nextEvent(t, events)
+	if e.Path != "a.txt" || !e.Op.Has(WatchCreate) {
+		t.Errorf("got event %v on %q, want CREATE on a.txt", e.Op, e.Path)
+	}
+}
+
+func TestInotifyWatcherDoesNotFollowSymlinkOutOfRoot(t *testing.T) {
+	ctx, dir := rootContext(t)
+	outside := t.TempDir()
+	if err := os.Mkdir(filepath.Join(outside, "sub"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	// A directory swapped for a symbolic link after the watcher discovered it
+	if err := os.Symlink(outside, filepath.Join(dir, "swapped")); err != nil {
+		t.Fatal(err)
+	}
+	fd, err := unix.InotifyInit1(unix.IN_CLOEXEC)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w := &inotifyWatcher{file: os.NewFile(uintptr(fd), "inotify"), fd: fd, root: RootFromContext(ctx),
+		watches: make(map[int]string)}
+	defer func() { _ = w.file.Close() }()
+
+	if err := w.addOne(filepath.Join("swapped", "sub")); err == nil {
+		t.Errorf("watch was added through a symlink to %s, outside the root", outside)
+	}
+}
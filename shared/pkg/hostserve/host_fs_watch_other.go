@@ -0,0 +1,12 @@
+//go:build !linux
+
+package hostserve
+
+import (
+	"context"
+)
+
+// Watch is not supported on this platform and always returns ErrWatchUnsupported.
+func (hf *HostFS) Watch(ctx context.Context, path string, recursive bool) (<-chan WatchEvent, error) {
+	return nil, ErrWatchUnsupported
+}
@@ -13,3 +13,11 @@ func NewHostServices(fs IHostFS, env IHostEnv) *HostServices {
 		IHostEnv: env,
 	}
 }
+
+// Sweep sweeps dir with the file system if it is a Sweeper, and does nothing otherwise.
+func (h *HostServices) Sweep(dir string) error {
+	if s, ok := h.IHostFS.(Sweeper); ok {
+		return s.Sweep(dir)
+	}
+	return nil
+}
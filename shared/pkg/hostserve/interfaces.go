@@ -2,8 +2,10 @@ package hostserve
 
 import (
 	"context"
+	"io"
 	"io/fs"
 	"os"
+	"time"
 )
 
 // IHostServices is an interface that combines IHostFS and IHostEnv to provide file system and environment services.
@@ -25,7 +27,84 @@ type IHostFS interface {
 	// WriteFile writes data to the specified file within the given directory, applying the provided file permissions.
 	WriteFile(ctx context.Context, path string, data []byte, perm os.FileMode) error
 
-	//TODO - Add support for streaming reads and writes
+	// WriteFileIf writes data to the specified file like WriteFile, provided the file currently satisfies pre.
+	// Otherwise nothing is written and an error matching ErrConflict is returned.
+	WriteFileIf(ctx context.Context, path string, data []byte, perm os.FileMode, pre WritePrecondition) error
+
+	// Stat returns the file info for the specified path, following symbolic links.
+	Stat(ctx context.Context, path string) (fs.FileInfo, error)
+
+	// Lstat returns the file info for the specified path without following a final symbolic link.
+	Lstat(ctx context.Context, path string) (fs.FileInfo, error)
+
+	// MkdirAll creates the directory at path along with any missing parents, applying the provided permissions.
+	MkdirAll(ctx context.Context, path string, perm os.FileMode) error
+
+	// Remove removes the file or empty directory at path.
+	Remove(ctx context.Context, path string) error
+
+	// RemoveAll removes path and any children it contains. It returns nil if path does not exist.
+	RemoveAll(ctx context.Context, path string) error
+
+	// Rename moves oldPath to newPath, replacing newPath if it already exists and is not a directory.
+	Rename(ctx context.Context, oldPath, newPath string) error
+
+	// Copy copies the contents and permissions of the file at src to dst, returning the number of bytes copied.
+	Copy(ctx context.Context, src, dst string) (int64, error)
+
+	// Chmod changes the permissions of the file at path.
+	Chmod(ctx context.Context, path string, mode os.FileMode) error
+
+	// Truncate changes the size of the file at path.
+	Truncate(ctx context.Context, path string, size int64) error
+
+	// Append appends data to the file at path, creating it with the provided permissions if it does not exist.
+	Append(ctx context.Context, path string, data []byte, perm os.FileMode) error
+
+	// Watch reports changes to path, and to everything beneath it when recursive is set, on the returned channel
+	// until ctx is done.
+	Watch(ctx context.Context, path string, recursive bool) (<-chan WatchEvent, error)
+
+	// Walk walks the tree rooted at path and reports every entry on the returned channel, which is closed once the
+	// walk is complete or ctx is done.
+	Walk(ctx context.Context, path string, opts WalkOptions) (<-chan WalkEntry, error)
+
+	// Glob returns the paths matching a doublestar pattern, such as "src/**/*.go".
+	Glob(ctx context.Context, pattern string) ([]string, error)
+
+	// ReadDirStream opens the specified directory for reading its entries in batches, without holding the whole
+	// directory in memory.
+	ReadDirStream(ctx context.Context, path string) (DirReader, error)
+
+	// ReadFileStream opens the specified file for sequential reading. The caller is responsible for closing the
+	// returned reader. Prefer this over ReadFile for files that may not fit in a single message.
+	ReadFileStream(ctx context.Context, path string) (io.ReadCloser, error)
+
+	// WriteFileStream creates or truncates the specified file and returns a writer for its contents, applying the
+	// provided file permissions. The data is only guaranteed to be flushed once the writer has been closed.
+	WriteFileStream(ctx context.Context, path string, perm os.FileMode) (io.WriteCloser, error)
+
+	// BeginTx starts a transaction and returns its ID. Writes made with a context from WithTx are staged in the
+	// transaction until it is committed.
+	BeginTx(ctx context.Context) (string, error)
+
+	// CommitTx moves every write staged in the transaction into place, replacing each file atomically, and ends the
+	// transaction. A transaction that fails to commit is rolled back.
+	CommitTx(ctx context.Context, txID string) error
+
+	// RollbackTx discards every write staged in the transaction and ends it.
+	RollbackTx(ctx context.Context, txID string) error
+
+	// Lock acquires an advisory lock on path in the given mode, waiting until it is available or ctx is done, and
+	// returns its ID and the time its lease runs out. The lease may be shorter than requested, as the host caps how
+	// long a lock may be held. The lock is released by Unlock or once its lease has run out.
+	Lock(ctx context.Context, path string, mode LockMode, lease time.Duration) (string, time.Time, error)
+
+	// TryLock acquires an advisory lock on path like Lock, but fails with ErrLocked rather than waiting.
+	TryLock(ctx context.Context, path string, mode LockMode, lease time.Duration) (string, time.Time, error)
+
+	// Unlock releases a lock acquired by Lock or TryLock.
+	Unlock(ctx context.Context, lockID string) error
 }
 
 // IHostEnv defines a contract for interacting with environment variables in the host system.
@@ -33,4 +112,19 @@ type IHostEnv interface {
 
 	// GetEnv fetches the value of an environment variable by its key and returns it as a string.
 	GetEnv(ctx context.Context, key string) string
+
+	// LookupEnv fetches the value of an environment variable by its key, reporting whether it is set. Unlike GetEnv
+	// it distinguishes an unset variable from one that could not be read.
+	LookupEnv(ctx context.Context, key string) (string, bool, error)
+
+	// Environ lists the environment variables in "KEY=value" form.
+	Environ(ctx context.Context) ([]string, error)
+}
+
+// DirReader reads the entries of an open directory in batches. It follows the semantics of (*os.File).ReadDir:
+// with n > 0 it returns at most n entries and io.EOF once the directory is exhausted, and with n <= 0 it returns
+// all remaining entries. Entries are returned in directory order rather than sorted by name.
+type DirReader interface {
+	ReadDir(n int) ([]fs.DirEntry, error)
+	Close() error
 }
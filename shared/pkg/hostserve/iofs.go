@@ -0,0 +1,226 @@
+package hostserve
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/fs"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// FS presents a directory of an IHostFS as an io/fs.FS, so that host files can be handed to standard library code
+// such as fs.WalkDir, template.ParseFS or http.FS. It implements fs.ReadDirFS, fs.ReadFileFS, fs.StatFS and
+// fs.GlobFS on top of the host calls, and reads files through ReadFileStream so they may be of any size.
+//
+// Plugins usually create one over their host service client. Every call is made with the context the FS was created
+// with, as io/fs has no way to pass one per call.
+type FS struct {
+	ctx  context.Context
+	host IHostFS
+	dir  string
+}
+
+var (
+	errIsDir  = errors.New("is a directory")
+	errNotDir = errors.New("not a directory")
+)
+
+var (
+	_ fs.ReadDirFS  = (*FS)(nil)
+	_ fs.ReadFileFS = (*FS)(nil)
+	_ fs.StatFS     = (*FS)(nil)
+	_ fs.GlobFS     = (*FS)(nil)
+)
+
+// NewFS creates an FS rooted at dir on host. Names passed to the FS are slash-separated and relative to dir.
+func NewFS(ctx context.Context, host IHostFS, dir string) *FS {
+	return &FS{ctx: ctx, host: host, dir: dir}
+}
+
+// hostPath validates name as an io/fs path and converts it into a path on the host.
+func (f *FS) hostPath(op, name string) (string, error) {
+	if !fs.ValidPath(name) {
+		return "", &fs.PathError{Op: op, Path: name, Err: fs.ErrInvalid}
+	}
+	return filepath.Join(f.dir, filepath.FromSlash(name)), nil
+}
+
+// Open opens the named file or directory. Files are streamed from the host as they are read, and directories are
+// listed in batches as ReadDir is called.
+func (f *FS) Open(name string) (fs.File, error) {
+	p, err := f.hostPath("open", name)
+	if err != nil {
+		return nil, err
+	}
+	info, err := f.host.Stat(f.ctx, p)
+	if err != nil {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: err}
+	}
+	return &hostFile{fsys: f, name: name, path: p, info: namedInfo{FileInfo: info, name: path.Base(name)}}, nil
+}
+
+// ReadDir reads the named directory and returns its entries sorted by name.
+func (f *FS) ReadDir(name string) ([]fs.DirEntry, error) {
+	p, err := f.hostPath("readdir", name)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := f.host.ReadDir(f.ctx, p)
+	if err != nil {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: err}
+	}
+	slices.SortFunc(entries, func(a, b fs.DirEntry) int {
+		return strings.Compare(a.Name(), b.Name())
+	})
+	return entries, nil
+}
+
+// ReadFile reads the named file and returns its contents.
+func (f *FS) ReadFile(name string) ([]byte, error) {
+	p, err := f.hostPath("readfile", name)
+	if err != nil {
+		return nil, err
+	}
+	r, err := f.host.ReadFileStream(f.ctx, p)
+	if err != nil {
+		return nil, &fs.PathError{Op: "readfile", Path: name, Err: err}
+	}
+	defer r.Close()
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, &fs.PathError{Op: "readfile", Path: name, Err: err}
+	}
+	return data, nil
+}
+
+// Stat returns the file info for the named file, following symbolic links.
+func (f *FS) Stat(name string) (fs.FileInfo, error) {
+	p, err := f.hostPath("stat", name)
+	if err != nil {
+		return nil, err
+	}
+	info, err := f.host.Stat(f.ctx, p)
+	if err != nil {
+		return nil, &fs.PathError{Op: "stat", Path: name, Err: err}
+	}
+	return namedInfo{FileInfo: info, name: path.Base(name)}, nil
+}
+
+// Glob returns the names of the files matching pattern, with the syntax and results of fs.Glob. Patterns are matched
+// on the host in a single call unless they use "**" or braces, which the host's doublestar matching would treat
+// differently; those are matched here by listing directories instead.
+func (f *FS) Glob(pattern string) ([]string, error) {
+	if _, err := path.Match(pattern, ""); err != nil {
+		return nil, err
+	}
+	if strings.Contains(pattern, "**") || strings.ContainsAny(pattern, "{}") {
+		// Hide this method from fs.Glob so that it falls back to listing directories
+		return fs.Glob(struct{ fs.ReadDirFS }{f}, pattern)
+	}
+	matches, err := f.host.Glob(f.ctx, filepath.Join(f.dir, filepath.FromSlash(pattern)))
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(matches))
+	for _, match := range matches {
+		rel, err := filepath.Rel(f.dir, match)
+		if err != nil {
+			return nil, err
+		}
+		names = append(names, filepath.ToSlash(rel))
+	}
+	return names, nil
+}
+
+// namedInfo overrides the name of file info reported by the host with the base of the name it was requested by,
+// as io/fs requires.
+type namedInfo struct {
+	fs.FileInfo
+	name string
+}
+
+// Name returns the base name the file was requested by.
+func (i namedInfo) Name() string { return i.name }
+
+// hostFile is an fs.File for a file or directory on the host. The stream backing it is opened on first use.
+type hostFile struct {
+	fsys   *FS
+	name   string
+	path   string
+	info   fs.FileInfo
+	reader io.ReadCloser
+	dir    DirReader
+	closed bool
+}
+
+// Stat returns the file info captured when the file was opened.
+func (hf *hostFile) Stat() (fs.FileInfo, error) {
+	if hf.closed {
+		return nil, &fs.PathError{Op: "stat", Path: hf.name, Err: fs.ErrClosed}
+	}
+	return hf.info, nil
+}
+
+// Read reads from the file, opening a stream from the host on the first call.
+func (hf *hostFile) Read(p []byte) (int, error) {
+	if hf.closed {
+		return 0, &fs.PathError{Op: "read", Path: hf.name, Err: fs.ErrClosed}
+	}
+	if hf.info.IsDir() {
+		return 0, &fs.PathError{Op: "read", Path: hf.name, Err: errIsDir}
+	}
+	if hf.reader == nil {
+		r, err := hf.fsys.host.ReadFileStream(hf.fsys.ctx, hf.path)
+		if err != nil {
+			return 0, &fs.PathError{Op: "read", Path: hf.name, Err: err}
+		}
+		hf.reader = r
+	}
+	n, err := hf.reader.Read(p)
+	if err != nil && !errors.Is(err, io.EOF) {
+		err = &fs.PathError{Op: "read", Path: hf.name, Err: err}
+	}
+	return n, err
+}
+
+// ReadDir reads the directory's entries in the order the host lists them, following the semantics of
+// fs.ReadDirFile. The host listing is opened on the first call.
+func (hf *hostFile) ReadDir(n int) ([]fs.DirEntry, error) {
+	if hf.closed {
+		return nil, &fs.PathError{Op: "readdir", Path: hf.name, Err: fs.ErrClosed}
+	}
+	if !hf.info.IsDir() {
+		return nil, &fs.PathError{Op: "readdir", Path: hf.name, Err: errNotDir}
+	}
+	if hf.dir == nil {
+		dr, err := hf.fsys.host.ReadDirStream(hf.fsys.ctx, hf.path)
+		if err != nil {
+			return nil, &fs.PathError{Op: "readdir", Path: hf.name, Err: err}
+		}
+		hf.dir = dr
+	}
+	entries, err := hf.dir.ReadDir(n)
+	if err != nil && !errors.Is(err, io.EOF) {
+		err = &fs.PathError{Op: "readdir", Path: hf.name, Err: err}
+	}
+	return entries, err
+}
+
+// Close releases the stream backing the file, if one was opened.
+func (hf *hostFile) Close() error {
+	if hf.closed {
+		return &fs.PathError{Op: "close", Path: hf.name, Err: fs.ErrClosed}
+	}
+	hf.closed = true
+	var err error
+	if hf.reader != nil {
+		err = hf.reader.Close()
+	}
+	if hf.dir != nil {
+		err = errors.Join(err, hf.dir.Close())
+	}
+	return err
+}
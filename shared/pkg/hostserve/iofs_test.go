@@ -0,0 +1,105 @@
+package hostserve
+
+import (
+	"context"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	"github.com/bmj2728/hst/shared/protogen/hostserve/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// grpcClient serves impl to a HostServiceGRPCClient over an in-memory connection, confining its calls to dir as the
+// host does for a plugin.
+func grpcClient(t *testing.T, impl IHostServices, dir string) *HostServiceGRPCClient {
+	t.Helper()
+	return NewHostServiceGRPCClient(grpcConn(t, impl, dir))
+}
+
+// grpcConn serves impl over an in-memory connection like grpcClient, returning the generated client so that tests can
+// send messages HostServiceGRPCClient never would.
+func grpcConn(t *testing.T, impl IHostServices, dir string) hostservev1.HostServiceClient {
+	t.Helper()
+	hostServer, err := NewHostServiceGRPCServer(impl, "test", dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lis := bufconn.Listen(1 << 20)
+	server := grpc.NewServer()
+	hostservev1.RegisterHostServiceServer(server, hostServer)
+	go func() { _ = server.Serve(lis) }()
+
+	conn, err := grpc.NewClient("passthrough:///bufconn",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		_ = conn.Close()
+		server.Stop()
+		hostServer.Close()
+	})
+	return hostservev1.NewHostServiceClient(conn)
+}
+
+// seedTree writes a small tree of files, directories and a symbolic link into dir, returning the files it wrote.
+func seedTree(t *testing.T, dir string) []string {
+	t.Helper()
+	files := map[string]string{
+		"README.md":           "# readme\n",
+		"empty":               "",
+		"src/main.go":         "package main\n",
+		"src/util/strings.go": "package util\n",
+		"docs/a/b/c.txt":      "deep",
+	}
+	for name, data := range files {
+		p := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.Mkdir(filepath.Join(dir, "emptydir"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink("src/main.go", filepath.Join(dir, "main-link.go")); err != nil {
+		t.Fatal(err)
+	}
+	return []string{"README.md", "empty", "src/main.go", "src/util/strings.go", "docs/a/b/c.txt", "main-link.go"}
+}
+
+func TestFSOverHostFS(t *testing.T) {
+	ctx, dir := rootContext(t)
+	expected := seedTree(t, dir)
+	if err := fstest.TestFS(NewFS(ctx, NewHostFS(), "."), expected...); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestFSOverHostFSSubdirectory(t *testing.T) {
+	ctx, dir := rootContext(t)
+	seedTree(t, dir)
+	if err := fstest.TestFS(NewFS(ctx, NewHostFS(), "src"), "main.go", "util/strings.go"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestFSOverGRPCClient(t *testing.T) {
+	dir := t.TempDir()
+	expected := seedTree(t, dir)
+	client := grpcClient(t, NewHostServices(NewHostFS(), NewHostEnv()), dir)
+	if err := fstest.TestFS(NewFS(context.Background(), client, "."), expected...); err != nil {
+		t.Fatal(err)
+	}
+}
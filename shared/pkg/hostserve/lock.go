@@ -0,0 +1,179 @@
+package hostserve
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// DefaultLockLease is the lease given to a lock requested without one.
+	DefaultLockLease = 30 * time.Second
+	// DefaultMaxLockLease is the longest lease a lock is given unless the host sets another limit, as with
+	// HostFS.SetMaxLockLease.
+	DefaultMaxLockLease = 5 * time.Minute
+)
+
+var (
+	// ErrLocked indicates TryLock could not acquire a lock because a conflicting lock is held on the path.
+	ErrLocked = errors.New("path is locked")
+	// ErrLockNotFound is returned for a lock that is not held, whether because it was unlocked, its lease ran out or
+	// it was acquired by another plugin.
+	ErrLockNotFound = errors.New("lock not found")
+)
+
+// LockMode is the mode of an advisory lock. Any number of shared locks on a path may be held at once, but an
+// exclusive lock excludes every other lock on it.
+type LockMode int32
+
+const (
+	// LockShared is held alongside other shared locks, typically by readers.
+	LockShared LockMode = iota + 1
+	// LockExclusive is held alone, typically by a writer.
+	LockExclusive
+)
+
+// String returns the name of the lock mode.
+func (m LockMode) String() string {
+	switch m {
+	case LockShared:
+		return "shared"
+	case LockExclusive:
+		return "exclusive"
+	default:
+		return fmt.Sprintf("LockMode(%d)", int32(m))
+	}
+}
+
+// heldLock is a lock in a lockTable.
+type heldLock struct {
+	key     string
+	mode    LockMode
+	client  string
+	expires time.Time
+}
+
+// lockTable holds the advisory locks of a host filesystem, keyed by the absolute path they lock. Locks are advisory:
+// they only exclude other locks, never reads or writes of the path.
+type lockTable struct {
+	mu    sync.Mutex
+	locks map[string]*heldLock
+	// maxLease is the longest lease a lock is given, or zero for DefaultMaxLockLease
+	maxLease time.Duration
+	// released is closed, and replaced, whenever a lock is released, waking callers waiting to acquire one.
+	released chan struct{}
+}
+
+// setMaxLease sets the longest lease a lock is given, with zero restoring DefaultMaxLockLease.
+func (t *lockTable) setMaxLease(max time.Duration) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.maxLease = max
+}
+
+// acquire takes a lock on key in mode for lease on behalf of client, returning its ID and expiry. Leases longer than
+// the table's maximum are cut to it. If a conflicting lock is held, acquire waits for it to be released or to expire
+// when wait is set, and otherwise fails with ErrLocked. The path the caller named is used in errors.
+func (t *lockTable) acquire(ctx context.Context, key, path, client string, mode LockMode, lease time.Duration,
+	wait bool,
+) (string, time.Time, error) {
+	if mode != LockShared && mode != LockExclusive {
+		return "", time.Time{}, &fs.PathError{Op: "lock", Path: path, Err: fmt.Errorf("%w: %s", fs.ErrInvalid, mode)}
+	}
+	if lease < 0 {
+		return "", time.Time{}, &fs.PathError{Op: "lock", Path: path,
+			Err: fmt.Errorf("%w: negative lease", fs.ErrInvalid)}
+	}
+	if lease == 0 {
+		lease = DefaultLockLease
+	}
+	for {
+		t.mu.Lock()
+		now := time.Now()
+		blockedUntil, blocked := t.conflict(key, mode, now)
+		if !blocked {
+			expires := now.Add(min(lease, cmp.Or(t.maxLease, DefaultMaxLockLease)))
+			id, err := uuid.NewV7()
+			if err == nil {
+				if t.locks == nil {
+					t.locks = make(map[string]*heldLock)
+				}
+				t.locks[id.String()] = &heldLock{key: key, mode: mode, client: client, expires: expires}
+			}
+			t.mu.Unlock()
+			if err != nil {
+				return "", time.Time{}, err
+			}
+			return id.String(), expires, nil
+		}
+		released := t.releasedChan()
+		t.mu.Unlock()
+
+		if !wait {
+			return "", time.Time{}, &fs.PathError{Op: "lock", Path: path, Err: ErrLocked}
+		}
+		timer := time.NewTimer(time.Until(blockedUntil))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return "", time.Time{}, ctx.Err()
+		case <-released:
+		case <-timer.C:
+		}
+		timer.Stop()
+	}
+}
+
+// release releases the lock lockID held by client.
+func (t *lockTable) release(lockID, client string) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	l, ok := t.locks[lockID]
+	if !ok || l.client != client {
+		return fmt.Errorf("%w: %s", ErrLockNotFound, lockID)
+	}
+	delete(t.locks, lockID)
+	if !time.Now().Before(l.expires) {
+		return fmt.Errorf("%w: %s: lease expired", ErrLockNotFound, lockID)
+	}
+	t.notify()
+	return nil
+}
+
+// conflict reports whether a lock held on key conflicts with one in mode and, if so, when the last such lock expires.
+// Expired locks are dropped along the way. It must be called with mu held.
+func (t *lockTable) conflict(key string, mode LockMode, now time.Time) (time.Time, bool) {
+	var until time.Time
+	for id, l := range t.locks {
+		if !now.Before(l.expires) {
+			delete(t.locks, id)
+			continue
+		}
+		if l.key == key && (mode == LockExclusive || l.mode == LockExclusive) && l.expires.After(until) {
+			until = l.expires
+		}
+	}
+	return until, !until.IsZero()
+}
+
+// releasedChan returns the channel closed on the next release. It must be called with mu held.
+func (t *lockTable) releasedChan() chan struct{} {
+	if t.released == nil {
+		t.released = make(chan struct{})
+	}
+	return t.released
+}
+
+// notify wakes every caller waiting to acquire a lock. It must be called with mu held.
+func (t *lockTable) notify() {
+	if t.released != nil {
+		close(t.released)
+		t.released = nil
+	}
+}
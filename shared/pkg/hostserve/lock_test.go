@@ -0,0 +1,180 @@
+package hostserve
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"testing"
+	"time"
+)
+
+func TestTryLockConflicts(t *testing.T) {
+	tests := []struct {
+		name        string
+		held, mode  LockMode
+		heldPath    string
+		path        string
+		wantBlocked bool
+	}{
+		{name: "shared beside shared", held: LockShared, mode: LockShared, heldPath: "a", path: "a"},
+		{name: "exclusive beside shared", held: LockShared, mode: LockExclusive, heldPath: "a", path: "a",
+			wantBlocked: true},
+		{name: "shared beside exclusive", held: LockExclusive, mode: LockShared, heldPath: "a", path: "a",
+			wantBlocked: true},
+		{name: "exclusive beside exclusive", held: LockExclusive, mode: LockExclusive, heldPath: "a", path: "a",
+			wantBlocked: true},
+		{name: "exclusive on another path", held: LockExclusive, mode: LockExclusive, heldPath: "a", path: "b"},
+		{name: "same path named differently", held: LockExclusive, mode: LockShared, heldPath: "a", path: "sub/../a",
+			wantBlocked: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, _ := rootContext(t)
+			hf := NewHostFS()
+			if _, _, err := hf.TryLock(ctx, tt.heldPath, tt.held, 0); err != nil {
+				t.Fatal(err)
+			}
+			_, _, err := hf.TryLock(WithClientID(ctx, "other"), tt.path, tt.mode, 0)
+			switch {
+			case tt.wantBlocked && !errors.Is(err, ErrLocked):
+				t.Errorf("TryLock = %v, want ErrLocked", err)
+			case !tt.wantBlocked && err != nil:
+				t.Errorf("TryLock = %v, want the lock acquired", err)
+			}
+		})
+	}
+}
+
+func TestLockLeases(t *testing.T) {
+	tests := []struct {
+		name     string
+		maxLease time.Duration
+		lease    time.Duration
+		want     time.Duration
+	}{
+		{name: "requested lease", lease: time.Minute, want: time.Minute},
+		{name: "default lease", want: DefaultLockLease},
+		{name: "lease cut to default maximum", lease: 24 * time.Hour, want: DefaultMaxLockLease},
+		{name: "lease cut to configured maximum", maxLease: 10 * time.Second, lease: time.Minute,
+			want: 10 * time.Second},
+		{name: "default lease cut to configured maximum", maxLease: time.Second, want: time.Second},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, _ := rootContext(t)
+			hf := NewHostFS()
+			hf.SetMaxLockLease(tt.maxLease)
+			before := time.Now()
+			_, expires, err := hf.TryLock(ctx, "a", LockExclusive, tt.lease)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := expires.Sub(before); got < tt.want || got > tt.want+time.Second {
+				t.Errorf("lock expires after %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLockRejectsBadRequests(t *testing.T) {
+	ctx, _ := rootContext(t)
+	m := NewMemFS()
+	if _, _, err := m.TryLock(ctx, "a", LockExclusive, -time.Second); !errors.Is(err, fs.ErrInvalid) {
+		t.Errorf("TryLock with negative lease = %v, want fs.ErrInvalid", err)
+	}
+	if _, _, err := m.TryLock(ctx, "a", LockMode(7), 0); !errors.Is(err, fs.ErrInvalid) {
+		t.Errorf("TryLock with unknown mode = %v, want fs.ErrInvalid", err)
+	}
+	if _, _, err := NewHostFS().TryLock(ctx, "../a", LockShared, 0); !errors.Is(err, ErrInvalidPath) {
+		t.Errorf("TryLock outside root = %v, want ErrInvalidPath", err)
+	}
+}
+
+func TestLockExpiry(t *testing.T) {
+	ctx, _ := rootContext(t)
+	m := NewMemFS()
+	id, _, err := m.TryLock(ctx, "a", LockExclusive, 20*time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	other := WithClientID(ctx, "other")
+	if _, _, err := m.TryLock(other, "a", LockExclusive, 0); !errors.Is(err, ErrLocked) {
+		t.Fatalf("TryLock before expiry = %v, want ErrLocked", err)
+	}
+
+	// Lock waits out the lease rather than failing
+	waitCtx, cancel := context.WithTimeout(other, 2*time.Second)
+	defer cancel()
+	otherID, _, err := m.Lock(waitCtx, "a", LockExclusive, 0)
+	if err != nil {
+		t.Fatalf("Lock after expiry = %v", err)
+	}
+	if err := m.Unlock(ctx, id); !errors.Is(err, ErrLockNotFound) {
+		t.Errorf("Unlock of expired lock = %v, want ErrLockNotFound", err)
+	}
+	if err := m.Unlock(ctx, otherID); !errors.Is(err, ErrLockNotFound) {
+		t.Errorf("Unlock of another plugin's lock = %v, want ErrLockNotFound", err)
+	}
+	if err := m.Unlock(other, otherID); err != nil {
+		t.Errorf("Unlock = %v", err)
+	}
+}
+
+func TestLockWaitsForRelease(t *testing.T) {
+	ctx, _ := rootContext(t)
+	hf := NewHostFS()
+	id, _, err := hf.TryLock(ctx, "a", LockExclusive, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	acquired := make(chan error, 1)
+	go func() {
+		_, _, err := hf.Lock(WithClientID(ctx, "other"), "a", LockShared, 0)
+		acquired <- err
+	}()
+	select {
+	case err := <-acquired:
+		t.Fatalf("Lock returned %v while a conflicting lock was held", err)
+	case <-time.After(20 * time.Millisecond):
+	}
+	if err := hf.Unlock(ctx, id); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case err := <-acquired:
+		if err != nil {
+			t.Errorf("Lock = %v after release", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Lock did not return after the conflicting lock was released")
+	}
+
+	deadline, cancel := context.WithTimeout(ctx, 20*time.Millisecond)
+	defer cancel()
+	if _, _, err := hf.Lock(WithClientID(deadline, "third"), "a", LockExclusive, 0); !errors.Is(err,
+		context.DeadlineExceeded) {
+		t.Errorf("Lock past deadline = %v, want context.DeadlineExceeded", err)
+	}
+}
+
+func TestGRPCClientLock(t *testing.T) {
+	hf := NewHostFS()
+	hf.SetMaxLockLease(time.Second)
+	client := grpcClient(t, NewHostServices(hf, NewHostEnv()), t.TempDir())
+	ctx := context.Background()
+
+	before := time.Now()
+	id, expires, err := client.TryLock(ctx, "a", LockExclusive, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expires.Before(before) || expires.After(before.Add(2*time.Second)) {
+		t.Errorf("lock expires at %v, want within the host's one second maximum of %v", expires, before)
+	}
+	if _, _, err := client.TryLock(ctx, "a", LockShared, 0); !errors.Is(err, ErrLocked) {
+		t.Errorf("conflicting TryLock = %v, want ErrLocked", err)
+	}
+	if err := client.Unlock(ctx, id); err != nil {
+		t.Errorf("Unlock = %v", err)
+	}
+}
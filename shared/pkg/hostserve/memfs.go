@@ -0,0 +1,824 @@
+package hostserve
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/bmatcuk/doublestar/v4"
+)
+
+// MemFS is an IHostFS held entirely in memory, for tests and for running plugins without touching the disk.
+//
+// Paths are resolved as HostFS resolves them: relative to the root carried by the call's context, which must not be
+// escaped, or to the host's working directory for calls without one. A context's root starts out as an empty
+// directory the first time it is used. MemFS has no symbolic links, and Watch returns ErrWatchUnsupported.
+type MemFS struct {
+	*memOps
+}
+
+// NewMemFS creates an empty MemFS.
+func NewMemFS() *MemFS {
+	tree := &memTree{
+		root:     &memNode{mode: fs.ModeDir | DirPermissions, modTime: time.Now()},
+		nodes:    make(map[string]*memNode),
+		children: make(map[string]map[string]struct{}),
+	}
+	return &MemFS{memOps: &memOps{layer: tree, createRoots: true}}
+}
+
+// memNode is a file or directory held in memory.
+type memNode struct {
+	mode    fs.FileMode
+	data    []byte
+	modTime time.Time
+}
+
+// info describes the node under the given name.
+func (n *memNode) info(name string) fs.FileInfo {
+	return &memInfo{name: name, size: int64(len(n.data)), mode: n.mode, modTime: n.modTime}
+}
+
+// memInfo implements fs.FileInfo for files and directories held in memory.
+type memInfo struct {
+	name    string
+	size    int64
+	mode    fs.FileMode
+	modTime time.Time
+}
+
+// Name returns the base name of the file.
+func (i *memInfo) Name() string { return i.name }
+
+// Size returns the length in bytes of the file's contents.
+func (i *memInfo) Size() int64 { return i.size }
+
+// Mode returns the file mode bits.
+func (i *memInfo) Mode() fs.FileMode { return i.mode }
+
+// ModTime returns the time the file was last written.
+func (i *memInfo) ModTime() time.Time { return i.modTime }
+
+// IsDir reports whether the file info describes a directory.
+func (i *memInfo) IsDir() bool { return i.mode.IsDir() }
+
+// Sys always returns nil, as there is no underlying data source.
+func (i *memInfo) Sys() interface{} { return nil }
+
+// memLayer stores the files behind a memOps. Keys are clean absolute paths. memOps checks that a key exists and has
+// the right type before reading, listing or changing the mode of it, and that its parent is a directory before
+// putting a file or directory at it.
+type memLayer interface {
+	lstat(ctx context.Context, key string) (fs.FileInfo, error)
+	stat(ctx context.Context, key string) (fs.FileInfo, error)
+	readDir(ctx context.Context, key string) ([]fs.DirEntry, error)
+	readFile(ctx context.Context, key string) ([]byte, error)
+	putFile(ctx context.Context, key string, data []byte, perm fs.FileMode) error
+	putDir(ctx context.Context, key string, perm fs.FileMode) error
+	chmod(ctx context.Context, key string, perm fs.FileMode) error
+	// remove removes key and everything beneath it.
+	remove(ctx context.Context, key string) error
+}
+
+// memOps implements IHostFS on top of a memLayer, giving the layer's files the semantics of the os package. Calls that
+// change the layer hold mu exclusively, so each one sees and leaves the layer in a consistent state, while calls that
+// only read it share mu.
+type memOps struct {
+	mu    sync.RWMutex
+	layer memLayer
+	// createRoots creates the directory a context's root names the first time it is used, for layers that do not
+	// start out with the host's directories.
+	createRoots bool
+	txs         map[string]*memTx
+	locks       lockTable
+}
+
+// rlock takes mu for a call that only reads the layer and returns the function that releases it. Such calls share mu,
+// so an OverlayFS reading its base from disk does not hold up other readers. Layers that create roots on first use take
+// mu exclusively instead, as key may have to create one.
+func (m *memOps) rlock() func() {
+	if m.createRoots {
+		m.mu.Lock()
+		return m.mu.Unlock
+	}
+	m.mu.RLock()
+	return m.mu.RUnlock
+}
+
+// key resolves path to the key it is stored under. It must be called with mu held.
+func (m *memOps) key(ctx context.Context, path string) (string, error) {
+	r := RootFromContext(ctx)
+	if r == nil {
+		return filepath.Abs(path)
+	}
+	name, err := confine(r, path)
+	if err != nil {
+		return "", err
+	}
+	rootKey := filepath.Clean(r.Name())
+	if m.createRoots {
+		if err := m.mkdirAll(ctx, rootKey, DirPermissions); err != nil {
+			return "", err
+		}
+	}
+	return filepath.Join(rootKey, name), nil
+}
+
+// keyPair resolves two paths with a single lock held.
+func (m *memOps) keyPair(ctx context.Context, a, b string) (string, string, error) {
+	keyA, err := m.key(ctx, a)
+	if err != nil {
+		return "", "", err
+	}
+	keyB, err := m.key(ctx, b)
+	if err != nil {
+		return "", "", err
+	}
+	return keyA, keyB, nil
+}
+
+// isRoot reports whether key is the root of the filesystem or of the call's context, which may not be removed or
+// renamed.
+func isRoot(ctx context.Context, key string) bool {
+	if r := RootFromContext(ctx); r != nil && key == filepath.Clean(r.Name()) {
+		return true
+	}
+	return filepath.Dir(key) == key
+}
+
+// pathError reports err as a failure of op on path, replacing the operation and path of any error returned by a
+// layer with those the caller used.
+func pathError(op, path string, err error) error {
+	var pe *fs.PathError
+	if errors.As(err, &pe) {
+		err = pe.Err
+	}
+	return &fs.PathError{Op: op, Path: path, Err: err}
+}
+
+// ReadDir reads the directory at path and returns its entries sorted by name.
+func (m *memOps) ReadDir(ctx context.Context, path string) ([]fs.DirEntry, error) {
+	defer m.rlock()()
+	key, err := m.key(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	info, err := m.layer.stat(ctx, key)
+	if err != nil {
+		return nil, pathError("open", path, err)
+	}
+	if !info.IsDir() {
+		return nil, pathError("readdirent", path, syscall.ENOTDIR)
+	}
+	entries, err := m.layer.readDir(ctx, key)
+	if err != nil {
+		return nil, pathError("readdirent", path, err)
+	}
+	slices.SortFunc(entries, func(a, b fs.DirEntry) int {
+		return strings.Compare(a.Name(), b.Name())
+	})
+	return entries, nil
+}
+
+// ReadFile returns a copy of the contents of the file at path.
+func (m *memOps) ReadFile(ctx context.Context, path string) ([]byte, error) {
+	defer m.rlock()()
+	key, err := m.key(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	data, _, err := m.readFile(ctx, key)
+	if err != nil {
+		return nil, pathError("read", path, err)
+	}
+	return data, nil
+}
+
+// readFile returns the contents and mode of the file at key.
+func (m *memOps) readFile(ctx context.Context, key string) ([]byte, fs.FileMode, error) {
+	info, err := m.layer.stat(ctx, key)
+	if err != nil {
+		return nil, 0, err
+	}
+	if info.IsDir() {
+		return nil, 0, syscall.EISDIR
+	}
+	data, err := m.layer.readFile(ctx, key)
+	return data, info.Mode(), err
+}
+
+// WriteFile replaces the contents of the file at path with a copy of data, creating it with the provided permissions
+// if it does not exist. If the provided permissions are zero, it defaults to StandardPermissions. Within a
+// transaction the write is staged until the transaction is committed.
+func (m *memOps) WriteFile(ctx context.Context, path string, data []byte, perm os.FileMode) error {
+	return m.WriteFileIf(ctx, path, data, perm, WritePrecondition{})
+}
+
+// WriteFileIf writes data to the file at path like WriteFile, provided the file satisfies pre. Otherwise nothing is
+// written and a *ConflictError is returned. Within a transaction pre is checked when the transaction is committed.
+func (m *memOps) WriteFileIf(ctx context.Context, path string, data []byte, perm os.FileMode,
+	pre WritePrecondition,
+) error {
+	if perm&PermissionsMask == 0 {
+		perm = StandardPermissions
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	key, err := m.key(ctx, path)
+	if err != nil {
+		return err
+	}
+	if txID := TxFromContext(ctx); txID != "" {
+		return m.stage(ctx, txID, key, path, bytes.Clone(data), perm, pre)
+	}
+	if err := m.checkPre(ctx, key, path, pre); err != nil {
+		return err
+	}
+	if err := m.writeFile(ctx, key, bytes.Clone(data), perm); err != nil {
+		return pathError("open", path, err)
+	}
+	return nil
+}
+
+// checkPre returns a *ConflictError if the file at key, requested as path, does not satisfy pre. It must be called
+// with mu held.
+func (m *memOps) checkPre(ctx context.Context, key, path string, pre WritePrecondition) error {
+	return pre.check(path,
+		func() (fs.FileInfo, error) { return m.layer.stat(ctx, key) },
+		func() (io.ReadCloser, error) {
+			data, _, err := m.readFile(ctx, key)
+			return io.NopCloser(bytes.NewReader(data)), err
+		},
+	)
+}
+
+// writeFile puts data at key. As with os.WriteFile, an existing file keeps its permissions.
+func (m *memOps) writeFile(ctx context.Context, key string, data []byte, perm fs.FileMode) error {
+	if err := m.checkParent(ctx, key); err != nil {
+		return err
+	}
+	info, err := m.layer.stat(ctx, key)
+	switch {
+	case err == nil && info.IsDir():
+		return syscall.EISDIR
+	case err == nil:
+		perm = info.Mode()
+	case !errors.Is(err, fs.ErrNotExist):
+		return err
+	}
+	return m.layer.putFile(ctx, key, data, perm.Perm())
+}
+
+// checkParent returns an error unless the parent of key is a directory.
+func (m *memOps) checkParent(ctx context.Context, key string) error {
+	info, err := m.layer.stat(ctx, filepath.Dir(key))
+	if err != nil {
+		return err
+	}
+	if !info.IsDir() {
+		return syscall.ENOTDIR
+	}
+	return nil
+}
+
+// Stat returns the file info for the specified path.
+func (m *memOps) Stat(ctx context.Context, path string) (fs.FileInfo, error) {
+	defer m.rlock()()
+	key, err := m.key(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	info, err := m.layer.stat(ctx, key)
+	if err != nil {
+		return nil, pathError("stat", path, err)
+	}
+	return info, nil
+}
+
+// Lstat returns the file info for the specified path without following a final symbolic link.
+func (m *memOps) Lstat(ctx context.Context, path string) (fs.FileInfo, error) {
+	defer m.rlock()()
+	key, err := m.key(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	info, err := m.layer.lstat(ctx, key)
+	if err != nil {
+		return nil, pathError("lstat", path, err)
+	}
+	return info, nil
+}
+
+// MkdirAll creates the directory at path along with any missing parents. If the provided permissions are zero,
+// it defaults to DirPermissions.
+func (m *memOps) MkdirAll(ctx context.Context, path string, perm os.FileMode) error {
+	if perm&PermissionsMask == 0 {
+		perm = DirPermissions
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	key, err := m.key(ctx, path)
+	if err != nil {
+		return err
+	}
+	if err := m.mkdirAll(ctx, key, perm); err != nil {
+		return pathError("mkdir", path, err)
+	}
+	return nil
+}
+
+// mkdirAll creates the directory at key along with any missing parents.
+func (m *memOps) mkdirAll(ctx context.Context, key string, perm fs.FileMode) error {
+	info, err := m.layer.stat(ctx, key)
+	if err == nil {
+		if info.IsDir() {
+			return nil
+		}
+		return syscall.ENOTDIR
+	}
+	if !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	if parent := filepath.Dir(key); parent != key {
+		if err := m.mkdirAll(ctx, parent, perm); err != nil {
+			return err
+		}
+	}
+	return m.layer.putDir(ctx, key, perm.Perm())
+}
+
+// Remove removes the file or empty directory at path.
+func (m *memOps) Remove(ctx context.Context, path string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	key, err := m.key(ctx, path)
+	if err != nil {
+		return err
+	}
+	if isRoot(ctx, key) {
+		return ErrInvalidPath
+	}
+	info, err := m.layer.lstat(ctx, key)
+	if err != nil {
+		return pathError("remove", path, err)
+	}
+	if info.IsDir() {
+		entries, err := m.layer.readDir(ctx, key)
+		if err != nil {
+			return pathError("remove", path, err)
+		}
+		if len(entries) > 0 {
+			return pathError("remove", path, syscall.ENOTEMPTY)
+		}
+	}
+	if err := m.layer.remove(ctx, key); err != nil {
+		return pathError("remove", path, err)
+	}
+	return nil
+}
+
+// RemoveAll removes path and any children it contains. It refuses to remove the root itself.
+func (m *memOps) RemoveAll(ctx context.Context, path string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	key, err := m.key(ctx, path)
+	if err != nil {
+		return err
+	}
+	if isRoot(ctx, key) {
+		return ErrInvalidPath
+	}
+	if _, err := m.layer.lstat(ctx, key); err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil
+		}
+		return pathError("unlinkat", path, err)
+	}
+	if err := m.layer.remove(ctx, key); err != nil {
+		return pathError("unlinkat", path, err)
+	}
+	return nil
+}
+
+// Rename moves oldPath to newPath, replacing newPath if it is a file or an empty directory.
+func (m *memOps) Rename(ctx context.Context, oldPath, newPath string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	oldKey, newKey, err := m.keyPair(ctx, oldPath, newPath)
+	if err != nil {
+		return err
+	}
+	if isRoot(ctx, oldKey) || isRoot(ctx, newKey) {
+		return ErrInvalidPath
+	}
+	if err := m.rename(ctx, oldKey, newKey); err != nil {
+		var pe *fs.PathError
+		if errors.As(err, &pe) {
+			err = pe.Err
+		}
+		return &os.LinkError{Op: "rename", Old: oldPath, New: newPath, Err: err}
+	}
+	return nil
+}
+
+// rename moves oldKey to newKey by copying it and then removing the original.
+func (m *memOps) rename(ctx context.Context, oldKey, newKey string) error {
+	info, err := m.layer.lstat(ctx, oldKey)
+	if err != nil {
+		return err
+	}
+	if oldKey == newKey {
+		return nil
+	}
+	if info.IsDir() && strings.HasPrefix(newKey, oldKey+string(filepath.Separator)) {
+		return syscall.EINVAL
+	}
+	if err := m.checkParent(ctx, newKey); err != nil {
+		return err
+	}
+	target, err := m.layer.lstat(ctx, newKey)
+	switch {
+	case err == nil && target.IsDir():
+		if !info.IsDir() {
+			return syscall.EISDIR
+		}
+		entries, err := m.layer.readDir(ctx, newKey)
+		if err != nil {
+			return err
+		}
+		if len(entries) > 0 {
+			return syscall.ENOTEMPTY
+		}
+	case err == nil && info.IsDir():
+		return syscall.ENOTDIR
+	case err != nil && !errors.Is(err, fs.ErrNotExist):
+		return err
+	}
+	if err == nil {
+		if err := m.layer.remove(ctx, newKey); err != nil {
+			return err
+		}
+	}
+	if err := m.copyTree(ctx, oldKey, newKey, info); err != nil {
+		return err
+	}
+	return m.layer.remove(ctx, oldKey)
+}
+
+// copyTree copies the file or directory at from, described by info, and everything beneath it to to.
+func (m *memOps) copyTree(ctx context.Context, from, to string, info fs.FileInfo) error {
+	if !info.IsDir() {
+		data, err := m.layer.readFile(ctx, from)
+		if err != nil {
+			return err
+		}
+		return m.layer.putFile(ctx, to, data, info.Mode().Perm())
+	}
+	if err := m.layer.putDir(ctx, to, info.Mode().Perm()); err != nil {
+		return err
+	}
+	entries, err := m.layer.readDir(ctx, from)
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		childInfo, err := entry.Info()
+		if err != nil {
+			return err
+		}
+		err = m.copyTree(ctx, filepath.Join(from, entry.Name()), filepath.Join(to, entry.Name()), childInfo)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Copy copies the contents of the file at src to dst, creating or truncating dst with the permissions of src.
+// It returns the number of bytes copied. Within a transaction the write of dst is staged.
+func (m *memOps) Copy(ctx context.Context, src, dst string) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	srcKey, dstKey, err := m.keyPair(ctx, src, dst)
+	if err != nil {
+		return 0, err
+	}
+	data, mode, err := m.readFile(ctx, srcKey)
+	if err != nil {
+		return 0, pathError("open", src, err)
+	}
+	if txID := TxFromContext(ctx); txID != "" {
+		return int64(len(data)), m.stage(ctx, txID, dstKey, dst, data, mode.Perm(), WritePrecondition{})
+	}
+	if err := m.writeFile(ctx, dstKey, data, mode.Perm()); err != nil {
+		return 0, pathError("open", dst, err)
+	}
+	return int64(len(data)), nil
+}
+
+// Chmod changes the permissions of the file at path.
+func (m *memOps) Chmod(ctx context.Context, path string, mode os.FileMode) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	key, err := m.key(ctx, path)
+	if err != nil {
+		return err
+	}
+	if _, err := m.layer.stat(ctx, key); err != nil {
+		return pathError("chmod", path, err)
+	}
+	if err := m.layer.chmod(ctx, key, mode.Perm()); err != nil {
+		return pathError("chmod", path, err)
+	}
+	return nil
+}
+
+// Truncate changes the size of the file at path, extending it with zero bytes if size is larger than the file.
+func (m *memOps) Truncate(ctx context.Context, path string, size int64) error {
+	if size < 0 {
+		return pathError("truncate", path, syscall.EINVAL)
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	key, err := m.key(ctx, path)
+	if err != nil {
+		return err
+	}
+	data, mode, err := m.readFile(ctx, key)
+	if err != nil {
+		return pathError("truncate", path, err)
+	}
+	if size <= int64(len(data)) {
+		data = data[:size]
+	} else {
+		data = append(data, make([]byte, size-int64(len(data)))...)
+	}
+	if err := m.layer.putFile(ctx, key, data, mode.Perm()); err != nil {
+		return pathError("truncate", path, err)
+	}
+	return nil
+}
+
+// Append appends data to the file at path, creating it if it does not exist. If the provided permissions are zero,
+// it defaults to StandardPermissions.
+func (m *memOps) Append(ctx context.Context, path string, data []byte, perm os.FileMode) error {
+	if perm&PermissionsMask == 0 {
+		perm = StandardPermissions
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	key, err := m.key(ctx, path)
+	if err != nil {
+		return err
+	}
+	existing, mode, err := m.readFile(ctx, key)
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+		err = m.writeFile(ctx, key, bytes.Clone(data), perm)
+	case err == nil:
+		err = m.layer.putFile(ctx, key, append(existing, data...), mode.Perm())
+	}
+	if err != nil {
+		return pathError("open", path, err)
+	}
+	return nil
+}
+
+// Watch is not supported for files held in memory and always returns ErrWatchUnsupported.
+func (m *memOps) Watch(ctx context.Context, path string, recursive bool) (<-chan WatchEvent, error) {
+	return nil, ErrWatchUnsupported
+}
+
+// Walk walks the tree rooted at path, with the same ordering and options as HostFS.Walk.
+func (m *memOps) Walk(ctx context.Context, path string, opts WalkOptions) (<-chan WalkEntry, error) {
+	return walkFS(ctx, m, path, opts)
+}
+
+// Glob returns the paths matching a doublestar pattern, such as "src/**/*.go", reported in the same form as the
+// pattern.
+func (m *memOps) Glob(ctx context.Context, pattern string) ([]string, error) {
+	base, rest := splitGlob(pattern)
+	if !doublestar.ValidatePattern(rest) {
+		return nil, fmt.Errorf("%w: %s", doublestar.ErrBadPattern, pattern)
+	}
+	found, err := doublestar.Glob(NewFS(ctx, m, base), rest, doublestar.WithNoFollow())
+	if err != nil {
+		return nil, err
+	}
+	matches := make([]string, 0, len(found))
+	for _, match := range found {
+		matches = append(matches, filepath.Join(base, filepath.FromSlash(match)))
+	}
+	return matches, nil
+}
+
+// ReadDirStream lists the directory at path and returns a DirReader over its entries, sorted by name.
+func (m *memOps) ReadDirStream(ctx context.Context, path string) (DirReader, error) {
+	entries, err := m.ReadDir(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	return &memDirReader{entries: entries}, nil
+}
+
+// ReadFileStream returns a reader over a copy of the contents of the file at path.
+func (m *memOps) ReadFileStream(ctx context.Context, path string) (io.ReadCloser, error) {
+	data, err := m.ReadFile(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	return io.NopCloser(bytes.NewReader(data)), nil
+}
+
+// WriteFileStream returns a writer for new contents of the file at path. If the provided permissions are zero, it
+// defaults to StandardPermissions. The data written replaces the file in a single step when the writer is closed.
+func (m *memOps) WriteFileStream(ctx context.Context, path string, perm os.FileMode) (io.WriteCloser, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	key, err := m.key(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	if TxFromContext(ctx) == "" {
+		if err := m.checkParent(ctx, key); err != nil {
+			return nil, pathError("open", path, err)
+		}
+	}
+	return &memWriter{ops: m, ctx: ctx, path: path, perm: perm}, nil
+}
+
+// memDirReader is a DirReader over a directory listed in full.
+type memDirReader struct {
+	entries []fs.DirEntry
+}
+
+// ReadDir returns the next n entries, or all remaining entries if n <= 0.
+func (r *memDirReader) ReadDir(n int) ([]fs.DirEntry, error) {
+	if n <= 0 {
+		entries := r.entries
+		r.entries = nil
+		return entries, nil
+	}
+	if len(r.entries) == 0 {
+		return nil, io.EOF
+	}
+	n = min(n, len(r.entries))
+	entries := r.entries[:n]
+	r.entries = r.entries[n:]
+	return entries, nil
+}
+
+// Close releases the remaining entries.
+func (r *memDirReader) Close() error {
+	r.entries = nil
+	return nil
+}
+
+// memWriter buffers a file written through WriteFileStream until it is closed.
+type memWriter struct {
+	ops    *memOps
+	ctx    context.Context
+	path   string
+	perm   fs.FileMode
+	buf    bytes.Buffer
+	closed bool
+}
+
+// Write appends p to the buffered contents.
+func (w *memWriter) Write(p []byte) (int, error) {
+	if w.closed {
+		return 0, &fs.PathError{Op: "write", Path: w.path, Err: fs.ErrClosed}
+	}
+	return w.buf.Write(p)
+}
+
+// Close stores the buffered contents in the file.
+func (w *memWriter) Close() error {
+	if w.closed {
+		return &fs.PathError{Op: "close", Path: w.path, Err: fs.ErrClosed}
+	}
+	w.closed = true
+	return w.ops.WriteFile(w.ctx, w.path, w.buf.Bytes(), w.perm)
+}
+
+// abort discards the buffered contents, leaving the file untouched.
+func (w *memWriter) abort() {
+	w.closed = true
+	w.buf.Reset()
+}
+
+// memTree is the memLayer of a MemFS, holding every file and directory in a map keyed by path.
+type memTree struct {
+	root  *memNode
+	nodes map[string]*memNode
+	// children holds the names of the nodes directly beneath each directory, keyed by the directory's path, so that
+	// listing a directory or removing a tree only visits the nodes within it.
+	children map[string]map[string]struct{}
+}
+
+// put stores n at key, adding it to the children of its parent.
+func (t *memTree) put(key string, n *memNode) {
+	t.nodes[key] = n
+	parent := filepath.Dir(key)
+	if parent == key {
+		return
+	}
+	if t.children[parent] == nil {
+		t.children[parent] = make(map[string]struct{})
+	}
+	t.children[parent][filepath.Base(key)] = struct{}{}
+}
+
+// lookup returns the node at key, or an error if it or one of its parents does not exist.
+func (t *memTree) lookup(key string) (*memNode, error) {
+	if n, ok := t.nodes[key]; ok {
+		return n, nil
+	}
+	parent := filepath.Dir(key)
+	if parent == key {
+		return t.root, nil
+	}
+	p, err := t.lookup(parent)
+	if err != nil {
+		return nil, err
+	}
+	if !p.mode.IsDir() {
+		return nil, syscall.ENOTDIR
+	}
+	return nil, fs.ErrNotExist
+}
+
+// lstat returns the info for the node at key. MemFS has no symbolic links, so it is the same as stat.
+func (t *memTree) lstat(ctx context.Context, key string) (fs.FileInfo, error) {
+	return t.stat(ctx, key)
+}
+
+// stat returns the info for the node at key.
+func (t *memTree) stat(_ context.Context, key string) (fs.FileInfo, error) {
+	n, err := t.lookup(key)
+	if err != nil {
+		return nil, err
+	}
+	return n.info(filepath.Base(key)), nil
+}
+
+// readDir returns the entries of the directory at key, in no particular order.
+func (t *memTree) readDir(_ context.Context, key string) ([]fs.DirEntry, error) {
+	entries := make([]fs.DirEntry, 0, len(t.children[key]))
+	for name := range t.children[key] {
+		entries = append(entries, fs.FileInfoToDirEntry(t.nodes[filepath.Join(key, name)].info(name)))
+	}
+	return entries, nil
+}
+
+// readFile returns a copy of the contents of the file at key.
+func (t *memTree) readFile(_ context.Context, key string) ([]byte, error) {
+	n, err := t.lookup(key)
+	if err != nil {
+		return nil, err
+	}
+	return bytes.Clone(n.data), nil
+}
+
+// putFile stores a file at key holding data, replacing any file already there.
+func (t *memTree) putFile(_ context.Context, key string, data []byte, perm fs.FileMode) error {
+	t.put(key, &memNode{mode: perm, data: data, modTime: time.Now()})
+	return nil
+}
+
+// putDir stores an empty directory at key.
+func (t *memTree) putDir(_ context.Context, key string, perm fs.FileMode) error {
+	t.put(key, &memNode{mode: fs.ModeDir | perm, modTime: time.Now()})
+	return nil
+}
+
+// chmod changes the permissions of the node at key.
+func (t *memTree) chmod(_ context.Context, key string, perm fs.FileMode) error {
+	n, err := t.lookup(key)
+	if err != nil {
+		return err
+	}
+	n.mode = n.mode.Type() | perm
+	return nil
+}
+
+// remove removes the node at key along with everything beneath it.
+func (t *memTree) remove(ctx context.Context, key string) error {
+	for name := range t.children[key] {
+		_ = t.remove(ctx, filepath.Join(key, name))
+	}
+	delete(t.children, key)
+	delete(t.nodes, key)
+	delete(t.children[filepath.Dir(key)], filepath.Base(key))
+	return nil
+}
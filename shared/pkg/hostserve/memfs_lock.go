@@ -0,0 +1,43 @@
+package hostserve
+
+import (
+	"context"
+	"time"
+)
+
+// Lock acquires an advisory lock on path in the given mode, waiting until no conflicting lock is held or ctx is
+// done, and returns its ID and expiry. A zero lease defaults to DefaultLockLease, and leases are cut to the maximum
+// set by SetMaxLockLease.
+func (m *memOps) Lock(ctx context.Context, path string, mode LockMode, lease time.Duration) (string, time.Time, error) {
+	return m.lock(ctx, path, mode, lease, true)
+}
+
+// TryLock acquires an advisory lock on path like Lock, but fails with ErrLocked instead of waiting.
+func (m *memOps) TryLock(ctx context.Context, path string, mode LockMode,
+	lease time.Duration,
+) (string, time.Time, error) {
+	return m.lock(ctx, path, mode, lease, false)
+}
+
+// SetMaxLockLease sets the longest lease a lock is given, with zero restoring DefaultMaxLockLease.
+func (m *memOps) SetMaxLockLease(max time.Duration) {
+	m.locks.setMaxLease(max)
+}
+
+// Unlock releases the lock lockID, provided it belongs to the caller and its lease has not run out.
+func (m *memOps) Unlock(ctx context.Context, lockID string) error {
+	return m.locks.release(lockID, ClientIDFromContext(ctx))
+}
+
+// lock acquires a lock on the key path resolves to, waiting for conflicting locks to be released if wait is set.
+func (m *memOps) lock(ctx context.Context, path string, mode LockMode, lease time.Duration,
+	wait bool,
+) (string, time.Time, error) {
+	m.mu.Lock()
+	key, err := m.key(ctx, path)
+	m.mu.Unlock()
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	return m.locks.acquire(ctx, key, path, ClientIDFromContext(ctx), mode, lease, wait)
+}
@@ -0,0 +1,211 @@
+package hostserve
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"syscall"
+	"testing"
+)
+
+// seedMemFS returns a MemFS and a context rooted in it holding dir/a.txt, dir/sub/b.txt and the empty directory
+// empty.
+func seedMemFS(t *testing.T) (context.Context, *MemFS) {
+	t.Helper()
+	ctx, _ := rootContext(t)
+	m := NewMemFS()
+	for _, dir := range []string{"dir/sub", "empty"} {
+		if err := m.MkdirAll(ctx, dir, 0); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, file := range []string{"dir/a.txt", "dir/sub/b.txt"} {
+		if err := m.WriteFile(ctx, file, []byte(file), 0); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return ctx, m
+}
+
+// names returns the names of entries, in order.
+func names(entries []fs.DirEntry) []string {
+	out := make([]string, 0, len(entries))
+	for _, e := range entries {
+		out = append(out, e.Name())
+	}
+	return out
+}
+
+func TestMemFSErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		op   func(ctx context.Context, m *MemFS) error
+		want error
+	}{
+		{name: "read missing file", want: fs.ErrNotExist, op: func(ctx context.Context, m *MemFS) error {
+			_, err := m.ReadFile(ctx, "missing.txt")
+			return err
+		}},
+		{name: "read directory", want: syscall.EISDIR, op: func(ctx context.Context, m *MemFS) error {
+			_, err := m.ReadFile(ctx, "dir")
+			return err
+		}},
+		{name: "list file", want: syscall.ENOTDIR, op: func(ctx context.Context, m *MemFS) error {
+			_, err := m.ReadDir(ctx, "dir/a.txt")
+			return err
+		}},
+		{name: "stat beneath file", want: syscall.ENOTDIR, op: func(ctx context.Context, m *MemFS) error {
+			_, err := m.Stat(ctx, "dir/a.txt/x")
+			return err
+		}},
+		{name: "write without parent", want: fs.ErrNotExist, op: func(ctx context.Context, m *MemFS) error {
+			return m.WriteFile(ctx, "missing/a.txt", nil, 0)
+		}},
+		{name: "write over directory", want: syscall.EISDIR, op: func(ctx context.Context, m *MemFS) error {
+			return m.WriteFile(ctx, "dir", nil, 0)
+		}},
+		{name: "conditional write over directory", want: ErrConflict,
+			op: func(ctx context.Context, m *MemFS) error {
+				return m.WriteFileIf(ctx, "empty", nil, 0, WritePrecondition{MustNotExist: true})
+			}},
+		{name: "mkdir over file", want: syscall.ENOTDIR, op: func(ctx context.Context, m *MemFS) error {
+			return m.MkdirAll(ctx, "dir/a.txt", 0)
+		}},
+		{name: "mkdir beneath file", want: syscall.ENOTDIR, op: func(ctx context.Context, m *MemFS) error {
+			return m.MkdirAll(ctx, "dir/a.txt/x", 0)
+		}},
+		{name: "remove missing", want: fs.ErrNotExist, op: func(ctx context.Context, m *MemFS) error {
+			return m.Remove(ctx, "missing.txt")
+		}},
+		{name: "remove non-empty directory", want: syscall.ENOTEMPTY, op: func(ctx context.Context, m *MemFS) error {
+			return m.Remove(ctx, "dir")
+		}},
+		{name: "remove root", want: ErrInvalidPath, op: func(ctx context.Context, m *MemFS) error {
+			return m.RemoveAll(ctx, ".")
+		}},
+		{name: "rename missing", want: fs.ErrNotExist, op: func(ctx context.Context, m *MemFS) error {
+			return m.Rename(ctx, "missing.txt", "b.txt")
+		}},
+		{name: "rename file over directory", want: syscall.EISDIR, op: func(ctx context.Context, m *MemFS) error {
+			return m.Rename(ctx, "dir/a.txt", "empty")
+		}},
+		{name: "rename directory over file", want: syscall.ENOTDIR, op: func(ctx context.Context, m *MemFS) error {
+			return m.Rename(ctx, "empty", "dir/a.txt")
+		}},
+		{name: "rename over non-empty directory", want: syscall.ENOTEMPTY,
+			op: func(ctx context.Context, m *MemFS) error { return m.Rename(ctx, "empty", "dir") }},
+		{name: "rename into itself", want: syscall.EINVAL, op: func(ctx context.Context, m *MemFS) error {
+			return m.Rename(ctx, "dir", "dir/sub/dir")
+		}},
+		{name: "path outside root", want: ErrInvalidPath, op: func(ctx context.Context, m *MemFS) error {
+			_, err := m.ReadFile(ctx, "../a.txt")
+			return err
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, m := seedMemFS(t)
+			if err := tt.op(ctx, m); !errors.Is(err, tt.want) {
+				t.Errorf("got %v, want %v", err, tt.want)
+			}
+			// A failed call changes nothing
+			if data, err := m.ReadFile(ctx, "dir/sub/b.txt"); string(data) != "dir/sub/b.txt" {
+				t.Errorf("dir/sub/b.txt = %q, %v after failed call", data, err)
+			}
+		})
+	}
+}
+
+func TestMemFSChanges(t *testing.T) {
+	tests := []struct {
+		name string
+		op   func(ctx context.Context, m *MemFS) error
+		// exist and gone list the paths that must and must not exist after op
+		exist, gone []string
+	}{
+		{name: "mkdir creates parents", exist: []string{"x/y/z"},
+			op: func(ctx context.Context, m *MemFS) error { return m.MkdirAll(ctx, "x/y/z", 0) }},
+		{name: "mkdir on existing directory", exist: []string{"dir/a.txt"},
+			op: func(ctx context.Context, m *MemFS) error { return m.MkdirAll(ctx, "dir", 0) }},
+		{name: "remove empty directory", gone: []string{"empty"},
+			op: func(ctx context.Context, m *MemFS) error { return m.Remove(ctx, "empty") }},
+		{name: "remove all", exist: []string{"empty"}, gone: []string{"dir", "dir/a.txt", "dir/sub/b.txt"},
+			op: func(ctx context.Context, m *MemFS) error { return m.RemoveAll(ctx, "dir") }},
+		{name: "remove all of missing path",
+			op: func(ctx context.Context, m *MemFS) error { return m.RemoveAll(ctx, "missing") }},
+		{name: "rename directory", exist: []string{"moved/a.txt", "moved/sub/b.txt"}, gone: []string{"dir"},
+			op: func(ctx context.Context, m *MemFS) error { return m.Rename(ctx, "dir", "moved") }},
+		{name: "rename over empty directory", exist: []string{"empty/a.txt", "empty/sub/b.txt"}, gone: []string{"dir"},
+			op: func(ctx context.Context, m *MemFS) error { return m.Rename(ctx, "dir", "empty") }},
+		{name: "rename file over file", exist: []string{"dir/sub/b.txt"}, gone: []string{"dir/a.txt"},
+			op: func(ctx context.Context, m *MemFS) error { return m.Rename(ctx, "dir/a.txt", "dir/sub/b.txt") }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, m := seedMemFS(t)
+			if err := tt.op(ctx, m); err != nil {
+				t.Fatal(err)
+			}
+			for _, p := range tt.exist {
+				if _, err := m.Stat(ctx, p); err != nil {
+					t.Errorf("%s: %v, want it to exist", p, err)
+				}
+			}
+			for _, p := range tt.gone {
+				if _, err := m.Stat(ctx, p); !errors.Is(err, fs.ErrNotExist) {
+					t.Errorf("%s: %v, want fs.ErrNotExist", p, err)
+				}
+			}
+		})
+	}
+}
+
+func TestMemFSReadDirAfterChanges(t *testing.T) {
+	ctx, m := seedMemFS(t)
+	if err := m.Rename(ctx, "dir/sub", "dir/moved"); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.WriteFile(ctx, "dir/c.txt", nil, 0); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Remove(ctx, "dir/a.txt"); err != nil {
+		t.Fatal(err)
+	}
+	entries, err := m.ReadDir(ctx, "dir")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := names(entries), []string{"c.txt", "moved"}; !slices.Equal(got, want) {
+		t.Errorf("ReadDir(dir) = %v, want %v", got, want)
+	}
+	entries, err = m.ReadDir(ctx, "dir/moved")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := names(entries), []string{"b.txt"}; !slices.Equal(got, want) {
+		t.Errorf("ReadDir(dir/moved) = %v, want %v", got, want)
+	}
+}
+
+func TestMemFSKeepsModeOnOverwrite(t *testing.T) {
+	ctx, m := seedMemFS(t)
+	if err := m.Chmod(ctx, "dir/a.txt", 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.WriteFile(ctx, "dir/a.txt", []byte("new"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	info, err := m.Stat(ctx, "dir/a.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode() != 0o600 || info.Size() != 3 {
+		t.Errorf("dir/a.txt has mode %v and size %d, want %v and 3", info.Mode(), info.Size(), os.FileMode(0o600))
+	}
+	if _, err := m.Stat(ctx, filepath.Join("dir", "sub", "..", "a.txt")); err != nil {
+		t.Errorf("Stat through parent element = %v", err)
+	}
+}
@@ -0,0 +1,105 @@
+package hostserve
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"maps"
+	"slices"
+	"syscall"
+
+	"github.com/google/uuid"
+)
+
+// memTx is an open transaction on a memOps, holding its staged writes in memory.
+type memTx struct {
+	client string
+	staged map[string]memStaged
+}
+
+// memStaged is a write staged in a memTx, with the path and precondition it was requested with.
+type memStaged struct {
+	data []byte
+	perm fs.FileMode
+	path string
+	pre  WritePrecondition
+}
+
+// BeginTx starts a transaction belonging to the calling plugin.
+func (m *memOps) BeginTx(ctx context.Context) (string, error) {
+	id, err := uuid.NewV7()
+	if err != nil {
+		return "", err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.txs == nil {
+		m.txs = make(map[string]*memTx)
+	}
+	m.txs[id.String()] = &memTx{client: ClientIDFromContext(ctx), staged: make(map[string]memStaged)}
+	return id.String(), nil
+}
+
+// CommitTx replaces every file written in the transaction with its staged contents and permissions. Every target,
+// and every precondition the writes were staged with, is checked before any file is replaced, so a commit that fails
+// changes nothing; the transaction ends either way.
+func (m *memOps) CommitTx(ctx context.Context, txID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	tx, err := m.lookupTx(ctx, txID, true)
+	if err != nil {
+		return err
+	}
+	keys := slices.Sorted(maps.Keys(tx.staged))
+	for _, key := range keys {
+		if err := m.checkParent(ctx, key); err != nil {
+			return pathError("commit", key, err)
+		}
+		if info, err := m.layer.stat(ctx, key); err == nil && info.IsDir() {
+			return pathError("commit", key, syscall.EISDIR)
+		}
+		if err := m.checkPre(ctx, key, tx.staged[key].path, tx.staged[key].pre); err != nil {
+			return err
+		}
+	}
+	for _, key := range keys {
+		if err := m.layer.putFile(ctx, key, tx.staged[key].data, tx.staged[key].perm); err != nil {
+			return pathError("commit", key, err)
+		}
+	}
+	return nil
+}
+
+// RollbackTx discards every write staged in the transaction and ends it.
+func (m *memOps) RollbackTx(ctx context.Context, txID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	_, err := m.lookupTx(ctx, txID, true)
+	return err
+}
+
+// lookupTx returns the open transaction txID, provided it belongs to the caller. If end is set the transaction is
+// also removed from the open transactions. It must be called with mu held.
+func (m *memOps) lookupTx(ctx context.Context, txID string, end bool) (*memTx, error) {
+	tx, ok := m.txs[txID]
+	if !ok || tx.client != ClientIDFromContext(ctx) {
+		return nil, fmt.Errorf("%w: %s", ErrTxNotFound, txID)
+	}
+	if end {
+		delete(m.txs, txID)
+	}
+	return tx, nil
+}
+
+// stage records a write of data to key, requested as path and conditional on pre, in the transaction txID. It must
+// be called with mu held.
+func (m *memOps) stage(ctx context.Context, txID, key, path string, data []byte, perm fs.FileMode,
+	pre WritePrecondition,
+) error {
+	tx, err := m.lookupTx(ctx, txID, false)
+	if err != nil {
+		return err
+	}
+	tx.staged[key] = memStaged{data: data, perm: perm.Perm(), path: path, pre: pre}
+	return nil
+}
@@ -0,0 +1,341 @@
+package hostserve
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io/fs"
+	"path/filepath"
+	"slices"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// OverlayFS is a copy-on-write IHostFS over another IHostFS. Reads see the base filesystem together with any
+// pending changes, while every write, removal, rename and mode change is captured in memory and leaves the base
+// untouched. Pending changes can be listed with Changes, shown with Diff, and then applied to the base with Commit or
+// dropped with Discard, which lets plugins be run in a dry-run mode.
+//
+// Paths are resolved as HostFS resolves them, and changes are recorded under their absolute paths. Symbolic links in
+// the base are followed when copied or renamed, and Watch returns ErrWatchUnsupported.
+//
+// Calls that only read, such as ReadFile, Stat and ReadDir, run alongside each other, so plugins reading through an
+// overlay are not serialized behind one another's base I/O. Calls that capture a change hold the overlay exclusively.
+type OverlayFS struct {
+	*memOps
+	upper *overlayLayer
+}
+
+// NewOverlayFS creates an OverlayFS over base with no pending changes.
+func NewOverlayFS(base IHostFS) *OverlayFS {
+	upper := &overlayLayer{base: base, entries: make(map[string]*overlayEntry)}
+	return &OverlayFS{memOps: &memOps{layer: upper}, upper: upper}
+}
+
+// Sweep sweeps dir with the base if it is a Sweeper. The internal files it removes were left by an earlier run and
+// are not pending changes, so they are removed from the base directly.
+func (o *OverlayFS) Sweep(dir string) error {
+	if s, ok := o.upper.base.(Sweeper); ok {
+		return s.Sweep(dir)
+	}
+	return nil
+}
+
+// ChangeKind describes how a pending change alters a path in the base filesystem.
+type ChangeKind int
+
+const (
+	// ChangeCreate reports a path that does not exist in the base.
+	ChangeCreate ChangeKind = iota + 1
+	// ChangeModify reports a path whose contents, permissions or type differ from the base.
+	ChangeModify
+	// ChangeRemove reports a path removed from the base, along with everything beneath it.
+	ChangeRemove
+)
+
+// String returns the name of the change kind, e.g. "CREATE".
+func (k ChangeKind) String() string {
+	switch k {
+	case ChangeCreate:
+		return "CREATE"
+	case ChangeModify:
+		return "MODIFY"
+	case ChangeRemove:
+		return "REMOVE"
+	default:
+		return "UNKNOWN"
+	}
+}
+
+// Change is a pending change to a single path. Mode and Size describe the path as it will be once the change is
+// committed, or as it was in the base for a removal.
+type Change struct {
+	Path string
+	Kind ChangeKind
+	Mode fs.FileMode
+	Size int64
+}
+
+// Changes lists the pending changes, sorted by path so that a directory comes before its contents. Paths beneath a
+// removed or replaced directory are not listed individually.
+func (o *OverlayFS) Changes(ctx context.Context) ([]Change, error) {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+	var changes []Change
+	for _, key := range o.upper.keys() {
+		change, err := o.upper.change(ctx, key)
+		if err != nil {
+			return nil, err
+		}
+		changes = append(changes, change)
+	}
+	return changes, nil
+}
+
+// Commit applies the pending changes to the base in path order, using ctx for the calls it makes. ctx should carry
+// the same root, if any, as the calls that made the changes. Changes are dropped as they are applied, so if Commit
+// fails the changes that remain can be inspected and committed again.
+func (o *OverlayFS) Commit(ctx context.Context) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	for _, key := range o.upper.keys() {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := o.upper.commit(ctx, key); err != nil {
+			return err
+		}
+		delete(o.upper.entries, key)
+	}
+	return nil
+}
+
+// Discard drops every pending change, leaving the overlay showing the base as it is.
+func (o *OverlayFS) Discard() {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	clear(o.upper.entries)
+}
+
+// overlayEntry is a pending change to a single path.
+type overlayEntry struct {
+	// node holds the path's new file or directory, or is nil if the path was removed.
+	node *memNode
+	// opaque hides any children the path has in the base, for a directory created by the overlay rather than carried
+	// over from the base.
+	opaque bool
+}
+
+// overlayLayer is the memLayer of an OverlayFS, holding pending changes keyed by path in front of a base IHostFS.
+type overlayLayer struct {
+	base    IHostFS
+	entries map[string]*overlayEntry
+}
+
+// keys returns the paths with pending changes, sorted so that a directory comes before its contents.
+func (l *overlayLayer) keys() []string {
+	keys := make([]string, 0, len(l.entries))
+	for key := range l.entries {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+// shadow checks the pending changes to the parents of key. It returns an error if one of them was removed or
+// replaced by a file, and reports whether one of them hides the base's contents.
+func (l *overlayLayer) shadow(key string) (bool, error) {
+	hidden := false
+	for dir := filepath.Dir(key); ; dir = filepath.Dir(dir) {
+		if e, ok := l.entries[dir]; ok {
+			switch {
+			case e.node == nil:
+				return false, fs.ErrNotExist
+			case !e.node.mode.IsDir():
+				return false, syscall.ENOTDIR
+			case e.opaque:
+				hidden = true
+			}
+		}
+		if filepath.Dir(dir) == dir {
+			return hidden, nil
+		}
+	}
+}
+
+// inBase reports whether key exists in the base and is not hidden by a pending change to one of its parents.
+func (l *overlayLayer) inBase(ctx context.Context, key string) (fs.FileInfo, bool) {
+	if hidden, err := l.shadow(key); err != nil || hidden {
+		return nil, false
+	}
+	info, err := l.base.Lstat(ctx, key)
+	return info, err == nil
+}
+
+// lookup returns the info for key from the pending changes, or from the base using baseStat.
+func (l *overlayLayer) lookup(key string, baseStat func() (fs.FileInfo, error)) (fs.FileInfo, error) {
+	hidden, err := l.shadow(key)
+	if err != nil {
+		return nil, err
+	}
+	if e, ok := l.entries[key]; ok {
+		if e.node == nil {
+			return nil, fs.ErrNotExist
+		}
+		return e.node.info(filepath.Base(key)), nil
+	}
+	if hidden {
+		return nil, fs.ErrNotExist
+	}
+	return baseStat()
+}
+
+// lstat returns the info for key from its pending change, or from the base without following a final symbolic link.
+func (l *overlayLayer) lstat(ctx context.Context, key string) (fs.FileInfo, error) {
+	return l.lookup(key, func() (fs.FileInfo, error) { return l.base.Lstat(ctx, key) })
+}
+
+// stat returns the info for key from its pending change, or from the base.
+func (l *overlayLayer) stat(ctx context.Context, key string) (fs.FileInfo, error) {
+	return l.lookup(key, func() (fs.FileInfo, error) { return l.base.Stat(ctx, key) })
+}
+
+// readDir merges the entries the directory at key has in the base, unless they are hidden, with the pending changes
+// beneath it, in no particular order. It looks through every pending change, which is cheap while there are only as
+// many as a dry run makes.
+func (l *overlayLayer) readDir(ctx context.Context, key string) ([]fs.DirEntry, error) {
+	hidden, err := l.shadow(key)
+	if err != nil {
+		return nil, err
+	}
+	e, pending := l.entries[key]
+	byName := make(map[string]fs.DirEntry)
+	if !hidden && (!pending || !e.opaque) {
+		entries, err := l.base.ReadDir(ctx, key)
+		if err != nil && !(pending && errors.Is(err, fs.ErrNotExist)) {
+			return nil, err
+		}
+		for _, entry := range entries {
+			byName[entry.Name()] = entry
+		}
+	}
+	for k, e := range l.entries {
+		if k == key || filepath.Dir(k) != key {
+			continue
+		}
+		name := filepath.Base(k)
+		if e.node == nil {
+			delete(byName, name)
+		} else {
+			byName[name] = fs.FileInfoToDirEntry(e.node.info(name))
+		}
+	}
+	entries := make([]fs.DirEntry, 0, len(byName))
+	for _, entry := range byName {
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// readFile returns the contents of the file at key from its pending change, or from the base.
+func (l *overlayLayer) readFile(ctx context.Context, key string) ([]byte, error) {
+	if e, ok := l.entries[key]; ok && e.node != nil {
+		return bytes.Clone(e.node.data), nil
+	}
+	return l.base.ReadFile(ctx, key)
+}
+
+// putFile records a file at key holding data.
+func (l *overlayLayer) putFile(_ context.Context, key string, data []byte, perm fs.FileMode) error {
+	l.entries[key] = &overlayEntry{node: &memNode{mode: perm, data: data, modTime: time.Now()}}
+	return nil
+}
+
+// putDir records a new directory at key. Nothing at key is visible when a directory is put there, so any children
+// it has in the base, such as those of a removed directory, are hidden.
+func (l *overlayLayer) putDir(_ context.Context, key string, perm fs.FileMode) error {
+	l.entries[key] = &overlayEntry{node: &memNode{mode: fs.ModeDir | perm, modTime: time.Now()}, opaque: true}
+	return nil
+}
+
+// chmod records the new mode of key, copying a file's contents up from the base if it has no pending change.
+func (l *overlayLayer) chmod(ctx context.Context, key string, perm fs.FileMode) error {
+	if e, ok := l.entries[key]; ok && e.node != nil {
+		e.node.mode = e.node.mode.Type() | perm
+		return nil
+	}
+	info, err := l.base.Stat(ctx, key)
+	if err != nil {
+		return err
+	}
+	node := &memNode{mode: fs.ModeDir | perm, modTime: info.ModTime()}
+	if !info.IsDir() {
+		data, err := l.base.ReadFile(ctx, key)
+		if err != nil {
+			return err
+		}
+		node = &memNode{mode: perm, data: data, modTime: info.ModTime()}
+	}
+	l.entries[key] = &overlayEntry{node: node}
+	return nil
+}
+
+// remove drops the pending changes to key and everything beneath it, and records the removal of key if it exists in
+// the base.
+func (l *overlayLayer) remove(ctx context.Context, key string) error {
+	prefix := key + string(filepath.Separator)
+	for k := range l.entries {
+		if strings.HasPrefix(k, prefix) {
+			delete(l.entries, k)
+		}
+	}
+	delete(l.entries, key)
+	if _, ok := l.inBase(ctx, key); ok {
+		l.entries[key] = &overlayEntry{}
+	}
+	return nil
+}
+
+// change describes the pending change to key.
+func (l *overlayLayer) change(ctx context.Context, key string) (Change, error) {
+	e := l.entries[key]
+	base, inBase := l.inBase(ctx, key)
+	if e.node == nil {
+		change := Change{Path: key, Kind: ChangeRemove}
+		if inBase {
+			change.Mode = base.Mode()
+			change.Size = base.Size()
+		}
+		return change, nil
+	}
+	change := Change{Path: key, Kind: ChangeCreate, Mode: e.node.mode, Size: int64(len(e.node.data))}
+	if inBase {
+		change.Kind = ChangeModify
+	}
+	return change, nil
+}
+
+// commit applies the pending change to key to the base.
+func (l *overlayLayer) commit(ctx context.Context, key string) error {
+	e := l.entries[key]
+	if e.node == nil {
+		return l.base.RemoveAll(ctx, key)
+	}
+	base, inBase := l.inBase(ctx, key)
+	// A directory created in place of a removed path, or a file in place of a directory, replaces it entirely
+	if inBase && (e.opaque || (base.IsDir() && !e.node.mode.IsDir())) {
+		if err := l.base.RemoveAll(ctx, key); err != nil {
+			return err
+		}
+	}
+	perm := e.node.mode.Perm()
+	if e.node.mode.IsDir() {
+		if err := l.base.MkdirAll(ctx, key, perm); err != nil {
+			return err
+		}
+	} else if err := l.base.WriteFile(ctx, key, e.node.data, perm); err != nil {
+		return err
+	}
+	return l.base.Chmod(ctx, key, perm)
+}
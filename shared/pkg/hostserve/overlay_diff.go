@@ -0,0 +1,242 @@
+package hostserve
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/fs"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	// diffContext is the number of unchanged lines shown around each change in a Diff.
+	diffContext = 3
+	// maxDiffCells bounds the work spent matching lines between two versions of a file. Larger files are shown as
+	// replaced in full between their common first and last lines.
+	maxDiffCells = 1 << 22
+)
+
+// Diff renders the pending changes as a unified diff against the base, in the order listed by Changes. Text files
+// are shown line by line, binary files and directories by a single line describing the change, and permission
+// changes by "old mode" and "new mode" lines.
+func (o *OverlayFS) Diff(ctx context.Context) (string, error) {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+	var out strings.Builder
+	for _, key := range o.upper.keys() {
+		if err := o.upper.diff(ctx, key, &out); err != nil {
+			return "", err
+		}
+	}
+	return out.String(), nil
+}
+
+// diff writes the pending change to key to out.
+func (l *overlayLayer) diff(ctx context.Context, key string, out *strings.Builder) error {
+	e := l.entries[key]
+	base, inBase := l.inBase(ctx, key)
+	fmt.Fprintf(out, "diff %s\n", key)
+	if e.opaque && inBase {
+		// A replaced directory is shown as removed and created again
+		fmt.Fprintf(out, "deleted directory\n")
+		inBase = false
+	}
+	var oldData, newData []byte
+	var oldMode, newMode fs.FileMode
+	if inBase {
+		oldMode = base.Mode()
+		if base.Mode().IsRegular() {
+			data, err := l.base.ReadFile(ctx, key)
+			if err != nil {
+				return err
+			}
+			oldData = data
+		}
+	}
+	if e.node != nil {
+		newMode = e.node.mode
+		newData = e.node.data
+	}
+
+	switch {
+	case !inBase && newMode.IsDir():
+		fmt.Fprintf(out, "new directory mode %04o\n", newMode.Perm())
+		return nil
+	case !inBase:
+		fmt.Fprintf(out, "new file mode %04o\n", newMode.Perm())
+	case e.node == nil && oldMode.IsDir():
+		fmt.Fprintf(out, "deleted directory\n")
+		return nil
+	case e.node == nil:
+		fmt.Fprintf(out, "deleted file mode %04o\n", oldMode.Perm())
+	case oldMode.Type() != newMode.Type():
+		fmt.Fprintf(out, "type changed from %s to %s\n", oldMode.String(), newMode.String())
+	case oldMode.Perm() != newMode.Perm():
+		fmt.Fprintf(out, "old mode %04o\nnew mode %04o\n", oldMode.Perm(), newMode.Perm())
+	}
+	if newMode.IsDir() || bytes.Equal(oldData, newData) {
+		return nil
+	}
+
+	oldName, newName := key, key
+	if !inBase {
+		oldName = "/dev/null"
+	}
+	if e.node == nil {
+		newName = "/dev/null"
+	}
+	if !isText(oldData) || !isText(newData) {
+		fmt.Fprintf(out, "Binary files %s and %s differ\n", oldName, newName)
+		return nil
+	}
+	fmt.Fprintf(out, "--- %s\n+++ %s\n", oldName, newName)
+	unifiedDiff(out, splitLines(oldData), splitLines(newData))
+	return nil
+}
+
+// isText reports whether data looks like text, that is valid UTF-8 without NUL bytes.
+func isText(data []byte) bool {
+	return utf8.Valid(data) && bytes.IndexByte(data, 0) < 0
+}
+
+// splitLines splits data into lines, each keeping its trailing newline if it has one.
+func splitLines(data []byte) []string {
+	var lines []string
+	for len(data) > 0 {
+		n := bytes.IndexByte(data, '\n') + 1
+		if n == 0 {
+			n = len(data)
+		}
+		lines = append(lines, string(data[:n]))
+		data = data[n:]
+	}
+	return lines
+}
+
+// diffLine is a single line of an edit script: kept (' '), removed ('-') or added ('+').
+type diffLine struct {
+	op   byte
+	text string
+}
+
+// editScript returns the lines of a and b as an edit script that turns a into b, keeping a longest common
+// subsequence of lines where the files are small enough to search for one.
+func editScript(a, b []string) []diffLine {
+	var script []diffLine
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		script = append(script, diffLine{' ', a[prefix]})
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+	midA, midB := a[prefix:len(a)-suffix], b[prefix:len(b)-suffix]
+
+	if len(midA)*len(midB) > maxDiffCells {
+		for _, line := range midA {
+			script = append(script, diffLine{'-', line})
+		}
+		for _, line := range midB {
+			script = append(script, diffLine{'+', line})
+		}
+	} else {
+		// lcs[i][j] is the length of the longest common subsequence of midA[i:] and midB[j:]
+		lcs := make([][]int32, len(midA)+1)
+		for i := range lcs {
+			lcs[i] = make([]int32, len(midB)+1)
+		}
+		for i := len(midA) - 1; i >= 0; i-- {
+			for j := len(midB) - 1; j >= 0; j-- {
+				if midA[i] == midB[j] {
+					lcs[i][j] = lcs[i+1][j+1] + 1
+				} else {
+					lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+				}
+			}
+		}
+		i, j := 0, 0
+		for i < len(midA) || j < len(midB) {
+			switch {
+			case i < len(midA) && j < len(midB) && midA[i] == midB[j]:
+				script = append(script, diffLine{' ', midA[i]})
+				i++
+				j++
+			case j == len(midB) || (i < len(midA) && lcs[i+1][j] >= lcs[i][j+1]):
+				script = append(script, diffLine{'-', midA[i]})
+				i++
+			default:
+				script = append(script, diffLine{'+', midB[j]})
+				j++
+			}
+		}
+	}
+
+	for _, line := range a[len(a)-suffix:] {
+		script = append(script, diffLine{' ', line})
+	}
+	return script
+}
+
+// unifiedDiff writes the hunks turning a into b to out, each with up to diffContext lines of context.
+func unifiedDiff(out *strings.Builder, a, b []string) {
+	script := editScript(a, b)
+	// posA[k] and posB[k] count the lines of a and b that come before script[k]
+	posA := make([]int, len(script)+1)
+	posB := make([]int, len(script)+1)
+	for k, line := range script {
+		posA[k+1], posB[k+1] = posA[k], posB[k]
+		if line.op != '+' {
+			posA[k+1]++
+		}
+		if line.op != '-' {
+			posB[k+1]++
+		}
+	}
+
+	for k := 0; k < len(script); {
+		if script[k].op == ' ' {
+			k++
+			continue
+		}
+		start := max(k-diffContext, 0)
+		end := k
+		for {
+			for end < len(script) && script[end].op != ' ' {
+				end++
+			}
+			next := end
+			for next < len(script) && script[next].op == ' ' && next-end < 2*diffContext {
+				next++
+			}
+			if next == len(script) || script[next].op == ' ' {
+				break
+			}
+			end = next
+		}
+		stop := min(end+diffContext, len(script))
+
+		fmt.Fprintf(out, "@@ -%s +%s @@\n", hunkRange(posA[start], posA[stop]), hunkRange(posB[start], posB[stop]))
+		for _, line := range script[start:stop] {
+			out.WriteByte(line.op)
+			out.WriteString(line.text)
+			if !strings.HasSuffix(line.text, "\n") {
+				out.WriteString("\n\\ No newline at end of file\n")
+			}
+		}
+		k = stop
+	}
+}
+
+// hunkRange formats the lines from start up to end in unified diff form, e.g. "3,4".
+func hunkRange(start, end int) string {
+	if end == start {
+		return fmt.Sprintf("%d,0", start)
+	}
+	if end-start == 1 {
+		return fmt.Sprintf("%d", start+1)
+	}
+	return fmt.Sprintf("%d,%d", start+1, end-start)
+}
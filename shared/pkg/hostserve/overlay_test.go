@@ -0,0 +1,210 @@
+package hostserve
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+// seedOverlay writes base.txt, dir/a.txt and dir/sub/b.txt to a new root, and returns an OverlayFS over a HostFS
+// along with a context confined to the root and the root's directory.
+func seedOverlay(t *testing.T) (context.Context, *OverlayFS, string) {
+	t.Helper()
+	ctx, dir := rootContext(t)
+	for name, data := range map[string]string{"base.txt": "one\ntwo\nthree\n", "dir/a.txt": "a", "dir/sub/b.txt": "b"} {
+		p := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return ctx, NewOverlayFS(NewHostFS()), dir
+}
+
+func TestOverlayFSReadsFallThrough(t *testing.T) {
+	ctx, o, _ := seedOverlay(t)
+	if data, err := o.ReadFile(ctx, "dir/a.txt"); string(data) != "a" {
+		t.Errorf("ReadFile(dir/a.txt) = %q, %v, want the base contents", data, err)
+	}
+	if err := o.WriteFile(ctx, "dir/c.txt", []byte("c"), 0); err != nil {
+		t.Fatal(err)
+	}
+	entries, err := o.ReadDir(ctx, "dir")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := names(entries), []string{"a.txt", "c.txt", "sub"}; !slices.Equal(got, want) {
+		t.Errorf("ReadDir(dir) = %v, want the base entries merged with the pending ones %v", got, want)
+	}
+}
+
+func TestOverlayFSWhiteouts(t *testing.T) {
+	ctx, o, dir := seedOverlay(t)
+	if err := o.RemoveAll(ctx, "dir"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := o.Stat(ctx, "dir/a.txt"); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("Stat(dir/a.txt) after removal = %v, want fs.ErrNotExist", err)
+	}
+	entries, err := o.ReadDir(ctx, ".")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := names(entries), []string{"base.txt"}; !slices.Equal(got, want) {
+		t.Errorf("ReadDir(.) = %v, want %v", got, want)
+	}
+
+	// A directory created over the removed one starts out empty, hiding what the base has beneath it
+	if err := o.MkdirAll(ctx, "dir", 0); err != nil {
+		t.Fatal(err)
+	}
+	entries, err = o.ReadDir(ctx, "dir")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 0 {
+		t.Errorf("ReadDir(dir) = %v, want the base's entries hidden", names(entries))
+	}
+	if _, err := os.Stat(filepath.Join(dir, "dir", "sub", "b.txt")); err != nil {
+		t.Errorf("base was changed: %v", err)
+	}
+}
+
+func TestOverlayFSChanges(t *testing.T) {
+	tests := []struct {
+		name string
+		op   func(ctx context.Context, o *OverlayFS) error
+		want []Change
+	}{
+		{name: "create file", op: func(ctx context.Context, o *OverlayFS) error {
+			return o.WriteFile(ctx, "new.txt", []byte("new"), 0o600)
+		}, want: []Change{{Path: "new.txt", Kind: ChangeCreate, Mode: 0o600, Size: 3}}},
+		{name: "modify file", op: func(ctx context.Context, o *OverlayFS) error {
+			return o.WriteFile(ctx, "dir/a.txt", []byte("aa"), 0)
+		}, want: []Change{{Path: "dir/a.txt", Kind: ChangeModify, Mode: 0o644, Size: 2}}},
+		{name: "remove tree", op: func(ctx context.Context, o *OverlayFS) error {
+			return o.RemoveAll(ctx, "dir")
+		}, want: []Change{{Path: "dir", Kind: ChangeRemove, Mode: fs.ModeDir | 0o755}}},
+		{name: "create and remove", op: func(ctx context.Context, o *OverlayFS) error {
+			if err := o.WriteFile(ctx, "tmp.txt", nil, 0); err != nil {
+				return err
+			}
+			return o.Remove(ctx, "tmp.txt")
+		}},
+		{name: "rename", op: func(ctx context.Context, o *OverlayFS) error {
+			return o.Rename(ctx, "dir/a.txt", "moved.txt")
+		}, want: []Change{
+			{Path: "dir/a.txt", Kind: ChangeRemove, Mode: 0o644, Size: 1},
+			{Path: "moved.txt", Kind: ChangeCreate, Mode: 0o644, Size: 1},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, o, dir := seedOverlay(t)
+			if err := tt.op(ctx, o); err != nil {
+				t.Fatal(err)
+			}
+			changes, err := o.Changes(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for i := range changes {
+				// The size of a directory depends on the base filesystem
+				if changes[i].Mode.IsDir() {
+					changes[i].Size = 0
+				}
+			}
+			for i := range tt.want {
+				tt.want[i].Path = filepath.Join(dir, filepath.FromSlash(tt.want[i].Path))
+			}
+			if !slices.Equal(changes, tt.want) {
+				t.Errorf("Changes() = %+v, want %+v", changes, tt.want)
+			}
+		})
+	}
+}
+
+func TestOverlayFSDiff(t *testing.T) {
+	ctx, o, dir := seedOverlay(t)
+	if err := o.WriteFile(ctx, "base.txt", []byte("one\n2\nthree\n"), 0); err != nil {
+		t.Fatal(err)
+	}
+	if err := o.Remove(ctx, "dir/sub/b.txt"); err != nil {
+		t.Fatal(err)
+	}
+	diff, err := o.Diff(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	base, b := filepath.Join(dir, "base.txt"), filepath.Join(dir, "dir", "sub", "b.txt")
+	want := "diff " + base + "\n--- " + base + "\n+++ " + base + "\n@@ -1,3 +1,3 @@\n one\n-two\n+2\n three\n" +
+		"diff " + b + "\ndeleted file mode 0644\n--- " + b + "\n+++ /dev/null\n@@ -1 +0,0 @@\n-b\n" +
+		"\\ No newline at end of file\n"
+	if diff != want {
+		t.Errorf("Diff() =\n%s\nwant\n%s", diff, want)
+	}
+}
+
+func TestOverlayFSCommit(t *testing.T) {
+	ctx, o, dir := seedOverlay(t)
+	// Replacing a directory with a file, and creating a directory with contents, need applying in path order
+	if err := o.RemoveAll(ctx, "dir/sub"); err != nil {
+		t.Fatal(err)
+	}
+	if err := o.WriteFile(ctx, "dir/sub", []byte("file"), 0); err != nil {
+		t.Fatal(err)
+	}
+	if err := o.MkdirAll(ctx, "new/deep", 0); err != nil {
+		t.Fatal(err)
+	}
+	if err := o.WriteFile(ctx, "new/deep/c.txt", []byte("c"), 0); err != nil {
+		t.Fatal(err)
+	}
+	if err := o.Remove(ctx, "base.txt"); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := readString(dir, "dir/sub/b.txt"); got != "b" {
+		t.Fatal("base was changed before commit")
+	}
+
+	if err := o.Commit(ctx); err != nil {
+		t.Fatal(err)
+	}
+	for name, want := range map[string]string{"dir/sub": "file", "new/deep/c.txt": "c", "dir/a.txt": "a"} {
+		if got, err := readString(dir, name); got != want {
+			t.Errorf("%s = %q, %v after commit, want %q", name, got, err, want)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(dir, "base.txt")); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("base.txt after commit: %v, want it removed", err)
+	}
+	if changes, err := o.Changes(ctx); err != nil || len(changes) != 0 {
+		t.Errorf("Changes() after commit = %v, %v, want none", changes, err)
+	}
+}
+
+func TestOverlayFSDiscard(t *testing.T) {
+	ctx, o, dir := seedOverlay(t)
+	if err := o.WriteFile(ctx, "dir/a.txt", []byte("changed"), 0); err != nil {
+		t.Fatal(err)
+	}
+	if err := o.RemoveAll(ctx, "dir/sub"); err != nil {
+		t.Fatal(err)
+	}
+	o.Discard()
+	if data, err := o.ReadFile(ctx, "dir/a.txt"); string(data) != "a" {
+		t.Errorf("ReadFile(dir/a.txt) after discard = %q, %v, want the base contents", data, err)
+	}
+	if _, err := o.Stat(ctx, "dir/sub/b.txt"); err != nil {
+		t.Errorf("Stat(dir/sub/b.txt) after discard = %v", err)
+	}
+	if got, _ := readString(dir, "dir/a.txt"); got != "a" {
+		t.Errorf("discard changed the base: dir/a.txt = %q", got)
+	}
+}
@@ -0,0 +1,98 @@
+package hostserve
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"time"
+)
+
+// ErrConflict indicates a conditional write was refused because the file did not satisfy its WritePrecondition.
+var ErrConflict = errors.New("write conflict")
+
+// ConflictError describes a conditional write that was refused, and why the file did not satisfy its precondition.
+// It matches ErrConflict with errors.Is.
+type ConflictError struct {
+	Path   string
+	Reason string
+}
+
+// Error returns a description of the refused write and the reason for it.
+func (e *ConflictError) Error() string {
+	return fmt.Sprintf("write %s: %s: %s", e.Path, ErrConflict, e.Reason)
+}
+
+// Unwrap returns ErrConflict so callers can test for conflicts with errors.Is.
+func (e *ConflictError) Unwrap() error {
+	return ErrConflict
+}
+
+// WritePrecondition is what a file must satisfy for WriteFileIf to replace it. Every condition that is set must hold,
+// so that plugins sharing files can detect that another has changed one since they last read it.
+type WritePrecondition struct {
+	// ModTime, if non-zero, is the modification time the file must have, as reported by Stat. Modification times
+	// are only as fine as the host filesystem's clock, so writes in quick succession may share one; SHA256 detects
+	// every change to the contents.
+	ModTime time.Time
+	// SHA256, if set, is the SHA-256 digest the file's contents must have.
+	SHA256 []byte
+	// MustNotExist requires that nothing exist at the path.
+	MustNotExist bool
+}
+
+// IsZero reports whether p sets no conditions.
+func (p WritePrecondition) IsZero() bool {
+	return p.ModTime.IsZero() && p.SHA256 == nil && !p.MustNotExist
+}
+
+// readsFile reports whether checking p reveals anything about the file's current state beyond its existence.
+func (p WritePrecondition) readsFile() bool {
+	return !p.ModTime.IsZero() || p.SHA256 != nil
+}
+
+// check returns a *ConflictError if the file at path does not satisfy p. The file is examined through stat and, only
+// when a digest is expected, open.
+func (p WritePrecondition) check(path string, stat func() (fs.FileInfo, error),
+	open func() (io.ReadCloser, error),
+) error {
+	if p.IsZero() {
+		return nil
+	}
+	info, err := stat()
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	exists := err == nil
+	switch {
+	case p.MustNotExist && exists:
+		return &ConflictError{Path: path, Reason: "file exists"}
+	case !p.readsFile():
+		return nil
+	case !exists:
+		return &ConflictError{Path: path, Reason: "file does not exist"}
+	case !info.Mode().IsRegular():
+		return &ConflictError{Path: path, Reason: "not a regular file"}
+	case !p.ModTime.IsZero() && !info.ModTime().Equal(p.ModTime):
+		return &ConflictError{Path: path, Reason: fmt.Sprintf("modified at %s, expected %s",
+			info.ModTime().Format(time.RFC3339Nano), p.ModTime.Format(time.RFC3339Nano))}
+	case p.SHA256 == nil:
+		return nil
+	}
+
+	f, err := open()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = f.Close() }()
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return err
+	}
+	if sum := h.Sum(nil); !bytes.Equal(sum, p.SHA256) {
+		return &ConflictError{Path: path, Reason: fmt.Sprintf("content digest %x, expected %x", sum, p.SHA256)}
+	}
+	return nil
+}
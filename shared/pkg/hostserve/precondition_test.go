@@ -0,0 +1,82 @@
+package hostserve
+
+import (
+	"crypto/sha256"
+	"errors"
+	"io/fs"
+	"testing"
+	"time"
+)
+
+func TestWriteFileIfPreconditions(t *testing.T) {
+	sum := sha256.Sum256([]byte("old"))
+	tests := []struct {
+		name string
+		path string
+		// pre returns the precondition to write with, given the file info of a.txt
+		pre      func(info fs.FileInfo) WritePrecondition
+		conflict bool
+	}{
+		{name: "no conditions", path: "a.txt", pre: func(fs.FileInfo) WritePrecondition {
+			return WritePrecondition{}
+		}},
+		{name: "must not exist on missing file", path: "b.txt", pre: func(fs.FileInfo) WritePrecondition {
+			return WritePrecondition{MustNotExist: true}
+		}},
+		{name: "must not exist on existing file", path: "a.txt", conflict: true,
+			pre: func(fs.FileInfo) WritePrecondition { return WritePrecondition{MustNotExist: true} }},
+		{name: "matching digest", path: "a.txt", pre: func(fs.FileInfo) WritePrecondition {
+			return WritePrecondition{SHA256: sum[:]}
+		}},
+		{name: "other digest", path: "a.txt", conflict: true, pre: func(fs.FileInfo) WritePrecondition {
+			return WritePrecondition{SHA256: make([]byte, sha256.Size)}
+		}},
+		{name: "digest of missing file", path: "b.txt", conflict: true, pre: func(fs.FileInfo) WritePrecondition {
+			return WritePrecondition{SHA256: sum[:]}
+		}},
+		{name: "matching modification time", path: "a.txt", pre: func(info fs.FileInfo) WritePrecondition {
+			return WritePrecondition{ModTime: info.ModTime()}
+		}},
+		{name: "other modification time", path: "a.txt", conflict: true, pre: func(info fs.FileInfo) WritePrecondition {
+			return WritePrecondition{ModTime: info.ModTime().Add(-time.Hour)}
+		}},
+	}
+	impls := map[string]func() IHostFS{
+		"HostFS": func() IHostFS { return NewHostFS() },
+		"MemFS":  func() IHostFS { return NewMemFS() },
+	}
+	for implName, newFS := range impls {
+		for _, tt := range tests {
+			t.Run(implName+"/"+tt.name, func(t *testing.T) {
+				ctx, _ := rootContext(t)
+				hfs := newFS()
+				if err := hfs.WriteFile(ctx, "a.txt", []byte("old"), 0); err != nil {
+					t.Fatal(err)
+				}
+				info, err := hfs.Stat(ctx, "a.txt")
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				err = hfs.WriteFileIf(ctx, tt.path, []byte("new"), 0, tt.pre(info))
+				var conflict *ConflictError
+				switch {
+				case tt.conflict && !errors.As(err, &conflict):
+					t.Errorf("WriteFileIf(%q) = %v, want a *ConflictError", tt.path, err)
+				case tt.conflict && conflict.Path != tt.path:
+					t.Errorf("ConflictError.Path = %q, want %q", conflict.Path, tt.path)
+				case !tt.conflict && err != nil:
+					t.Errorf("WriteFileIf(%q) = %v, want the write made", tt.path, err)
+				}
+
+				want := "new"
+				if tt.conflict {
+					want = "old"
+				}
+				if data, err := hfs.ReadFile(ctx, "a.txt"); tt.path == "a.txt" && string(data) != want {
+					t.Errorf("a.txt = %q, %v, want %q", data, err, want)
+				}
+			})
+		}
+	}
+}
@@ -0,0 +1,152 @@
+package hostserve
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+)
+
+// ErrQuotaExceeded indicates a host service call was rejected because it would take the plugin past one of its
+// resource quotas.
+var ErrQuotaExceeded = errors.New("quota exceeded")
+
+// QuotaError describes a host service call that was rejected by a QuotaChecker, naming the operation, its path and
+// the resource whose limit it would have exceeded. It matches ErrQuotaExceeded with errors.Is.
+type QuotaError struct {
+	Op       string
+	Path     string
+	Resource string
+}
+
+// Error returns a description of the rejected operation and the exhausted resource.
+func (e *QuotaError) Error() string {
+	if e.Path == "" {
+		return fmt.Sprintf("%s: %s: %s", e.Op, ErrQuotaExceeded, e.Resource)
+	}
+	return fmt.Sprintf("%s %s: %s: %s", e.Op, e.Path, ErrQuotaExceeded, e.Resource)
+}
+
+// Unwrap returns ErrQuotaExceeded so callers can test for exhausted quotas with errors.Is.
+func (e *QuotaError) Unwrap() error {
+	return ErrQuotaExceeded
+}
+
+// The resources a Quota limits, as named in a QuotaError.
+const (
+	ResourceBytesRead      = "bytes read"
+	ResourceBytesWritten   = "bytes written"
+	ResourceFiles          = "files created"
+	ResourceCallsPerSecond = "calls per second"
+)
+
+// Quota limits a plugin's use of host services. A zero limit means no limit. The byte and file limits are totals over
+// the lifetime of the QuotaTracker counting them, so they survive the plugin being restarted.
+type Quota struct {
+	// BytesRead limits the bytes of file contents the plugin may read.
+	BytesRead int64
+	// BytesWritten limits the bytes the plugin may write to files, including bytes staged in transactions. Bytes
+	// staged in a transaction that is rolled back or fails to commit are refunded, as are the files they created.
+	BytesWritten int64
+	// Files limits how many files the plugin may create.
+	Files int64
+	// CallsPerSecond limits the rate of the plugin's calls, allowing bursts of up to one second's worth.
+	CallsPerSecond float64
+}
+
+// QuotaUsage is a plugin's use of host services, as counted by a QuotaTracker.
+type QuotaUsage struct {
+	BytesRead    int64
+	BytesWritten int64
+	FilesCreated int64
+	// Calls counts the calls made, including those that were rejected.
+	Calls int64
+	// Rejected counts the calls rejected for exceeding a quota.
+	Rejected int64
+}
+
+// QuotaTracker counts the use of host services by each plugin, keyed by client ID. A single tracker is shared by the
+// QuotaCheckers of every plugin so that the host can query their usage in one place.
+type QuotaTracker struct {
+	mu      sync.Mutex
+	clients map[string]*quotaState
+}
+
+// quotaState is the usage of a single plugin, along with its call rate limiter.
+type quotaState struct {
+	usage    QuotaUsage
+	tokens   float64
+	refilled time.Time
+}
+
+// NewQuotaTracker creates an empty QuotaTracker.
+func NewQuotaTracker() *QuotaTracker {
+	return &QuotaTracker{clients: make(map[string]*quotaState)}
+}
+
+// Usage returns the usage counted for the plugin identified by clientID.
+func (t *QuotaTracker) Usage(clientID string) QuotaUsage {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if s, ok := t.clients[clientID]; ok {
+		return s.usage
+	}
+	return QuotaUsage{}
+}
+
+// state returns the state of clientID, creating it if needed. It must be called with mu held.
+func (t *QuotaTracker) state(clientID string) *quotaState {
+	s, ok := t.clients[clientID]
+	if !ok {
+		s = &quotaState{}
+		t.clients[clientID] = s
+	}
+	return s
+}
+
+// call counts a call by clientID and reports whether it is within rate, in calls per second. Each client may make a
+// burst of up to one second's worth of calls, and at least one.
+func (t *QuotaTracker) call(clientID string, rate float64) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	s := t.state(clientID)
+	s.usage.Calls++
+	if rate <= 0 {
+		return true
+	}
+	burst := math.Max(1, rate)
+	now := time.Now()
+	if s.refilled.IsZero() {
+		s.tokens = burst
+	} else {
+		s.tokens = math.Min(burst, s.tokens+now.Sub(s.refilled).Seconds()*rate)
+	}
+	s.refilled = now
+	if s.tokens < 1 {
+		s.usage.Rejected++
+		return false
+	}
+	s.tokens--
+	return true
+}
+
+// charge adds n to the counter of clientID that field selects, provided that keeps it within limit, and reports
+// whether it did. A zero limit means no limit, and a negative n, refunding an earlier charge, is always applied.
+func (t *QuotaTracker) charge(clientID string, field func(*QuotaUsage) *int64, n, limit int64) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	s := t.state(clientID)
+	counter := field(&s.usage)
+	if n > 0 && limit > 0 && *counter+n > limit {
+		s.usage.Rejected++
+		return false
+	}
+	*counter = max(*counter+n, 0)
+	return true
+}
+
+// The counters a QuotaTracker charges.
+func bytesRead(u *QuotaUsage) *int64    { return &u.BytesRead }
+func bytesWritten(u *QuotaUsage) *int64 { return &u.BytesWritten }
+func filesCreated(u *QuotaUsage) *int64 { return &u.FilesCreated }
@@ -0,0 +1,545 @@
+package hostserve
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+)
+
+// QuotaChecker is an IHostServices decorator that enforces a plugin's Quota, counting its usage in a QuotaTracker
+// under the client ID of each call. Hosts create one checker per plugin around the shared host services, inside its
+// CapabilityChecker so that only permitted calls are counted.
+//
+// Writes staged in a transaction are charged as they are staged, and refunded if the transaction is rolled back or
+// its commit fails.
+type QuotaChecker struct {
+	impl    IHostServices
+	tracker *QuotaTracker
+	quota   Quota
+
+	txMu sync.Mutex
+	// txCharges holds what the writes staged in each open transaction were charged, by transaction ID.
+	txCharges map[string]writeCharge
+}
+
+// writeCharge is what one or more writes were charged against the write and file quotas.
+type writeCharge struct {
+	bytes int64
+	files int64
+}
+
+// NewQuotaChecker creates a QuotaChecker that forwards calls within quota to impl, counting them in tracker.
+func NewQuotaChecker(impl IHostServices, tracker *QuotaTracker, quota Quota) *QuotaChecker {
+	return &QuotaChecker{
+		impl:    impl,
+		tracker: tracker,
+		quota:   quota,
+	}
+}
+
+// exceed logs and returns a QuotaError for the given operation, path and resource, and marks the call as denied in
+// its audit record.
+func exceed(ctx context.Context, op, path, resource string) error {
+	hclog.Default().Warn("Quota exceeded", "clientID", ClientIDFromContext(ctx), "op", op, "path", path,
+		"resource", resource)
+	markDenied(ctx)
+	return &QuotaError{Op: op, Path: path, Resource: resource}
+}
+
+// call counts a call of op on path, rejecting it if the plugin is over its call rate.
+func (qc *QuotaChecker) call(ctx context.Context, op, path string) error {
+	if !qc.tracker.call(ClientIDFromContext(ctx), qc.quota.CallsPerSecond) {
+		return exceed(ctx, op, path, ResourceCallsPerSecond)
+	}
+	return nil
+}
+
+// reserve charges n units of resource to the plugin, rejecting op on path if that would exceed its quota.
+func (qc *QuotaChecker) reserve(ctx context.Context, op, path, resource string, n int64) error {
+	field, limit := qc.counter(resource)
+	if !qc.tracker.charge(ClientIDFromContext(ctx), field, n, limit) {
+		return exceed(ctx, op, path, resource)
+	}
+	return nil
+}
+
+// adjust charges n units of resource to the plugin without checking its quota, correcting an earlier reservation
+// once the actual amount is known. A negative n refunds.
+func (qc *QuotaChecker) adjust(ctx context.Context, resource string, n int64) {
+	field, _ := qc.counter(resource)
+	qc.tracker.charge(ClientIDFromContext(ctx), field, n, 0)
+}
+
+// counter returns the usage counter and the limit for resource.
+func (qc *QuotaChecker) counter(resource string) (func(*QuotaUsage) *int64, int64) {
+	switch resource {
+	case ResourceBytesRead:
+		return bytesRead, qc.quota.BytesRead
+	case ResourceBytesWritten:
+		return bytesWritten, qc.quota.BytesWritten
+	default:
+		return filesCreated, qc.quota.Files
+	}
+}
+
+// reserveCreate charges a file to the plugin if a write of path would create it, reporting whether it did.
+func (qc *QuotaChecker) reserveCreate(ctx context.Context, op, path string) (bool, error) {
+	if _, err := qc.impl.Lstat(ctx, path); !errors.Is(err, fs.ErrNotExist) {
+		return false, nil
+	}
+	if err := qc.reserve(ctx, op, path, ResourceFiles, 1); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// reserveWrite charges a write of n bytes to path to the plugin, along with the file if the write would create it,
+// and returns the charge.
+func (qc *QuotaChecker) reserveWrite(ctx context.Context, op, path string, n int64) (writeCharge, error) {
+	if err := qc.reserve(ctx, op, path, ResourceBytesWritten, n); err != nil {
+		return writeCharge{}, err
+	}
+	created, err := qc.reserveCreate(ctx, op, path)
+	if err != nil {
+		qc.adjust(ctx, ResourceBytesWritten, -n)
+		return writeCharge{}, err
+	}
+	c := writeCharge{bytes: n}
+	if created {
+		c.files = 1
+	}
+	return c, nil
+}
+
+// refund refunds a charge, for writes that failed or were never put in place.
+func (qc *QuotaChecker) refund(ctx context.Context, c writeCharge) {
+	qc.adjust(ctx, ResourceBytesWritten, -c.bytes)
+	qc.adjust(ctx, ResourceFiles, -c.files)
+}
+
+// staged records the charge for a write that succeeded, if it was staged in a transaction, so that it can be
+// refunded should the transaction not be committed.
+func (qc *QuotaChecker) staged(ctx context.Context, c writeCharge) {
+	txID := TxFromContext(ctx)
+	if txID == "" {
+		return
+	}
+	qc.txMu.Lock()
+	defer qc.txMu.Unlock()
+	if qc.txCharges == nil {
+		qc.txCharges = make(map[string]writeCharge)
+	}
+	total := qc.txCharges[txID]
+	qc.txCharges[txID] = writeCharge{bytes: total.bytes + c.bytes, files: total.files + c.files}
+}
+
+// endTx forgets the charges recorded for txID, returning them.
+func (qc *QuotaChecker) endTx(txID string) writeCharge {
+	qc.txMu.Lock()
+	defer qc.txMu.Unlock()
+	c := qc.txCharges[txID]
+	delete(qc.txCharges, txID)
+	return c
+}
+
+// ReadDir reads the directory if the plugin is within its call rate.
+func (qc *QuotaChecker) ReadDir(ctx context.Context, path string) ([]fs.DirEntry, error) {
+	if err := qc.call(ctx, "ReadDir", path); err != nil {
+		return nil, err
+	}
+	return qc.impl.ReadDir(ctx, path)
+}
+
+// ReadFile reads the file if the plugin is within its call rate and reading the file would not exceed its read quota.
+func (qc *QuotaChecker) ReadFile(ctx context.Context, path string) ([]byte, error) {
+	if err := qc.call(ctx, "ReadFile", path); err != nil {
+		return nil, err
+	}
+	var size int64
+	if info, err := qc.impl.Stat(ctx, path); err == nil && info.Mode().IsRegular() {
+		size = info.Size()
+	}
+	if err := qc.reserve(ctx, "ReadFile", path, ResourceBytesRead, size); err != nil {
+		return nil, err
+	}
+	data, err := qc.impl.ReadFile(ctx, path)
+	qc.adjust(ctx, ResourceBytesRead, int64(len(data))-size)
+	return data, err
+}
+
+// WriteFile writes the file if the plugin is within its call rate and the write would not exceed its write or file
+// quotas.
+func (qc *QuotaChecker) WriteFile(ctx context.Context, path string, data []byte, perm os.FileMode) error {
+	if err := qc.call(ctx, "WriteFile", path); err != nil {
+		return err
+	}
+	charge, err := qc.reserveWrite(ctx, "WriteFile", path, int64(len(data)))
+	if err != nil {
+		return err
+	}
+	if err := qc.impl.WriteFile(ctx, path, data, perm); err != nil {
+		qc.refund(ctx, charge)
+		return err
+	}
+	qc.staged(ctx, charge)
+	return nil
+}
+
+// WriteFileIf writes the file under the same quotas as WriteFile.
+func (qc *QuotaChecker) WriteFileIf(ctx context.Context, path string, data []byte, perm os.FileMode,
+	pre WritePrecondition,
+) error {
+	if err := qc.call(ctx, "WriteFileIf", path); err != nil {
+		return err
+	}
+	charge, err := qc.reserveWrite(ctx, "WriteFileIf", path, int64(len(data)))
+	if err != nil {
+		return err
+	}
+	if err := qc.impl.WriteFileIf(ctx, path, data, perm, pre); err != nil {
+		qc.refund(ctx, charge)
+		return err
+	}
+	qc.staged(ctx, charge)
+	return nil
+}
+
+// Stat returns the file info if the plugin is within its call rate.
+func (qc *QuotaChecker) Stat(ctx context.Context, path string) (fs.FileInfo, error) {
+	if err := qc.call(ctx, "Stat", path); err != nil {
+		return nil, err
+	}
+	return qc.impl.Stat(ctx, path)
+}
+
+// Lstat returns the file info if the plugin is within its call rate.
+func (qc *QuotaChecker) Lstat(ctx context.Context, path string) (fs.FileInfo, error) {
+	if err := qc.call(ctx, "Lstat", path); err != nil {
+		return nil, err
+	}
+	return qc.impl.Lstat(ctx, path)
+}
+
+// quotaReader counts the bytes read from a file against the plugin's read quota.
+type quotaReader struct {
+	io.ReadCloser
+	qc   *QuotaChecker
+	ctx  context.Context
+	path string
+}
+
+// Read reads from the file, failing instead once the plugin's read quota is exhausted.
+func (r *quotaReader) Read(p []byte) (int, error) {
+	n, err := r.ReadCloser.Read(p)
+	if n > 0 {
+		if qerr := r.qc.reserve(r.ctx, "ReadFileStream", r.path, ResourceBytesRead, int64(n)); qerr != nil {
+			return 0, qerr
+		}
+	}
+	return n, err
+}
+
+// ReadFileStream opens the file if the plugin is within its call rate. Reads fail once its read quota is exhausted.
+func (qc *QuotaChecker) ReadFileStream(ctx context.Context, path string) (io.ReadCloser, error) {
+	if err := qc.call(ctx, "ReadFileStream", path); err != nil {
+		return nil, err
+	}
+	r, err := qc.impl.ReadFileStream(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	return &quotaReader{ReadCloser: r, qc: qc, ctx: ctx, path: path}, nil
+}
+
+// ReadDirStream opens the directory if the plugin is within its call rate.
+func (qc *QuotaChecker) ReadDirStream(ctx context.Context, path string) (DirReader, error) {
+	if err := qc.call(ctx, "ReadDirStream", path); err != nil {
+		return nil, err
+	}
+	return qc.impl.ReadDirStream(ctx, path)
+}
+
+// quotaWriter counts the bytes written to a file against the plugin's write quota.
+type quotaWriter struct {
+	io.WriteCloser
+	qc      *QuotaChecker
+	ctx     context.Context
+	path    string
+	created bool
+	written int64
+}
+
+// Write writes to the file, failing instead if the write would exceed the plugin's write quota.
+func (w *quotaWriter) Write(p []byte) (int, error) {
+	if err := w.qc.reserve(w.ctx, "WriteFileStream", w.path, ResourceBytesWritten, int64(len(p))); err != nil {
+		return 0, err
+	}
+	n, err := w.WriteCloser.Write(p)
+	w.qc.adjust(w.ctx, ResourceBytesWritten, int64(n-len(p)))
+	w.written += int64(n)
+	return n, err
+}
+
+// Close closes the file, refunding its creation if it could not be put in place.
+func (w *quotaWriter) Close() error {
+	err := w.WriteCloser.Close()
+	if err != nil && w.created {
+		w.qc.adjust(w.ctx, ResourceFiles, -1)
+	}
+	if err == nil {
+		c := writeCharge{bytes: w.written}
+		if w.created {
+			c.files = 1
+		}
+		w.qc.staged(w.ctx, c)
+	}
+	return err
+}
+
+// abort abandons the file, refunding its creation.
+func (w *quotaWriter) abort() {
+	abortWriter(w.WriteCloser)
+	if w.created {
+		w.qc.adjust(w.ctx, ResourceFiles, -1)
+	}
+}
+
+// WriteFileStream opens the file if the plugin is within its call rate and its file quota. Writes fail once its write
+// quota would be exceeded.
+func (qc *QuotaChecker) WriteFileStream(ctx context.Context,
+	path string,
+	perm os.FileMode,
+) (io.WriteCloser, error) {
+	if err := qc.call(ctx, "WriteFileStream", path); err != nil {
+		return nil, err
+	}
+	created, err := qc.reserveCreate(ctx, "WriteFileStream", path)
+	if err != nil {
+		return nil, err
+	}
+	w, err := qc.impl.WriteFileStream(ctx, path, perm)
+	if err != nil {
+		if created {
+			qc.adjust(ctx, ResourceFiles, -1)
+		}
+		return nil, err
+	}
+	return &quotaWriter{WriteCloser: w, qc: qc, ctx: ctx, path: path, created: created}, nil
+}
+
+// MkdirAll creates the directory if the plugin is within its call rate.
+func (qc *QuotaChecker) MkdirAll(ctx context.Context, path string, perm os.FileMode) error {
+	if err := qc.call(ctx, "MkdirAll", path); err != nil {
+		return err
+	}
+	return qc.impl.MkdirAll(ctx, path, perm)
+}
+
+// Remove removes the path if the plugin is within its call rate.
+func (qc *QuotaChecker) Remove(ctx context.Context, path string) error {
+	if err := qc.call(ctx, "Remove", path); err != nil {
+		return err
+	}
+	return qc.impl.Remove(ctx, path)
+}
+
+// RemoveAll removes the path and its children if the plugin is within its call rate.
+func (qc *QuotaChecker) RemoveAll(ctx context.Context, path string) error {
+	if err := qc.call(ctx, "RemoveAll", path); err != nil {
+		return err
+	}
+	return qc.impl.RemoveAll(ctx, path)
+}
+
+// Rename moves the path if the plugin is within its call rate.
+func (qc *QuotaChecker) Rename(ctx context.Context, oldPath, newPath string) error {
+	if err := qc.call(ctx, "Rename", oldPath); err != nil {
+		return err
+	}
+	return qc.impl.Rename(ctx, oldPath, newPath)
+}
+
+// Copy copies the file if the plugin is within its call rate, and copying it would exceed neither its read quota nor
+// its write and file quotas.
+func (qc *QuotaChecker) Copy(ctx context.Context, src, dst string) (int64, error) {
+	if err := qc.call(ctx, "Copy", src); err != nil {
+		return 0, err
+	}
+	var size int64
+	if info, err := qc.impl.Stat(ctx, src); err == nil && info.Mode().IsRegular() {
+		size = info.Size()
+	}
+	if err := qc.reserve(ctx, "Copy", src, ResourceBytesRead, size); err != nil {
+		return 0, err
+	}
+	charge, err := qc.reserveWrite(ctx, "Copy", dst, size)
+	if err != nil {
+		qc.adjust(ctx, ResourceBytesRead, -size)
+		return 0, err
+	}
+	n, err := qc.impl.Copy(ctx, src, dst)
+	if err != nil {
+		qc.refund(ctx, charge)
+		qc.adjust(ctx, ResourceBytesWritten, n)
+	} else {
+		qc.adjust(ctx, ResourceBytesWritten, n-size)
+		qc.staged(ctx, writeCharge{bytes: n, files: charge.files})
+	}
+	qc.adjust(ctx, ResourceBytesRead, n-size)
+	return n, err
+}
+
+// Chmod changes the permissions if the plugin is within its call rate.
+func (qc *QuotaChecker) Chmod(ctx context.Context, path string, mode os.FileMode) error {
+	if err := qc.call(ctx, "Chmod", path); err != nil {
+		return err
+	}
+	return qc.impl.Chmod(ctx, path, mode)
+}
+
+// Truncate changes the size of the file if the plugin is within its call rate and any growth would not exceed its
+// write quota.
+func (qc *QuotaChecker) Truncate(ctx context.Context, path string, size int64) error {
+	if err := qc.call(ctx, "Truncate", path); err != nil {
+		return err
+	}
+	var growth int64
+	if info, err := qc.impl.Stat(ctx, path); err == nil && info.Mode().IsRegular() {
+		growth = max(size-info.Size(), 0)
+	}
+	if err := qc.reserve(ctx, "Truncate", path, ResourceBytesWritten, growth); err != nil {
+		return err
+	}
+	if err := qc.impl.Truncate(ctx, path, size); err != nil {
+		qc.adjust(ctx, ResourceBytesWritten, -growth)
+		return err
+	}
+	return nil
+}
+
+// Append appends to the file under the same quotas as WriteFile.
+func (qc *QuotaChecker) Append(ctx context.Context, path string, data []byte, perm os.FileMode) error {
+	if err := qc.call(ctx, "Append", path); err != nil {
+		return err
+	}
+	charge, err := qc.reserveWrite(ctx, "Append", path, int64(len(data)))
+	if err != nil {
+		return err
+	}
+	if err := qc.impl.Append(ctx, path, data, perm); err != nil {
+		qc.refund(ctx, charge)
+		return err
+	}
+	return nil
+}
+
+// Watch watches the path if the plugin is within its call rate. The events delivered are not counted.
+func (qc *QuotaChecker) Watch(ctx context.Context, path string, recursive bool) (<-chan WatchEvent, error) {
+	if err := qc.call(ctx, "Watch", path); err != nil {
+		return nil, err
+	}
+	return qc.impl.Watch(ctx, path, recursive)
+}
+
+// Walk walks the tree if the plugin is within its call rate. The entries delivered are not counted.
+func (qc *QuotaChecker) Walk(ctx context.Context, path string, opts WalkOptions) (<-chan WalkEntry, error) {
+	if err := qc.call(ctx, "Walk", path); err != nil {
+		return nil, err
+	}
+	return qc.impl.Walk(ctx, path, opts)
+}
+
+// Glob matches the pattern if the plugin is within its call rate.
+func (qc *QuotaChecker) Glob(ctx context.Context, pattern string) ([]string, error) {
+	if err := qc.call(ctx, "Glob", pattern); err != nil {
+		return nil, err
+	}
+	return qc.impl.Glob(ctx, pattern)
+}
+
+// BeginTx starts a transaction if the plugin is within its call rate. Each write staged in it is counted when it is
+// made.
+func (qc *QuotaChecker) BeginTx(ctx context.Context) (string, error) {
+	if err := qc.call(ctx, "BeginTx", ""); err != nil {
+		return "", err
+	}
+	return qc.impl.BeginTx(ctx)
+}
+
+// CommitTx commits the transaction if the plugin is within its call rate. A commit that fails puts none of its writes
+// in place, so what they were charged is refunded.
+func (qc *QuotaChecker) CommitTx(ctx context.Context, txID string) error {
+	if err := qc.call(ctx, "CommitTx", ""); err != nil {
+		return err
+	}
+	err := qc.impl.CommitTx(ctx, txID)
+	charge := qc.endTx(txID)
+	if err != nil {
+		qc.refund(ctx, charge)
+	}
+	return err
+}
+
+// RollbackTx rolls back the transaction, refunding what the writes staged in it were charged. It is never limited,
+// so that a plugin over quota can still clean up.
+func (qc *QuotaChecker) RollbackTx(ctx context.Context, txID string) error {
+	err := qc.impl.RollbackTx(ctx, txID)
+	qc.refund(ctx, qc.endTx(txID))
+	return err
+}
+
+// Lock acquires a lock if the plugin is within its call rate.
+func (qc *QuotaChecker) Lock(ctx context.Context, path string, mode LockMode,
+	lease time.Duration,
+) (string, time.Time, error) {
+	if err := qc.call(ctx, "Lock", path); err != nil {
+		return "", time.Time{}, err
+	}
+	return qc.impl.Lock(ctx, path, mode, lease)
+}
+
+// TryLock acquires a lock without waiting if the plugin is within its call rate.
+func (qc *QuotaChecker) TryLock(ctx context.Context, path string, mode LockMode,
+	lease time.Duration,
+) (string, time.Time, error) {
+	if err := qc.call(ctx, "TryLock", path); err != nil {
+		return "", time.Time{}, err
+	}
+	return qc.impl.TryLock(ctx, path, mode, lease)
+}
+
+// Unlock releases a lock. It is never limited, so that a plugin over quota can still clean up.
+func (qc *QuotaChecker) Unlock(ctx context.Context, lockID string) error {
+	return qc.impl.Unlock(ctx, lockID)
+}
+
+// GetEnv returns the variable if the plugin is within its call rate. As GetEnv cannot report errors, calls over the
+// rate are logged and read as unset.
+func (qc *QuotaChecker) GetEnv(ctx context.Context, key string) string {
+	if err := qc.call(ctx, "GetEnv", key); err != nil {
+		return ""
+	}
+	return qc.impl.GetEnv(ctx, key)
+}
+
+// LookupEnv returns the variable if the plugin is within its call rate.
+func (qc *QuotaChecker) LookupEnv(ctx context.Context, key string) (string, bool, error) {
+	if err := qc.call(ctx, "LookupEnv", key); err != nil {
+		return "", false, err
+	}
+	return qc.impl.LookupEnv(ctx, key)
+}
+
+// Environ lists the variables if the plugin is within its call rate.
+func (qc *QuotaChecker) Environ(ctx context.Context) ([]string, error) {
+	if err := qc.call(ctx, "Environ", ""); err != nil {
+		return nil, err
+	}
+	return qc.impl.Environ(ctx)
+}
@@ -0,0 +1,113 @@
+package hostserve
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestGRPCClientReportsQuotaExceeded(t *testing.T) {
+	tests := []struct {
+		name  string
+		quota Quota
+		// first is written before the write that exceeds the quota
+		first, second string
+	}{
+		{name: "bytes written", quota: Quota{BytesWritten: 10}, first: "a.txt", second: "a.txt"},
+		{name: "files created", quota: Quota{Files: 1}, first: "a.txt", second: "b.txt"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			tracker := NewQuotaTracker()
+			impl := NewQuotaChecker(NewHostServices(NewHostFS(), NewHostEnv()), tracker, tt.quota)
+			client := grpcClient(t, impl, dir)
+			ctx := context.Background()
+
+			if err := client.WriteFile(ctx, tt.first, []byte("first"), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			err := client.WriteFile(ctx, tt.second, []byte("second"), 0o644)
+			if !errors.Is(err, ErrQuotaExceeded) {
+				t.Fatalf("WriteFile = %v, want ErrQuotaExceeded", err)
+			}
+			var hostErr *HostServiceError
+			if !errors.As(err, &hostErr) || hostErr.Op != "WriteFile" || hostErr.Path != tt.second {
+				t.Errorf("WriteFile = %#v, want a HostServiceError for WriteFile %s", err, tt.second)
+			}
+			if data, err := os.ReadFile(filepath.Join(dir, tt.first)); string(data) != "first" {
+				t.Errorf("%s = %q, %v after rejected write, want %q", tt.first, data, err, "first")
+			}
+			if usage := tracker.Usage("test"); usage.Rejected != 1 {
+				t.Errorf("usage counts %d rejected calls, want 1", usage.Rejected)
+			}
+		})
+	}
+}
+
+func TestQuotaCheckerRefundsRolledBackTx(t *testing.T) {
+	ctx, dir := rootContext(t)
+	tracker := NewQuotaTracker()
+	qc := NewQuotaChecker(NewHostServices(NewHostFS(), NewHostEnv()), tracker, Quota{BytesWritten: 10, Files: 2})
+
+	txID, err := qc.BeginTx(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	txCtx := WithTx(ctx, txID)
+	if err := qc.WriteFile(txCtx, "a.txt", []byte("12345678"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := qc.WriteFile(txCtx, "b.txt", []byte("123"), 0o644); !errors.Is(err, ErrQuotaExceeded) {
+		t.Fatalf("WriteFile past the staged bytes = %v, want ErrQuotaExceeded", err)
+	}
+	if err := qc.RollbackTx(ctx, txID); err != nil {
+		t.Fatal(err)
+	}
+	if usage := tracker.Usage("test"); usage.BytesWritten != 0 || usage.FilesCreated != 0 {
+		t.Errorf("usage after rollback = %d bytes, %d files, want none", usage.BytesWritten, usage.FilesCreated)
+	}
+
+	if err := qc.WriteFile(ctx, "a.txt", []byte("12345678"), 0o644); err != nil {
+		t.Fatalf("WriteFile after rollback = %v", err)
+	}
+	if got, err := readString(dir, "a.txt"); got != "12345678" {
+		t.Errorf("a.txt = %q, %v, want %q", got, err, "12345678")
+	}
+}
+
+func TestQuotaCheckerKeepsCommittedTxCharges(t *testing.T) {
+	ctx, _ := rootContext(t)
+	tracker := NewQuotaTracker()
+	qc := NewQuotaChecker(NewHostServices(NewHostFS(), NewHostEnv()), tracker, Quota{})
+
+	txID, err := qc.BeginTx(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	txCtx := WithTx(ctx, txID)
+	if err := qc.WriteFile(txCtx, "a.txt", []byte("abc"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	w, err := qc.WriteFileStream(txCtx, "b.txt", 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := w.Write([]byte("defg")); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := qc.CommitTx(ctx, txID); err != nil {
+		t.Fatal(err)
+	}
+	// a late rollback of the committed transaction must not refund what it wrote
+	_ = qc.RollbackTx(ctx, txID)
+	if usage := tracker.Usage("test"); usage.BytesWritten != 7 || usage.FilesCreated != 2 {
+		t.Errorf("usage after commit = %d bytes, %d files, want 7 bytes, 2 files", usage.BytesWritten,
+			usage.FilesCreated)
+	}
+}
@@ -0,0 +1,51 @@
+package hostserve
+
+import (
+	"context"
+	"errors"
+
+	"github.com/hashicorp/go-hclog"
+)
+
+// ErrTxNotFound is returned for a transaction that is not open, or that was begun by another plugin or under another
+// root.
+var ErrTxNotFound = errors.New("transaction not found")
+
+// txKey is the context key used to carry the transaction a call's writes are staged in.
+type txKey struct{}
+
+// WithTx returns a copy of ctx whose writes are staged in the transaction txID until it is committed. WriteFile,
+// WriteFileIf, WriteFileStream and the destination of Copy are staged; every other call made with ctx takes effect
+// immediately, and reads do not see staged writes.
+func WithTx(ctx context.Context, txID string) context.Context {
+	return context.WithValue(ctx, txKey{}, txID)
+}
+
+// TxFromContext returns the transaction the current call's writes are staged in, or an empty string if there is none.
+func TxFromContext(ctx context.Context) string {
+	txID, _ := ctx.Value(txKey{}).(string)
+	return txID
+}
+
+// InTx runs fn in a new transaction on host, passing it a context whose writes are staged in the transaction. The
+// transaction is committed if fn succeeds and rolled back if it returns an error or panics.
+func InTx(ctx context.Context, host IHostFS, fn func(ctx context.Context) error) (err error) {
+	txID, err := host.BeginTx(ctx)
+	if err != nil {
+		return err
+	}
+	committed := false
+	defer func() {
+		if committed {
+			return
+		}
+		if rbErr := host.RollbackTx(ctx, txID); rbErr != nil {
+			hclog.Default().Error("Failed to roll back transaction", "tx", txID, "err", rbErr)
+		}
+	}()
+	if err := fn(WithTx(ctx, txID)); err != nil {
+		return err
+	}
+	committed = true
+	return host.CommitTx(ctx, txID)
+}
@@ -0,0 +1,144 @@
+package hostserve
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/bmatcuk/doublestar/v4"
+)
+
+// ErrWalkCycle is reported on a directory reached through a symbolic link while it is already being walked. The walk
+// does not descend into it again.
+var ErrWalkCycle = errors.New("directory cycle")
+
+// WalkOptions controls which entries a Walk visits.
+type WalkOptions struct {
+	// MaxDepth limits how far below the walked path entries are reported; the walked path itself has depth 0 and
+	// its children depth 1. Zero or less walks the whole tree.
+	MaxDepth int
+	// FollowSymlinks descends into symbolic links to directories. Links are still resolved within the root, and a
+	// directory already being walked is not entered again.
+	FollowSymlinks bool
+	// Skip holds doublestar patterns for entries to leave out, along with everything beneath them. A pattern
+	// without a "/" is matched against the entry's name, so "node_modules" skips every directory of that name;
+	// other patterns are matched against the entry's slash-separated path relative to the walked path.
+	Skip []string
+}
+
+// WalkEntry is a single path visited by a Walk. Paths are reported in the same form the walk was requested in, so
+// a walk of "src" reports "src/main.go". Info describes the entry itself, or the target of a followed symbolic link.
+//
+// An entry with Err set and a path could not be fully visited, for example a directory that could not be read, and
+// the walk carries on past it. If the walk itself fails, the final entry carries the error and has no path.
+type WalkEntry struct {
+	Path  string
+	Depth int
+	Info  fs.FileInfo
+	Err   error
+}
+
+// skipped reports whether the entry at rel, a slash-separated path relative to the walked path, matches any of the
+// skip patterns.
+func (o *WalkOptions) skipped(rel string) bool {
+	for _, pattern := range o.Skip {
+		target := rel
+		if !strings.Contains(pattern, "/") {
+			target = path.Base(rel)
+		}
+		if ok, _ := doublestar.Match(pattern, target); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// validate reports an error if any skip pattern is malformed.
+func (o *WalkOptions) validate() error {
+	for _, pattern := range o.Skip {
+		if !doublestar.ValidatePattern(pattern) {
+			return &fs.PathError{Op: "walk", Path: pattern, Err: doublestar.ErrBadPattern}
+		}
+	}
+	return nil
+}
+
+// splitGlob splits a glob pattern into the directory before its first wildcard, in the host's path form, and the
+// slash-separated pattern to match beneath it.
+func splitGlob(pattern string) (string, string) {
+	base, rest := doublestar.SplitPattern(filepath.ToSlash(pattern))
+	return filepath.FromSlash(base), rest
+}
+
+// walkFS implements Walk for backends other than HostFS on top of their Lstat, Stat and ReadDir methods, with the
+// same ordering, options and error reporting as HostFS.Walk.
+func walkFS(ctx context.Context, host IHostFS, root string, opts WalkOptions) (<-chan WalkEntry, error) {
+	if err := opts.validate(); err != nil {
+		return nil, err
+	}
+	info, err := host.Lstat(ctx, root)
+	if err != nil {
+		return nil, err
+	}
+	entries := make(chan WalkEntry)
+	send := func(e WalkEntry) bool {
+		select {
+		case entries <- e:
+			return true
+		case <-ctx.Done():
+			return false
+		}
+	}
+	var ancestors []fs.FileInfo
+	var visit func(p, rel string, depth int, info fs.FileInfo) bool
+	visit = func(p, rel string, depth int, info fs.FileInfo) bool {
+		if info.Mode()&fs.ModeSymlink != 0 && opts.FollowSymlinks {
+			target, err := host.Stat(ctx, p)
+			if err != nil {
+				return send(WalkEntry{Path: p, Depth: depth, Info: info, Err: err})
+			}
+			info = target
+		}
+		if !info.IsDir() || (opts.MaxDepth > 0 && depth >= opts.MaxDepth) {
+			return send(WalkEntry{Path: p, Depth: depth, Info: info})
+		}
+		for _, ancestor := range ancestors {
+			if os.SameFile(ancestor, info) {
+				return send(WalkEntry{Path: p, Depth: depth, Info: info, Err: ErrWalkCycle})
+			}
+		}
+		children, err := host.ReadDir(ctx, p)
+		if !send(WalkEntry{Path: p, Depth: depth, Info: info, Err: err}) {
+			return false
+		}
+		ancestors = append(ancestors, info)
+		defer func() { ancestors = ancestors[:len(ancestors)-1] }()
+		for _, child := range children {
+			childRel := path.Join(rel, child.Name())
+			if opts.skipped(childRel) {
+				continue
+			}
+			childPath := filepath.Join(p, child.Name())
+			childInfo, err := child.Info()
+			if err != nil {
+				if !send(WalkEntry{Path: childPath, Depth: depth + 1, Err: err}) {
+					return false
+				}
+				continue
+			}
+			if !visit(childPath, childRel, depth+1, childInfo) {
+				return false
+			}
+		}
+		return true
+	}
+	go func() {
+		defer close(entries)
+		visit(root, ".", 0, info)
+	}()
+	return entries, nil
+}
@@ -0,0 +1,263 @@
+package hostserve
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+)
+
+// walkImpls returns the implementations Walk and Glob are tested against, each serving a root seeded by seedTree.
+func walkImpls() map[string]func(t *testing.T) (context.Context, IHostFS, string) {
+	return map[string]func(t *testing.T) (context.Context, IHostFS, string){
+		"HostFS": func(t *testing.T) (context.Context, IHostFS, string) {
+			ctx, dir := rootContext(t)
+			seedTree(t, dir)
+			return ctx, NewHostFS(), dir
+		},
+		"GRPCClient": func(t *testing.T) (context.Context, IHostFS, string) {
+			dir := t.TempDir()
+			seedTree(t, dir)
+			return context.Background(), grpcClient(t, NewHostServices(NewHostFS(), NewHostEnv()), dir), dir
+		},
+	}
+}
+
+// collect drains a walk, returning the path of every entry and the entries themselves.
+func collect(t *testing.T, entries <-chan WalkEntry) ([]string, []WalkEntry) {
+	t.Helper()
+	var paths []string
+	var all []WalkEntry
+	for e := range entries {
+		paths = append(paths, filepath.ToSlash(e.Path))
+		all = append(all, e)
+	}
+	return paths, all
+}
+
+func TestWalkOptions(t *testing.T) {
+	tests := []struct {
+		name string
+		path string
+		opts WalkOptions
+		want []string
+	}{
+		{name: "whole tree", path: ".", want: []string{".", "README.md", "docs", "docs/a", "docs/a/b",
+			"docs/a/b/c.txt", "empty", "emptydir", "main-link.go", "src", "src/main.go", "src/util",
+			"src/util/strings.go"}},
+		{name: "max depth", path: ".", opts: WalkOptions{MaxDepth: 1}, want: []string{".", "README.md", "docs",
+			"empty", "emptydir", "main-link.go", "src"}},
+		{name: "max depth below path", path: "docs", opts: WalkOptions{MaxDepth: 2},
+			want: []string{"docs", "docs/a", "docs/a/b"}},
+		{name: "skip by name", path: ".", opts: WalkOptions{Skip: []string{"util", "docs", "*.md"}},
+			want: []string{".", "empty", "emptydir", "main-link.go", "src", "src/main.go"}},
+		{name: "skip by path", path: "docs", opts: WalkOptions{Skip: []string{"a/b"}},
+			want: []string{"docs", "docs/a"}},
+		{name: "file", path: "src/main.go", want: []string{"src/main.go"}},
+	}
+	for implName, setup := range walkImpls() {
+		for _, tt := range tests {
+			t.Run(implName+"/"+tt.name, func(t *testing.T) {
+				ctx, hfs, _ := setup(t)
+				entries, err := hfs.Walk(ctx, tt.path, tt.opts)
+				if err != nil {
+					t.Fatal(err)
+				}
+				paths, all := collect(t, entries)
+				if !slices.Equal(paths, tt.want) {
+					t.Errorf("Walk(%s) = %v, want %v", tt.path, paths, tt.want)
+				}
+				for _, e := range all {
+					if e.Err != nil {
+						t.Errorf("Walk reported %s with error %v", e.Path, e.Err)
+					}
+				}
+			})
+		}
+	}
+}
+
+func TestWalkDeliversEntryErrors(t *testing.T) {
+	for implName, setup := range walkImpls() {
+		t.Run(implName, func(t *testing.T) {
+			ctx, hfs, dir := setup(t)
+			// loop/back leads to the walked directory itself, and broken to nothing at all
+			if err := os.Mkdir(filepath.Join(dir, "loop"), 0o755); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.Symlink("..", filepath.Join(dir, "loop", "back")); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.Symlink("missing", filepath.Join(dir, "broken")); err != nil {
+				t.Fatal(err)
+			}
+			entries, err := hfs.Walk(ctx, ".", WalkOptions{FollowSymlinks: true, Skip: []string{"docs", "src"}})
+			if err != nil {
+				t.Fatal(err)
+			}
+			paths, all := collect(t, entries)
+			errs := make(map[string]error)
+			for _, e := range all {
+				if e.Err != nil {
+					errs[filepath.ToSlash(e.Path)] = e.Err
+				}
+			}
+			if !errors.Is(errs["loop/back"], ErrWalkCycle) {
+				t.Errorf("loop/back reported with %v, want ErrWalkCycle", errs["loop/back"])
+			}
+			if !errors.Is(errs["broken"], fs.ErrNotExist) {
+				t.Errorf("broken reported with %v, want fs.ErrNotExist", errs["broken"])
+			}
+			if len(errs) != 2 {
+				t.Errorf("errors reported for %v, want only loop/back and broken", errs)
+			}
+			// The walk carries on past both, and the followed link to a file is reported as one
+			for _, p := range []string{"main-link.go", "README.md", "loop"} {
+				if !slices.Contains(paths, p) {
+					t.Errorf("Walk did not report %s after the errors: %v", p, paths)
+				}
+			}
+		})
+	}
+}
+
+func TestWalkStopsWhenCancelled(t *testing.T) {
+	for implName, setup := range walkImpls() {
+		t.Run(implName, func(t *testing.T) {
+			ctx, hfs, _ := setup(t)
+			ctx, cancel := context.WithCancel(ctx)
+			entries, err := hfs.Walk(ctx, ".", WalkOptions{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			<-entries
+			cancel()
+			done := make(chan struct{})
+			go func() {
+				for range entries {
+				}
+				close(done)
+			}()
+			select {
+			case <-done:
+			case <-time.After(5 * time.Second):
+				t.Fatal("walk channel was not closed after cancelling")
+			}
+		})
+	}
+}
+
+func TestWalkDirSkips(t *testing.T) {
+	ctx, dir := rootContext(t)
+	seedTree(t, dir)
+	fsys := NewFS(ctx, NewHostFS(), ".")
+	tests := []struct {
+		name string
+		// at is the path fs.SkipDir is returned for, or fs.SkipAll if all is set
+		at   string
+		all  bool
+		want []string
+	}{
+		{name: "skip directory", at: "docs", want: []string{".", "README.md", "docs", "empty",
+			"emptydir", "main-link.go", "src", "src/main.go", "src/util", "src/util/strings.go"}},
+		{name: "skip rest of directory", at: "src/main.go",
+			want: []string{".", "README.md", "docs", "docs/a", "docs/a/b", "docs/a/b/c.txt", "empty", "emptydir",
+				"main-link.go", "src", "src/main.go"}},
+		{name: "skip all", at: "empty", all: true, want: []string{".", "README.md", "docs", "docs/a", "docs/a/b",
+			"docs/a/b/c.txt", "empty"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var walked []string
+			err := fs.WalkDir(fsys, ".", func(p string, _ fs.DirEntry, err error) error {
+				if err != nil {
+					return err
+				}
+				walked = append(walked, p)
+				if p != tt.at {
+					return nil
+				}
+				if tt.all {
+					return fs.SkipAll
+				}
+				return fs.SkipDir
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !slices.Equal(walked, tt.want) {
+				t.Errorf("WalkDir = %v, want %v", walked, tt.want)
+			}
+		})
+	}
+}
+
+func TestGlob(t *testing.T) {
+	tests := []struct {
+		pattern string
+		want    []string
+	}{
+		{pattern: "*.md", want: []string{"README.md"}},
+		{pattern: "src/**/*.go", want: []string{"src/main.go", "src/util/strings.go"}},
+		{pattern: "**/c.txt", want: []string{"docs/a/b/c.txt"}},
+		{pattern: "missing/*", want: []string{}},
+	}
+	for implName, setup := range walkImpls() {
+		for _, tt := range tests {
+			t.Run(implName+"/"+tt.pattern, func(t *testing.T) {
+				ctx, hfs, _ := setup(t)
+				matches, err := hfs.Glob(ctx, tt.pattern)
+				if err != nil {
+					t.Fatal(err)
+				}
+				got := make([]string, 0, len(matches))
+				for _, m := range matches {
+					got = append(got, filepath.ToSlash(m))
+				}
+				slices.Sort(got)
+				if !slices.Equal(got, tt.want) {
+					t.Errorf("Glob(%s) = %v, want %v", tt.pattern, got, tt.want)
+				}
+			})
+		}
+	}
+	ctx, dir := rootContext(t)
+	seedTree(t, dir)
+	if _, err := NewHostFS().Glob(ctx, "src/[*.go"); err == nil {
+		t.Error("Glob with a malformed pattern succeeded")
+	}
+}
+
+func TestCapabilityCheckerWalkAndGlobDenials(t *testing.T) {
+	ctx, cc := checkedMemFS(t, []string{"read:pub/**", "read:pub"}, nil, func(ctx context.Context, m *MemFS) {
+		for _, file := range []string{"pub/a.txt", "pub/secret/key", "priv/c.txt"} {
+			if err := m.MkdirAll(ctx, filepath.Dir(file), 0); err != nil {
+				t.Fatal(err)
+			}
+			if err := m.WriteFile(ctx, file, nil, 0); err != nil {
+				t.Fatal(err)
+			}
+		}
+	})
+	if _, err := cc.Walk(ctx, "priv", WalkOptions{}); !errors.Is(err, ErrAccessDenied) {
+		t.Errorf("Walk(priv) = %v, want ErrAccessDenied", err)
+	}
+	if _, err := cc.Walk(ctx, ".", WalkOptions{}); !errors.Is(err, ErrAccessDenied) {
+		t.Errorf("Walk(.) = %v, want ErrAccessDenied", err)
+	}
+	if _, err := cc.Glob(ctx, "priv/*"); !errors.Is(err, ErrAccessDenied) {
+		t.Errorf("Glob(priv/*) = %v, want ErrAccessDenied", err)
+	}
+	entries, err := cc.Walk(ctx, "pub", WalkOptions{MaxDepth: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	paths, _ := collect(t, entries)
+	if want := []string{"pub", "pub/a.txt", "pub/secret"}; !slices.Equal(paths, want) {
+		t.Errorf("Walk(pub) = %v, want %v", paths, want)
+	}
+}
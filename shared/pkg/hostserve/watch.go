@@ -0,0 +1,70 @@
+package hostserve
+
+import (
+	"errors"
+	"strings"
+	"time"
+)
+
+// WatchCoalesceWindow is how long a watcher collects changes before reporting them. Every change to the same path
+// within the window is merged into a single WatchEvent.
+const WatchCoalesceWindow = 100 * time.Millisecond
+
+// ErrWatchUnsupported is returned by Watch on platforms without a filesystem notification backend.
+var ErrWatchUnsupported = errors.New("watch not supported on this platform")
+
+// WatchOp is a set of changes observed on a path.
+type WatchOp uint32
+
+const (
+	// WatchCreate reports that the path was created or moved into the watched tree.
+	WatchCreate WatchOp = 1 << iota
+	// WatchWrite reports that the contents of the path were modified.
+	WatchWrite
+	// WatchRemove reports that the path was removed.
+	WatchRemove
+	// WatchRename reports that the path was moved away.
+	WatchRename
+	// WatchChmod reports that the metadata of the path changed.
+	WatchChmod
+	// WatchOverflow reports that the host dropped events and the watched tree should be rescanned.
+	WatchOverflow
+)
+
+// watchOpNames lists the name of every WatchOp bit in order.
+var watchOpNames = []struct {
+	op   WatchOp
+	name string
+}{
+	{WatchCreate, "CREATE"},
+	{WatchWrite, "WRITE"},
+	{WatchRemove, "REMOVE"},
+	{WatchRename, "RENAME"},
+	{WatchChmod, "CHMOD"},
+	{WatchOverflow, "OVERFLOW"},
+}
+
+// Has reports whether op includes every bit of other.
+func (op WatchOp) Has(other WatchOp) bool {
+	return op&other == other
+}
+
+// String returns the names of the set bits joined with "|", e.g. "CREATE|WRITE".
+func (op WatchOp) String() string {
+	var names []string
+	for _, n := range watchOpNames {
+		if op.Has(n.op) {
+			names = append(names, n.name)
+		}
+	}
+	return strings.Join(names, "|")
+}
+
+// WatchEvent is a change to a path beneath a watched directory. Paths are reported in the same form the watch was
+// requested in, so a watch on "src" reports "src/main.go". If watching stops because of an error, the final event
+// carries it in Err and has no path.
+type WatchEvent struct {
+	Path string
+	Op   WatchOp
+	Err  error
+}
@@ -0,0 +1,120 @@
+package manifest
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+)
+
+// FileName is the conventional name of a plugin manifest within its plugin directory.
+const FileName = "manifest.yaml"
+
+var (
+	// ErrMissingName indicates a manifest did not declare the plugin name.
+	ErrMissingName = errors.New("manifest: missing name")
+	// ErrInvalidPlugin indicates a manifest declared a plugin without a name or type.
+	ErrInvalidPlugin = errors.New("manifest: plugin requires a name and type")
+)
+
+// Manifest describes a plugin and the host resources it declares it needs.
+//
+// Root is the directory the plugin's filesystem access is confined to. Relative roots are resolved against the
+// host's working directory; an empty root means the working directory itself.
+//
+// Command is the plugin binary, relative to the directory holding the manifest; it defaults to the plugin name.
+// Plugins lists the plugins the binary serves, each dispensed by name and handled by the host as the given type.
+//
+// Capabilities are strings of the form "<kind>:<pattern>", for example:
+//
+//	capabilities:
+//	  - read:config/**
+//	  - write:output/**
+//	  - env:API_KEY
+//
+// Env, when set, gives the plugin a virtual environment holding only the listed variables in place of the host's.
+// Values are expanded against the host environment, so "PATH: $PATH" passes a host variable through. The plugin
+// still needs an env capability to read each of them.
+//
+// Quota, when set, limits each plugin the binary serves. Omitted or zero limits are unlimited:
+//
+//	quota:
+//	  bytes_read: 1073741824
+//	  bytes_written: 104857600
+//	  files: 1000
+//	  calls_per_second: 50
+type Manifest struct {
+	Name         string            `yaml:"name"`
+	Version      string            `yaml:"version"`
+	Command      string            `yaml:"command"`
+	Root         string            `yaml:"root"`
+	Capabilities []string          `yaml:"capabilities"`
+	Env          map[string]string `yaml:"env"`
+	Quota        *Quota            `yaml:"quota"`
+	Plugins      []Plugin          `yaml:"plugins"`
+
+	// Dir is the directory the manifest was loaded from. It is set by Load.
+	Dir string `yaml:"-"`
+}
+
+// Plugin is a single plugin served by a plugin binary.
+type Plugin struct {
+	Name string `yaml:"name"`
+	Type string `yaml:"type"`
+}
+
+// Quota limits a plugin's use of host services. The byte and file limits are totals kept by the host across restarts
+// of the plugin, less what was written in transactions that were rolled back; CallsPerSecond limits its call rate.
+type Quota struct {
+	BytesRead      int64   `yaml:"bytes_read"`
+	BytesWritten   int64   `yaml:"bytes_written"`
+	Files          int64   `yaml:"files"`
+	CallsPerSecond float64 `yaml:"calls_per_second"`
+}
+
+// CommandPath returns the path of the plugin binary.
+func (m *Manifest) CommandPath() string {
+	if filepath.IsAbs(m.Command) {
+		return m.Command
+	}
+	return filepath.Join(m.Dir, m.Command)
+}
+
+// Parse decodes a manifest from YAML data and validates its required fields.
+func Parse(data []byte) (*Manifest, error) {
+	var m Manifest
+	if err := yaml.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("manifest: %w", err)
+	}
+	if m.Name == "" {
+		return nil, ErrMissingName
+	}
+	if m.Root == "" {
+		m.Root = "."
+	}
+	if m.Command == "" {
+		m.Command = m.Name
+	}
+	for _, p := range m.Plugins {
+		if p.Name == "" || p.Type == "" {
+			return nil, ErrInvalidPlugin
+		}
+	}
+	return &m, nil
+}
+
+// Load reads and parses the manifest at the given path.
+func Load(path string) (*Manifest, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	m, err := Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	m.Dir = filepath.Dir(path)
+	return m, nil
+}
@@ -0,0 +1,99 @@
+package manifest
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name        string
+		data        string
+		wantErr     error
+		wantRoot    string
+		wantCommand string
+	}{
+		{name: "defaults", data: "name: lister\n", wantRoot: ".", wantCommand: "lister"},
+		{name: "explicit", data: "name: lister\nroot: data\ncommand: bin/lister\n", wantRoot: "data",
+			wantCommand: "bin/lister"},
+		{name: "missing name", data: "root: data\n", wantErr: ErrMissingName},
+		{name: "plugin without type", data: "name: lister\nplugins:\n  - name: files\n", wantErr: ErrInvalidPlugin},
+		{name: "plugin without name", data: "name: lister\nplugins:\n  - type: filelister\n",
+			wantErr: ErrInvalidPlugin},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := Parse([]byte(tt.data))
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("Parse = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if m.Root != tt.wantRoot || m.Command != tt.wantCommand {
+				t.Errorf("Parse gave root %q and command %q, want %q and %q", m.Root, m.Command, tt.wantRoot,
+					tt.wantCommand)
+			}
+		})
+	}
+	if _, err := Parse([]byte("name: [")); err == nil {
+		t.Error("Parse of malformed YAML succeeded")
+	}
+}
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, FileName)
+	data := "name: lister\nplugins:\n  - name: files\n    type: filelister\n"
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	m, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.Dir != dir || len(m.Plugins) != 1 || m.Plugins[0] != (Plugin{Name: "files", Type: "filelister"}) {
+		t.Errorf("Load = %+v", m)
+	}
+
+	bad := filepath.Join(dir, "bad.yaml")
+	if err := os.WriteFile(bad, []byte("root: data\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(bad); !errors.Is(err, ErrMissingName) {
+		t.Errorf("Load(bad.yaml) = %v, want ErrMissingName", err)
+	}
+	if _, err := Load(filepath.Join(dir, "missing.yaml")); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("Load(missing.yaml) = %v, want os.ErrNotExist", err)
+	}
+}
+
+func TestCommandPath(t *testing.T) {
+	abs, err := filepath.Abs(filepath.Join("bin", "lister"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name    string
+		command string
+		want    string
+	}{
+		{name: "relative", command: "lister", want: filepath.Join("plugins", "lister", "lister")},
+		{name: "relative subdirectory", command: "bin/lister",
+			want: filepath.Join("plugins", "lister", "bin", "lister")},
+		{name: "absolute", command: abs, want: abs},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := &Manifest{Command: tt.command, Dir: filepath.Join("plugins", "lister")}
+			if got := m.CommandPath(); got != tt.want {
+				t.Errorf("CommandPath() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,91 @@
+package pluginmgr
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/bmj2728/hst/shared/pkg/manifest"
+)
+
+var (
+	// ErrUnavailable indicates a plugin's process has exited and has not been restarted yet.
+	ErrUnavailable = errors.New("pluginmgr: plugin unavailable")
+	// ErrWrongType indicates a plugin does not implement the interface it was called through.
+	ErrWrongType = errors.New("pluginmgr: plugin has the wrong type")
+)
+
+// Handle is a stable reference to a plugin in the Manager's registry. When the plugin's process is restarted, the
+// new instance is swapped in behind the handle, so callers should keep the handle rather than the dispensed
+// client and reach the plugin through Use or Call.
+type Handle struct {
+	// Name is the name the plugin is dispensed by and the identity it is assigned for host services.
+	Name string
+	// Type is the plugin type declared in the manifest.
+	Type string
+
+	proc *process
+
+	mu   sync.RWMutex
+	inst *instance
+}
+
+// Manifest returns the manifest of the binary currently serving the plugin.
+func (h *Handle) Manifest() *manifest.Manifest {
+	return h.proc.manifest()
+}
+
+// Raw returns the dispensed plugin client of the current instance, or nil if the plugin is unavailable. The
+// client stops working once its instance is replaced, so it should not be retained.
+func (h *Handle) Raw() interface{} {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	if h.inst == nil {
+		return nil
+	}
+	return h.inst.raws[h.Name]
+}
+
+// Use calls fn with the dispensed plugin client of the current instance. The instance is not retired until fn
+// returns. Use returns ErrUnavailable if the plugin's process has exited and not yet been restarted.
+func (h *Handle) Use(fn func(raw interface{}) error) error {
+	h.mu.RLock()
+	inst := h.inst
+	if inst == nil {
+		h.mu.RUnlock()
+		return fmt.Errorf("%w: %s", ErrUnavailable, h.Name)
+	}
+	inst.calls.Add(1)
+	h.mu.RUnlock()
+	defer inst.calls.Done()
+
+	return fn(inst.raws[h.Name])
+}
+
+// set swaps inst in behind the handle, or marks the plugin unavailable if inst is nil.
+func (h *Handle) set(inst *instance) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.inst = inst
+}
+
+// Call calls fn with the plugin behind h as a T, for example:
+//
+//	entries, err := pluginmgr.Call(h, func(fl filelister.FileLister) ([]filelister.FileListEntry, error) {
+//		return fl.ListFiles(ctx, ".")
+//	})
+//
+// It returns ErrWrongType if the plugin does not implement T.
+func Call[T any, R any](h *Handle, fn func(T) (R, error)) (R, error) {
+	var result R
+	err := h.Use(func(raw interface{}) error {
+		p, ok := raw.(T)
+		if !ok {
+			return fmt.Errorf("%w: %s is %T", ErrWrongType, h.Name, raw)
+		}
+		var err error
+		result, err = fn(p)
+		return err
+	})
+	return result, err
+}
@@ -0,0 +1,316 @@
+package pluginmgr
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/bmj2728/hst/shared/pkg/audit"
+	"github.com/bmj2728/hst/shared/pkg/hostconn"
+	"github.com/bmj2728/hst/shared/pkg/hostserve"
+	"github.com/bmj2728/hst/shared/pkg/manifest"
+	"github.com/bmj2728/hst/shared/pkg/sdk"
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/go-plugin"
+)
+
+var (
+	// ErrNoPlugins indicates a manifest did not declare any plugins to dispense.
+	ErrNoPlugins = errors.New("pluginmgr: manifest declares no plugins")
+	// ErrUnknownType indicates a manifest declared a plugin of a type the host has not registered.
+	ErrUnknownType = errors.New("pluginmgr: unknown plugin type")
+	// ErrDuplicateName indicates two plugins were declared with the same name, by one manifest or by two.
+	ErrDuplicateName = errors.New("pluginmgr: duplicate plugin name")
+)
+
+// Config configures a Manager.
+type Config struct {
+	// Dir is the plugins directory. Each plugin lives in its own subdirectory alongside its manifest.
+	Dir string
+	// Types maps the plugin types manifests may declare to the plugin.Plugin that handles them on the host,
+	// e.g. "filelister" to &filelister.FileListerGRPCPlugin{}.
+	Types map[string]plugin.Plugin
+	// HostServices are shared by every plugin, each wrapped in checkers for the quota and capabilities its manifest
+	// declares.
+	HostServices hostserve.IHostServices
+	// Audit, if set, records every host service call made by the plugins.
+	Audit audit.Sink
+	// Logger receives the manager's and plugins' logs. It defaults to hclog.Default().
+	Logger hclog.Logger
+
+	// HealthInterval is how often each plugin's process is checked for having exited. It defaults to
+	// DefaultHealthInterval.
+	HealthInterval time.Duration
+	// RestartBackoff is the delay before restarting a plugin whose process exited. It doubles with each consecutive
+	// restart up to MaxRestartBackoff. It defaults to DefaultRestartBackoff.
+	RestartBackoff time.Duration
+	// MaxRestartBackoff caps the restart delay. It defaults to DefaultMaxRestartBackoff.
+	MaxRestartBackoff time.Duration
+	// MaxRestarts is the number of consecutive restarts attempted before a plugin is left unavailable. Zero means
+	// no limit.
+	MaxRestarts int
+
+	// Reload enables hot reload. Each plugin's binary and manifest are checked every HealthInterval, and once a
+	// change has settled the plugin is relaunched from them and swapped in behind its handles.
+	Reload bool
+	// DrainTimeout is how long calls in flight on a replaced instance may run before it is stopped regardless.
+	// It defaults to DefaultDrainTimeout.
+	DrainTimeout time.Duration
+}
+
+// Manager discovers plugins from their manifests, launches their binaries, dispenses the plugins they declare and
+// connects each to host services. Dispensed plugins are kept in a registry of stable handles keyed by plugin name,
+// and each binary is supervised so that it is restarted if its process exits.
+type Manager struct {
+	cfg    Config
+	logger hclog.Logger
+
+	// quotas counts every plugin's use of host services against the quota its manifest declares
+	quotas *hostserve.QuotaTracker
+
+	mu        sync.RWMutex
+	handles   map[string]*Handle
+	processes []*process
+}
+
+// New creates a Manager with the given configuration.
+func New(cfg Config) *Manager {
+	if cfg.Logger == nil {
+		cfg.Logger = hclog.Default()
+	}
+	if cfg.HealthInterval <= 0 {
+		cfg.HealthInterval = DefaultHealthInterval
+	}
+	if cfg.RestartBackoff <= 0 {
+		cfg.RestartBackoff = DefaultRestartBackoff
+	}
+	if cfg.MaxRestartBackoff <= 0 {
+		cfg.MaxRestartBackoff = DefaultMaxRestartBackoff
+	}
+	if cfg.DrainTimeout <= 0 {
+		cfg.DrainTimeout = DefaultDrainTimeout
+	}
+	return &Manager{
+		cfg:     cfg,
+		logger:  cfg.Logger,
+		quotas:  hostserve.NewQuotaTracker(),
+		handles: make(map[string]*Handle),
+	}
+}
+
+// Discover loads the manifest of every plugin in the plugins directory, ordered by path.
+func (m *Manager) Discover() ([]*manifest.Manifest, error) {
+	paths, err := filepath.Glob(filepath.Join(m.cfg.Dir, "*", manifest.FileName))
+	if err != nil {
+		return nil, err
+	}
+	manifests := make([]*manifest.Manifest, 0, len(paths))
+	for _, path := range paths {
+		mf, err := manifest.Load(path)
+		if err != nil {
+			return nil, err
+		}
+		manifests = append(manifests, mf)
+	}
+	return manifests, nil
+}
+
+// Start discovers and launches every plugin in the plugins directory. A plugin that fails to launch does not
+// prevent the others from starting; the failures are returned together. If the host services are a
+// hostserve.Sweeper, the root of every plugin is swept before any is launched.
+func (m *Manager) Start() error {
+	manifests, err := m.Discover()
+	if err != nil {
+		return err
+	}
+	if s, ok := m.cfg.HostServices.(hostserve.Sweeper); ok {
+		for _, mf := range manifests {
+			if err := s.Sweep(mf.Root); err != nil {
+				m.logger.Warn("Failed to sweep plugin root", "plugin", mf.Name, "root", mf.Root, "err", err)
+			}
+		}
+	}
+	var errs []error
+	for _, mf := range manifests {
+		if err := m.Launch(mf); err != nil {
+			m.logger.Error("Failed to launch plugin", "plugin", mf.Name, "err", err)
+			errs = append(errs, fmt.Errorf("%s: %w", mf.Name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Launch starts the binary described by mf, dispenses every plugin it declares and connects each to host services
+// before adding it to the registry. The binary is then supervised until Shutdown.
+func (m *Manager) Launch(mf *manifest.Manifest) error {
+	if len(mf.Plugins) == 0 {
+		return ErrNoPlugins
+	}
+	seen := make(map[string]bool, len(mf.Plugins))
+	for _, p := range mf.Plugins {
+		if _, ok := m.cfg.Types[p.Type]; !ok {
+			return fmt.Errorf("%w: %s", ErrUnknownType, p.Type)
+		}
+		if _, exists := m.Get(p.Name); exists || seen[p.Name] {
+			return fmt.Errorf("%w: %s", ErrDuplicateName, p.Name)
+		}
+		seen[p.Name] = true
+	}
+	services, err := m.services(mf)
+	if err != nil {
+		return err
+	}
+
+	proc := &process{
+		logger:   m.logger.Named(mf.Name),
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+		mf:       mf,
+		services: services,
+	}
+	inst, err := m.launch(proc, mf, services)
+	if err != nil {
+		return err
+	}
+	for _, p := range mf.Plugins {
+		proc.handles = append(proc.handles, &Handle{Name: p.Name, Type: p.Type, proc: proc})
+	}
+	proc.swap(inst)
+
+	m.mu.Lock()
+	for _, h := range proc.handles {
+		m.handles[h.Name] = h
+	}
+	m.processes = append(m.processes, proc)
+	m.mu.Unlock()
+
+	go m.supervise(proc)
+	proc.logger.Info("Plugin launched", "version", mf.Version, "plugins", len(mf.Plugins))
+	return nil
+}
+
+// services wraps the host services in checkers for the quota and capabilities declared in mf, substituting the
+// virtual environment mf declares, if any, for the host's.
+func (m *Manager) services(mf *manifest.Manifest) (hostserve.IHostServices, error) {
+	caps, err := hostserve.NewCapabilities(mf.Root, mf.Capabilities)
+	if err != nil {
+		return nil, err
+	}
+	services := m.cfg.HostServices
+	if mf.Env != nil {
+		env := make(map[string]string, len(mf.Env))
+		for key, val := range mf.Env {
+			env[key] = os.Expand(val, func(name string) string {
+				return m.cfg.HostServices.GetEnv(context.Background(), name)
+			})
+		}
+		services = hostserve.NewHostServices(m.cfg.HostServices, hostserve.NewMapEnv(env))
+	}
+	var quota hostserve.Quota
+	if mf.Quota != nil {
+		quota = hostserve.Quota{
+			BytesRead:      mf.Quota.BytesRead,
+			BytesWritten:   mf.Quota.BytesWritten,
+			Files:          mf.Quota.Files,
+			CallsPerSecond: mf.Quota.CallsPerSecond,
+		}
+	}
+	services = hostserve.NewQuotaChecker(services, m.quotas, quota)
+	return hostserve.NewCapabilityChecker(services, caps), nil
+}
+
+// launch starts a new instance of the binary described by mf, dispenses every plugin it declares and connects
+// each to services.
+func (m *Manager) launch(proc *process, mf *manifest.Manifest, services hostserve.IHostServices) (*instance, error) {
+	pluginMap := make(map[string]plugin.Plugin, len(mf.Plugins))
+	for _, p := range mf.Plugins {
+		pluginMap[p.Name] = m.cfg.Types[p.Type]
+	}
+	stamp := stampOf(mf)
+	client := plugin.NewClient(&plugin.ClientConfig{
+		HandshakeConfig:  sdk.Handshake,
+		Plugins:          pluginMap,
+		Cmd:              exec.Command(mf.CommandPath()),
+		AllowedProtocols: []plugin.Protocol{plugin.ProtocolGRPC},
+		Logger:           proc.logger,
+	})
+	rpcClient, err := client.Client()
+	if err != nil {
+		client.Kill()
+		return nil, fmt.Errorf("failed to get RPC client: %w", err)
+	}
+
+	inst := &instance{
+		client:  client,
+		raws:    make(map[string]interface{}, len(mf.Plugins)),
+		started: time.Now(),
+		stamp:   stamp,
+	}
+	for _, p := range mf.Plugins {
+		raw, err := rpcClient.Dispense(p.Name)
+		if err != nil {
+			client.Kill()
+			return nil, fmt.Errorf("failed to dispense %s: %w", p.Name, err)
+		}
+		err = hostconn.EstablishHostServices(raw, services, proc.logger,
+			hostconn.WithClientID(p.Name),
+			hostconn.WithRoot(mf.Root),
+			hostconn.WithAudit(m.cfg.Audit),
+		)
+		if err != nil {
+			client.Kill()
+			return nil, fmt.Errorf("failed to establish host services for %s: %w", p.Name, err)
+		}
+		inst.raws[p.Name] = raw
+	}
+	return inst, nil
+}
+
+// Get returns the handle of the registered plugin with the given name.
+func (m *Manager) Get(name string) (*Handle, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	h, ok := m.handles[name]
+	return h, ok
+}
+
+// Usage returns the host service usage counted for the plugin with the given name. Usage is kept across restarts and
+// reloads of the plugin.
+func (m *Manager) Usage(name string) hostserve.QuotaUsage {
+	return m.quotas.Usage(name)
+}
+
+// Names returns the names of every registered plugin in sorted order.
+func (m *Manager) Names() []string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	names := make([]string, 0, len(m.handles))
+	for name := range m.handles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Shutdown stops supervising every plugin, waits for instances replaced by a reload to drain, disconnects the
+// plugins from host services, stops their binaries and clears the registry.
+func (m *Manager) Shutdown() {
+	m.mu.Lock()
+	processes := m.processes
+	m.processes = nil
+	m.handles = make(map[string]*Handle)
+	m.mu.Unlock()
+
+	for _, proc := range processes {
+		close(proc.stop)
+		<-proc.done
+		proc.retiring.Wait()
+		proc.retire(proc.swap(nil))
+	}
+}
@@ -0,0 +1,75 @@
+package pluginmgr
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/bmj2728/hst/shared/pkg/manifest"
+	"github.com/hashicorp/go-plugin"
+)
+
+// stubPlugin is a plugin type that is registered but never launched.
+type stubPlugin struct {
+	plugin.NetRPCUnsupportedPlugin
+}
+
+func TestDiscover(t *testing.T) {
+	dir := t.TempDir()
+	for name, data := range map[string]string{
+		"b/manifest.yaml":      "name: b\n",
+		"a/manifest.yaml":      "name: a\ncommand: bin/a\n",
+		"c/other.yaml":         "name: c\n",
+		"deep/d/manifest.yaml": "name: d\n",
+	} {
+		p := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	manifests, err := New(Config{Dir: dir}).Discover()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(manifests) != 2 || manifests[0].Name != "a" || manifests[1].Name != "b" {
+		t.Fatalf("Discover = %+v, want the manifests of a and b in order", manifests)
+	}
+	if got, want := manifests[0].CommandPath(), filepath.Join(dir, "a", "bin", "a"); got != want {
+		t.Errorf("CommandPath() = %q, want %q", got, want)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "c", manifest.FileName), nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := New(Config{Dir: dir}).Discover(); !errors.Is(err, manifest.ErrMissingName) {
+		t.Errorf("Discover with an invalid manifest = %v, want ErrMissingName", err)
+	}
+}
+
+func TestLaunchRefusesInvalidManifests(t *testing.T) {
+	tests := []struct {
+		name    string
+		plugins []manifest.Plugin
+		want    error
+	}{
+		{name: "no plugins", want: ErrNoPlugins},
+		{name: "unknown type", want: ErrUnknownType, plugins: []manifest.Plugin{{Name: "a", Type: "missing"}}},
+		{name: "duplicate name", want: ErrDuplicateName, plugins: []manifest.Plugin{
+			{Name: "a", Type: "stub"},
+			{Name: "a", Type: "stub"},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := New(Config{Dir: t.TempDir(), Types: map[string]plugin.Plugin{"stub": &stubPlugin{}}})
+			mf := &manifest.Manifest{Name: "test", Command: "missing", Root: ".", Plugins: tt.plugins}
+			if err := m.Launch(mf); !errors.Is(err, tt.want) {
+				t.Errorf("Launch = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,123 @@
+package pluginmgr
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"github.com/bmj2728/hst/shared/pkg/manifest"
+)
+
+// fileStamp is the modification time and size of a file, or zero if it could not be read.
+type fileStamp struct {
+	modTime time.Time
+	size    int64
+}
+
+// stamp identifies the versions of a plugin's binary and manifest.
+type stamp struct {
+	binary   fileStamp
+	manifest fileStamp
+}
+
+// statStamp returns the stamp of the file at path.
+func statStamp(path string) fileStamp {
+	info, err := os.Stat(path)
+	if err != nil {
+		return fileStamp{}
+	}
+	return fileStamp{modTime: info.ModTime(), size: info.Size()}
+}
+
+// stampOf returns the current stamp of the binary and manifest described by mf.
+func stampOf(mf *manifest.Manifest) stamp {
+	return stamp{
+		binary:   statStamp(mf.CommandPath()),
+		manifest: statStamp(filepath.Join(mf.Dir, manifest.FileName)),
+	}
+}
+
+// checkReload reloads the process once its binary or manifest differs from the ones current was launched from.
+// A change must be seen unchanged on two consecutive checks, so that a binary still being written is not launched,
+// and a change that failed to reload is not retried until the files change again.
+func (m *Manager) checkReload(p *process, current *instance) {
+	p.mu.Lock()
+	mf := p.mf
+	p.mu.Unlock()
+
+	now := stampOf(mf)
+	if now == current.stamp || now == p.rejected || now.binary == (fileStamp{}) {
+		p.pending = stamp{}
+		return
+	}
+	if now != p.pending {
+		p.pending = now
+		return
+	}
+	p.pending = stamp{}
+	if !m.reload(p, mf) {
+		p.rejected = now
+	}
+}
+
+// reload launches a new instance from the plugin's binary and manifest on disk, swaps it in behind the handles and
+// retires the old instance once the calls in flight on it have drained. If the new instance cannot be launched,
+// the old one keeps serving and reload reports false.
+func (m *Manager) reload(p *process, old *manifest.Manifest) bool {
+	p.logger.Info("Plugin changed on disk, reloading")
+	mf, err := manifest.Load(filepath.Join(old.Dir, manifest.FileName))
+	if err != nil {
+		p.logger.Error("Failed to reload plugin manifest", "err", err)
+		return false
+	}
+	if !slices.Equal(mf.Plugins, old.Plugins) {
+		p.logger.Error("Plugin manifest changed the plugins it declares, restart the host to apply it")
+		return false
+	}
+	services, err := m.services(mf)
+	if err != nil {
+		p.logger.Error("Failed to load plugin capabilities", "err", err)
+		return false
+	}
+	inst, err := m.launch(p, mf, services)
+	if err != nil {
+		p.logger.Error("Failed to launch reloaded plugin, keeping the running version", "err", err)
+		return false
+	}
+
+	p.mu.Lock()
+	p.mf, p.services = mf, services
+	p.mu.Unlock()
+	retired := p.swap(inst)
+	p.logger.Info("Plugin reloaded", "version", mf.Version)
+
+	p.retiring.Add(1)
+	go func() {
+		defer p.retiring.Done()
+		if !m.drain(retired) {
+			p.logger.Warn("Calls still in flight on replaced plugin, stopping it anyway", "timeout",
+				m.cfg.DrainTimeout)
+		}
+		p.retire(retired)
+	}()
+	return true
+}
+
+// drain waits for the calls in flight on inst to finish, reporting false if they outlast Config.DrainTimeout.
+func (m *Manager) drain(inst *instance) bool {
+	if inst == nil {
+		return true
+	}
+	drained := make(chan struct{})
+	go func() {
+		inst.calls.Wait()
+		close(drained)
+	}()
+	select {
+	case <-drained:
+		return true
+	case <-time.After(m.cfg.DrainTimeout):
+		return false
+	}
+}
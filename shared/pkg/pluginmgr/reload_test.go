@@ -0,0 +1,203 @@
+package pluginmgr
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/bmj2728/hst/shared/pkg/manifest"
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/go-plugin"
+)
+
+// reloadProcess writes a manifest and a binary that cannot be launched to a new plugin directory, and returns a
+// process for them whose current instance carries their stamp.
+func reloadProcess(t *testing.T) (*process, *instance) {
+	t.Helper()
+	dir := t.TempDir()
+	data := "name: lister\nplugins:\n  - name: files\n    type: stub\n"
+	if err := os.WriteFile(filepath.Join(dir, manifest.FileName), []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "lister"), []byte("v1"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	mf, err := manifest.Load(filepath.Join(dir, manifest.FileName))
+	if err != nil {
+		t.Fatal(err)
+	}
+	current := &instance{stamp: stampOf(mf)}
+	return &process{logger: hclog.NewNullLogger(), mf: mf, current: current}, current
+}
+
+func TestCheckReload(t *testing.T) {
+	tests := []struct {
+		name string
+		// change alters the plugin's files before each of the checks
+		change []func(t *testing.T, dir string)
+		// wantPending and wantRejected report whether a change is waiting to settle and whether a reload failed
+		wantPending, wantRejected bool
+	}{
+		{name: "unchanged", change: []func(*testing.T, string){nil, nil}},
+		{name: "changed once", change: []func(*testing.T, string){writeBinary("v2")}, wantPending: true},
+		{name: "settled change reloads", change: []func(*testing.T, string){writeBinary("v2"), nil},
+			wantRejected: true},
+		{name: "still changing", change: []func(*testing.T, string){writeBinary("v2"), writeBinary("v22")},
+			wantPending: true},
+		{name: "changed manifest reloads", wantRejected: true, change: []func(*testing.T, string){
+			writeManifest("name: lister\nversion: 2\nplugins:\n  - name: files\n    type: stub\n"), nil}},
+		{name: "changed plugins are refused", wantRejected: true, change: []func(*testing.T, string){
+			writeManifest("name: lister\nplugins:\n  - name: other\n    type: stub\n"), nil}},
+		{name: "invalid manifest is refused", wantRejected: true, change: []func(*testing.T, string){
+			writeManifest("plugins: [\n"), nil}},
+		{name: "binary removed", change: []func(*testing.T, string){removeBinary, nil}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := New(Config{Types: map[string]plugin.Plugin{"stub": &stubPlugin{}}, Logger: hclog.NewNullLogger()})
+			p, current := reloadProcess(t)
+			for _, change := range tt.change {
+				if change != nil {
+					change(t, p.mf.Dir)
+				}
+				m.checkReload(p, current)
+			}
+			if pending := p.pending != (stamp{}); pending != tt.wantPending {
+				t.Errorf("change pending = %t, want %t", pending, tt.wantPending)
+			}
+			if rejected := p.rejected != (stamp{}); rejected != tt.wantRejected {
+				t.Errorf("reload rejected = %t, want %t", rejected, tt.wantRejected)
+			}
+			if p.current != current {
+				t.Error("the running instance was replaced by one that failed to launch")
+			}
+		})
+	}
+}
+
+func TestCheckReloadDoesNotRetryRejectedChange(t *testing.T) {
+	m := New(Config{Types: map[string]plugin.Plugin{"stub": &stubPlugin{}}, Logger: hclog.NewNullLogger()})
+	p, current := reloadProcess(t)
+	writeBinary("v2")(t, p.mf.Dir)
+	m.checkReload(p, current)
+	m.checkReload(p, current)
+	rejected := p.rejected
+	if rejected == (stamp{}) {
+		t.Fatal("settled change was not reloaded")
+	}
+	m.checkReload(p, current)
+	if p.pending != (stamp{}) || p.rejected != rejected {
+		t.Error("a change that failed to reload was taken up again")
+	}
+	writeBinary("v3")(t, p.mf.Dir)
+	m.checkReload(p, current)
+	if p.pending == (stamp{}) {
+		t.Error("a new change after a failed reload was not taken up")
+	}
+}
+
+// writeBinary returns a change that replaces the plugin's binary with data.
+func writeBinary(data string) func(t *testing.T, dir string) {
+	return func(t *testing.T, dir string) {
+		if err := os.WriteFile(filepath.Join(dir, "lister"), []byte(data), 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// writeManifest returns a change that replaces the plugin's manifest with data.
+func writeManifest(data string) func(t *testing.T, dir string) {
+	return func(t *testing.T, dir string) {
+		if err := os.WriteFile(filepath.Join(dir, manifest.FileName), []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// removeBinary is a change that removes the plugin's binary.
+func removeBinary(t *testing.T, dir string) {
+	if err := os.Remove(filepath.Join(dir, "lister")); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// lister is the plugin interface Call is tested through.
+type lister interface {
+	List(ctx context.Context) string
+}
+
+// version is a lister that reports its version.
+type version string
+
+// List returns the version.
+func (v version) List(context.Context) string {
+	return string(v)
+}
+
+func TestCallDrainsAcrossSwap(t *testing.T) {
+	m := New(Config{DrainTimeout: 5 * time.Second})
+	old := &instance{raws: map[string]interface{}{"files": version("v1")}}
+	h := &Handle{Name: "files"}
+	h.set(old)
+
+	entered, release := make(chan struct{}), make(chan struct{})
+	result := make(chan string)
+	go func() {
+		got, _ := Call(h, func(l lister) (string, error) {
+			close(entered)
+			<-release
+			return l.List(context.Background()), nil
+		})
+		result <- got
+	}()
+	<-entered
+
+	h.set(&instance{raws: map[string]interface{}{"files": version("v2")}})
+	got, err := Call(h, func(l lister) (string, error) { return l.List(context.Background()), nil })
+	if err != nil || got != "v2" {
+		t.Errorf("Call after the swap = %q, %v, want the new instance", got, err)
+	}
+
+	drained := make(chan bool)
+	go func() { drained <- m.drain(old) }()
+	select {
+	case <-drained:
+		t.Fatal("old instance drained while a call was still in flight on it")
+	case <-time.After(20 * time.Millisecond):
+	}
+	close(release)
+	if got := <-result; got != "v1" {
+		t.Errorf("call in flight across the swap = %q, want it finished on the old instance", got)
+	}
+	if !<-drained {
+		t.Error("old instance did not drain once its call returned")
+	}
+}
+
+func TestDrainTimesOut(t *testing.T) {
+	m := New(Config{DrainTimeout: 10 * time.Millisecond})
+	inst := &instance{}
+	inst.calls.Add(1)
+	defer inst.calls.Done()
+	if m.drain(inst) {
+		t.Error("drain reported a stuck call as drained")
+	}
+	if !m.drain(nil) {
+		t.Error("drain of no instance did not succeed")
+	}
+}
+
+func TestCallWhileUnavailable(t *testing.T) {
+	h := &Handle{Name: "files"}
+	h.set(&instance{raws: map[string]interface{}{"files": 42}})
+	if _, err := Call(h, func(l lister) (string, error) { return "", nil }); !errors.Is(err, ErrWrongType) {
+		t.Errorf("Call through the wrong type = %v, want ErrWrongType", err)
+	}
+	h.set(nil)
+	if _, err := Call(h, func(l lister) (string, error) { return "", nil }); !errors.Is(err, ErrUnavailable) {
+		t.Errorf("Call while unavailable = %v, want ErrUnavailable", err)
+	}
+}
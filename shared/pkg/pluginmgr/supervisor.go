@@ -0,0 +1,158 @@
+package pluginmgr
+
+import (
+	"sync"
+	"time"
+
+	"github.com/bmj2728/hst/shared/pkg/hostconn"
+	"github.com/bmj2728/hst/shared/pkg/hostserve"
+	"github.com/bmj2728/hst/shared/pkg/manifest"
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/go-plugin"
+)
+
+const (
+	// DefaultHealthInterval is how often a plugin's process is checked when Config.HealthInterval is not set.
+	DefaultHealthInterval = time.Second
+	// DefaultRestartBackoff is the delay before the first restart when Config.RestartBackoff is not set.
+	DefaultRestartBackoff = 500 * time.Millisecond
+	// DefaultMaxRestartBackoff caps the restart delay when Config.MaxRestartBackoff is not set.
+	DefaultMaxRestartBackoff = 30 * time.Second
+	// DefaultDrainTimeout bounds how long a replaced instance is drained when Config.DrainTimeout is not set.
+	DefaultDrainTimeout = 30 * time.Second
+)
+
+// instance is a single running process of a plugin binary along with the plugins dispensed from it.
+type instance struct {
+	client  *plugin.Client
+	raws    map[string]interface{}
+	started time.Time
+	// stamp identifies the binary and manifest the instance was launched from.
+	stamp stamp
+	// calls tracks calls made through handles, so the instance can be drained before it is retired.
+	calls sync.WaitGroup
+}
+
+// process is a plugin binary under supervision. It outlives the instances it runs.
+type process struct {
+	logger  hclog.Logger
+	handles []*Handle
+	stop    chan struct{}
+	done    chan struct{}
+	// pending is a changed stamp waiting to settle before the process is reloaded, and rejected is the last stamp
+	// that failed to reload. Both are only used by supervise.
+	pending  stamp
+	rejected stamp
+	// retiring tracks instances replaced by a reload that are still draining.
+	retiring sync.WaitGroup
+
+	mu       sync.Mutex
+	mf       *manifest.Manifest
+	services hostserve.IHostServices
+	current  *instance
+}
+
+// manifest returns the manifest the process was last launched from.
+func (p *process) manifest() *manifest.Manifest {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.mf
+}
+
+// swap makes inst the current instance of the process and of every handle, returning the previous instance.
+// A nil inst marks the plugins unavailable.
+func (p *process) swap(inst *instance) *instance {
+	p.mu.Lock()
+	old := p.current
+	p.current = inst
+	p.mu.Unlock()
+	for _, h := range p.handles {
+		h.set(inst)
+	}
+	return old
+}
+
+// retire disconnects the instance's plugins from host services and stops its process.
+func (p *process) retire(inst *instance) {
+	if inst == nil {
+		return
+	}
+	for _, raw := range inst.raws {
+		hostconn.DisconnectHostServices(raw, p.logger)
+	}
+	inst.client.Kill()
+}
+
+// nextRestart decides the restart that follows an exit, given the number of restarts attempted so far and how
+// long the exited instance ran, zero if it never started. The delay doubles from cfg.RestartBackoff with each
+// attempt up to cfg.MaxRestartBackoff, and an instance that ran longer than the maximum starts the count over. It
+// returns the delay and the updated count, or false once cfg.MaxRestarts attempts have been made.
+func nextRestart(cfg Config, attempt int, uptime time.Duration) (time.Duration, int, bool) {
+	if uptime > cfg.MaxRestartBackoff {
+		attempt = 0
+	}
+	if cfg.MaxRestarts > 0 && attempt >= cfg.MaxRestarts {
+		return 0, attempt, false
+	}
+	d := cfg.RestartBackoff
+	for i := 0; i < attempt && d < cfg.MaxRestartBackoff; i++ {
+		d *= 2
+	}
+	return min(d, cfg.MaxRestartBackoff), attempt + 1, true
+}
+
+// supervise watches the process until it is stopped, restarting it with backoff whenever it exits. Each restart
+// re-dispenses the plugins, re-establishes their host services and swaps the new instance in behind their handles.
+// With Config.Reload set, it also reloads the process when its binary or manifest changes.
+func (m *Manager) supervise(p *process) {
+	defer close(p.done)
+	ticker := time.NewTicker(m.cfg.HealthInterval)
+	defer ticker.Stop()
+
+	attempt := 0
+	for {
+		select {
+		case <-p.stop:
+			return
+		case <-ticker.C:
+		}
+		p.mu.Lock()
+		current := p.current
+		p.mu.Unlock()
+		if current != nil && !current.client.Exited() {
+			if m.cfg.Reload {
+				m.checkReload(p, current)
+			}
+			continue
+		}
+
+		var uptime time.Duration
+		if current != nil {
+			p.logger.Warn("Plugin process exited, restarting")
+			p.retire(p.swap(nil))
+			uptime = time.Since(current.started)
+		}
+		delay, next, ok := nextRestart(m.cfg, attempt, uptime)
+		if !ok {
+			p.logger.Error("Plugin exceeded its restart limit, giving up", "restarts", attempt)
+			return
+		}
+		attempt = next
+		select {
+		case <-p.stop:
+			return
+		case <-time.After(delay):
+		}
+
+		p.mu.Lock()
+		mf, services := p.mf, p.services
+		p.mu.Unlock()
+		inst, err := m.launch(p, mf, services)
+		if err != nil {
+			p.logger.Error("Failed to restart plugin", "attempt", attempt, "err", err)
+			continue
+		}
+		p.swap(inst)
+		p.logger.Info("Plugin restarted", "attempt", attempt)
+	}
+}
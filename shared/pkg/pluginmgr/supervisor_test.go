@@ -0,0 +1,94 @@
+package pluginmgr
+
+import (
+	"testing"
+	"time"
+
+	"github.com/bmj2728/hst/shared/pkg/manifest"
+	"github.com/hashicorp/go-hclog"
+)
+
+func TestNextRestart(t *testing.T) {
+	cfg := Config{RestartBackoff: time.Second, MaxRestartBackoff: 10 * time.Second}
+	limited := cfg
+	limited.MaxRestarts = 3
+	tests := []struct {
+		name      string
+		cfg       Config
+		attempt   int
+		uptime    time.Duration
+		wantDelay time.Duration
+		wantNext  int
+		wantOK    bool
+	}{
+		{name: "first restart", cfg: cfg, wantDelay: time.Second, wantNext: 1, wantOK: true},
+		{name: "doubles", cfg: cfg, attempt: 1, wantDelay: 2 * time.Second, wantNext: 2, wantOK: true},
+		{name: "doubles again", cfg: cfg, attempt: 3, wantDelay: 8 * time.Second, wantNext: 4, wantOK: true},
+		{name: "capped", cfg: cfg, attempt: 4, wantDelay: 10 * time.Second, wantNext: 5, wantOK: true},
+		{name: "stays capped", cfg: cfg, attempt: 100, wantDelay: 10 * time.Second, wantNext: 101, wantOK: true},
+		{name: "short run keeps the count", cfg: cfg, attempt: 2, uptime: 10 * time.Second,
+			wantDelay: 4 * time.Second, wantNext: 3, wantOK: true},
+		{name: "stable run resets the count", cfg: cfg, attempt: 4, uptime: 11 * time.Second,
+			wantDelay: time.Second, wantNext: 1, wantOK: true},
+		{name: "within the limit", cfg: limited, attempt: 2, wantDelay: 4 * time.Second, wantNext: 3, wantOK: true},
+		{name: "limit reached", cfg: limited, attempt: 3, wantNext: 3},
+		{name: "stable run resets the limit", cfg: limited, attempt: 3, uptime: time.Minute, wantDelay: time.Second,
+			wantNext: 1, wantOK: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			delay, next, ok := nextRestart(tt.cfg, tt.attempt, tt.uptime)
+			if delay != tt.wantDelay || next != tt.wantNext || ok != tt.wantOK {
+				t.Errorf("nextRestart(%d, %v) = %v, %d, %t, want %v, %d, %t", tt.attempt, tt.uptime, delay, next, ok,
+					tt.wantDelay, tt.wantNext, tt.wantOK)
+			}
+		})
+	}
+}
+
+// failingProcess returns a process for a binary that does not exist, so every restart of it fails.
+func failingProcess(t *testing.T) *process {
+	t.Helper()
+	mf := &manifest.Manifest{Name: "missing", Command: "missing", Root: ".", Dir: t.TempDir(),
+		Plugins: []manifest.Plugin{{Name: "missing", Type: "stub"}}}
+	return &process{
+		logger: hclog.NewNullLogger(),
+		stop:   make(chan struct{}),
+		done:   make(chan struct{}),
+		mf:     mf,
+	}
+}
+
+func TestSuperviseGivesUpAfterMaxRestarts(t *testing.T) {
+	m := New(Config{Dir: t.TempDir(), HealthInterval: time.Millisecond, RestartBackoff: time.Millisecond,
+		MaxRestarts: 2, Logger: hclog.NewNullLogger()})
+	proc := failingProcess(t)
+	go m.supervise(proc)
+	select {
+	case <-proc.done:
+	case <-time.After(5 * time.Second):
+		close(proc.stop)
+		t.Fatal("supervise kept restarting past MaxRestarts")
+	}
+}
+
+func TestShutdownStopsSupervisionDuringBackoff(t *testing.T) {
+	m := New(Config{Dir: t.TempDir(), HealthInterval: time.Millisecond, RestartBackoff: time.Hour,
+		Logger: hclog.NewNullLogger()})
+	proc := failingProcess(t)
+	m.processes = append(m.processes, proc)
+	go m.supervise(proc)
+	// Let supervise reach its first hour-long backoff
+	time.Sleep(20 * time.Millisecond)
+
+	shutdown := make(chan struct{})
+	go func() {
+		m.Shutdown()
+		close(shutdown)
+	}()
+	select {
+	case <-shutdown:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Shutdown waited out the restart backoff")
+	}
+}
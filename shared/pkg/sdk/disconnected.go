@@ -0,0 +1,163 @@
+package sdk
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"time"
+
+	"github.com/bmj2728/hst/shared/pkg/hostserve"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ErrNotConnected is returned by calls made through HostConnector.Host before the host has established host
+// services, or after it has disconnected them.
+var ErrNotConnected = errors.New("host services not established")
+
+// notConnectedError is the error returned by every call to disconnectedHost. It matches ErrNotConnected with
+// errors.Is and carries an Unavailable gRPC status, like a call that could not reach the host.
+type notConnectedError struct{}
+
+// Error returns the text of ErrNotConnected.
+func (notConnectedError) Error() string { return ErrNotConnected.Error() }
+
+// Unwrap returns ErrNotConnected so callers can test for it with errors.Is.
+func (notConnectedError) Unwrap() error { return ErrNotConnected }
+
+// GRPCStatus reports the error as an Unavailable gRPC status.
+func (notConnectedError) GRPCStatus() *status.Status {
+	return status.New(codes.Unavailable, ErrNotConnected.Error())
+}
+
+// errNotConnected is the error every disconnectedHost call fails with.
+var errNotConnected error = notConnectedError{}
+
+// disconnectedHost stands in for the host services while none are established, failing every call with
+// errNotConnected rather than leaving plugins a nil interface to call.
+type disconnectedHost struct{}
+
+var _ hostserve.IHostServices = disconnectedHost{}
+
+func (disconnectedHost) ReadDir(context.Context, string) ([]fs.DirEntry, error) {
+	return nil, errNotConnected
+}
+
+func (disconnectedHost) ReadFile(context.Context, string) ([]byte, error) {
+	return nil, errNotConnected
+}
+
+func (disconnectedHost) WriteFile(context.Context, string, []byte, os.FileMode) error {
+	return errNotConnected
+}
+
+func (disconnectedHost) WriteFileIf(context.Context, string, []byte, os.FileMode,
+	hostserve.WritePrecondition,
+) error {
+	return errNotConnected
+}
+
+func (disconnectedHost) Stat(context.Context, string) (fs.FileInfo, error) {
+	return nil, errNotConnected
+}
+
+func (disconnectedHost) Lstat(context.Context, string) (fs.FileInfo, error) {
+	return nil, errNotConnected
+}
+
+func (disconnectedHost) MkdirAll(context.Context, string, os.FileMode) error {
+	return errNotConnected
+}
+
+func (disconnectedHost) Remove(context.Context, string) error {
+	return errNotConnected
+}
+
+func (disconnectedHost) RemoveAll(context.Context, string) error {
+	return errNotConnected
+}
+
+func (disconnectedHost) Rename(context.Context, string, string) error {
+	return errNotConnected
+}
+
+func (disconnectedHost) Copy(context.Context, string, string) (int64, error) {
+	return 0, errNotConnected
+}
+
+func (disconnectedHost) Chmod(context.Context, string, os.FileMode) error {
+	return errNotConnected
+}
+
+func (disconnectedHost) Truncate(context.Context, string, int64) error {
+	return errNotConnected
+}
+
+func (disconnectedHost) Append(context.Context, string, []byte, os.FileMode) error {
+	return errNotConnected
+}
+
+func (disconnectedHost) Watch(context.Context, string, bool) (<-chan hostserve.WatchEvent, error) {
+	return nil, errNotConnected
+}
+
+func (disconnectedHost) Walk(context.Context, string, hostserve.WalkOptions) (<-chan hostserve.WalkEntry, error) {
+	return nil, errNotConnected
+}
+
+func (disconnectedHost) Glob(context.Context, string) ([]string, error) {
+	return nil, errNotConnected
+}
+
+func (disconnectedHost) ReadDirStream(context.Context, string) (hostserve.DirReader, error) {
+	return nil, errNotConnected
+}
+
+func (disconnectedHost) ReadFileStream(context.Context, string) (io.ReadCloser, error) {
+	return nil, errNotConnected
+}
+
+func (disconnectedHost) WriteFileStream(context.Context, string, os.FileMode) (io.WriteCloser, error) {
+	return nil, errNotConnected
+}
+
+func (disconnectedHost) BeginTx(context.Context) (string, error) {
+	return "", errNotConnected
+}
+
+func (disconnectedHost) CommitTx(context.Context, string) error {
+	return errNotConnected
+}
+
+func (disconnectedHost) RollbackTx(context.Context, string) error {
+	return errNotConnected
+}
+
+func (disconnectedHost) Lock(context.Context, string, hostserve.LockMode, time.Duration) (string, time.Time, error) {
+	return "", time.Time{}, errNotConnected
+}
+
+func (disconnectedHost) TryLock(context.Context, string, hostserve.LockMode,
+	time.Duration,
+) (string, time.Time, error) {
+	return "", time.Time{}, errNotConnected
+}
+
+func (disconnectedHost) Unlock(context.Context, string) error {
+	return errNotConnected
+}
+
+// GetEnv reads every variable as unset, as it cannot report errors.
+func (disconnectedHost) GetEnv(context.Context, string) string {
+	return ""
+}
+
+func (disconnectedHost) LookupEnv(context.Context, string) (string, bool, error) {
+	return "", false, errNotConnected
+}
+
+func (disconnectedHost) Environ(context.Context) ([]string, error) {
+	return nil, errNotConnected
+}
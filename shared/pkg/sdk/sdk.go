@@ -0,0 +1,122 @@
+package sdk
+
+import (
+	"sync"
+
+	"github.com/bmj2728/hst/shared/pkg/hostconn"
+	"github.com/bmj2728/hst/shared/pkg/hostserve"
+	hostservev1 "github.com/bmj2728/hst/shared/protogen/hostserve/v1"
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/go-plugin"
+	"google.golang.org/grpc"
+)
+
+// Handshake is the handshake configuration shared by the host and every plugin.
+var Handshake = plugin.HandshakeConfig{
+	ProtocolVersion:  1,
+	MagicCookieKey:   "TEST_KEY",
+	MagicCookieValue: "TEST_VALUE",
+}
+
+// HostConnector implements hostconn.HostConnection for plugins. Embed it in a plugin implementation to receive
+// a host services client once the host has established services:
+//
+//	type MyPlugin struct {
+//		sdk.HostConnector
+//	}
+//
+//	func (p *MyPlugin) DoWork() error {
+//		_, err := p.Host().ReadDir(context.Background(), ".")
+//		return err
+//	}
+//
+// It is safe for concurrent use, and the zero value is ready to use.
+type HostConnector struct {
+	mu     sync.RWMutex
+	broker *plugin.GRPCBroker
+	conn   *grpc.ClientConn
+	host   hostserve.IHostServices
+}
+
+var _ hostconn.HostConnection = (*HostConnector)(nil)
+
+// SetBroker provides the gRPC broker used to dial the host.
+func (c *HostConnector) SetBroker(broker *plugin.GRPCBroker) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.broker = broker
+}
+
+// EstablishHostServices dials the host services at hostServiceID, replacing any previous connection.
+func (c *HostConnector) EstablishHostServices(hostServiceID uint32) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.broker == nil {
+		hclog.Default().Error("Cannot dial host service before the broker is set", "id", hostServiceID)
+		return
+	}
+	conn, err := c.broker.Dial(hostServiceID)
+	if err != nil {
+		hclog.Default().Error("Failed to dial host service", "err", err)
+		return
+	}
+
+	c.closeLocked()
+	c.conn = conn
+	c.host = hostserve.NewHostServiceGRPCClient(hostservev1.NewHostServiceClient(conn))
+	hclog.Default().Info("Established host services", "id", hostServiceID)
+}
+
+// DisconnectHostServices closes the connection to the host services, if any.
+func (c *HostConnector) DisconnectHostServices() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.conn != nil {
+		c.closeLocked()
+		hclog.Default().Info("Disconnected from host services")
+	}
+}
+
+// closeLocked closes the current connection. The caller must hold the write lock.
+func (c *HostConnector) closeLocked() {
+	if c.conn == nil {
+		return
+	}
+	if err := c.conn.Close(); err != nil {
+		hclog.Default().Error("Failed to close connection", "err", err)
+	}
+	c.conn = nil
+	c.host = nil
+}
+
+// Host returns the client for the host services. Until the host has established them, and once it has disconnected
+// them, every call through the returned client fails with ErrNotConnected, carried in an Unavailable gRPC status.
+func (c *HostConnector) Host() hostserve.IHostServices {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if c.host == nil {
+		return disconnectedHost{}
+	}
+	return c.host
+}
+
+// Connected reports whether host services have been established.
+func (c *HostConnector) Connected() bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.host != nil
+}
+
+// Serve runs the plugin process, serving p under name with the shared Handshake. It does not return until the
+// host stops the plugin.
+func Serve(name string, p plugin.Plugin) {
+	plugin.Serve(&plugin.ServeConfig{
+		HandshakeConfig: Handshake,
+		Plugins: map[string]plugin.Plugin{
+			name: p,
+		},
+		GRPCServer: plugin.DefaultGRPCServer,
+	})
+}
@@ -0,0 +1,31 @@
+package sdk
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestHostBeforeEstablishedFailsCalls(t *testing.T) {
+	var c HostConnector
+	if c.Connected() {
+		t.Fatal("zero HostConnector reports itself connected")
+	}
+	host := c.Host()
+	if host == nil {
+		t.Fatal("Host returned nil before host services were established")
+	}
+	_, err := host.ReadDir(context.Background(), ".")
+	if !errors.Is(err, ErrNotConnected) {
+		t.Errorf("ReadDir = %v, want ErrNotConnected", err)
+	}
+	if code := status.Code(err); code != codes.Unavailable {
+		t.Errorf("ReadDir status code = %v, want %v", code, codes.Unavailable)
+	}
+	if err := host.WriteFile(context.Background(), "x", nil, 0); !errors.Is(err, ErrNotConnected) {
+		t.Errorf("WriteFile = %v, want ErrNotConnected", err)
+	}
+}
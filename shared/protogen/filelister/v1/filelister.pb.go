@@ -9,7 +9,7 @@ package filelisterv1
 import (
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
-	_ "google.golang.org/protobuf/types/known/apipb"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
 	reflect "reflect"
 	sync "sync"
 	unsafe "unsafe"
@@ -22,53 +22,72 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
-type Empty struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+// EntryKind is the type of file a FileListEntry names.
+type EntryKind int32
+
+const (
+	EntryKind_ENTRY_KIND_UNSPECIFIED EntryKind = 0
+	EntryKind_ENTRY_KIND_FILE        EntryKind = 1
+	EntryKind_ENTRY_KIND_DIR         EntryKind = 2
+	EntryKind_ENTRY_KIND_SYMLINK     EntryKind = 3
+	EntryKind_ENTRY_KIND_OTHER       EntryKind = 4
+)
+
+// Enum value maps for EntryKind.
+var (
+	EntryKind_name = map[int32]string{
+		0: "ENTRY_KIND_UNSPECIFIED",
+		1: "ENTRY_KIND_FILE",
+		2: "ENTRY_KIND_DIR",
+		3: "ENTRY_KIND_SYMLINK",
+		4: "ENTRY_KIND_OTHER",
+	}
+	EntryKind_value = map[string]int32{
+		"ENTRY_KIND_UNSPECIFIED": 0,
+		"ENTRY_KIND_FILE":        1,
+		"ENTRY_KIND_DIR":         2,
+		"ENTRY_KIND_SYMLINK":     3,
+		"ENTRY_KIND_OTHER":       4,
+	}
+)
+
+func (x EntryKind) Enum() *EntryKind {
+	p := new(EntryKind)
+	*p = x
+	return p
 }
 
-func (x *Empty) Reset() {
-	*x = Empty{}
-	mi := &file_filelister_v1_filelister_proto_msgTypes[0]
-	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-	ms.StoreMessageInfo(mi)
+func (x EntryKind) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
 }
 
-func (x *Empty) String() string {
-	return protoimpl.X.MessageStringOf(x)
+func (EntryKind) Descriptor() protoreflect.EnumDescriptor {
+	return file_filelister_v1_filelister_proto_enumTypes[0].Descriptor()
 }
 
-func (*Empty) ProtoMessage() {}
+func (EntryKind) Type() protoreflect.EnumType {
+	return &file_filelister_v1_filelister_proto_enumTypes[0]
+}
 
-func (x *Empty) ProtoReflect() protoreflect.Message {
-	mi := &file_filelister_v1_filelister_proto_msgTypes[0]
-	if x != nil {
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		if ms.LoadMessageInfo() == nil {
-			ms.StoreMessageInfo(mi)
-		}
-		return ms
-	}
-	return mi.MessageOf(x)
+func (x EntryKind) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
 }
 
-// Deprecated: Use Empty.ProtoReflect.Descriptor instead.
-func (*Empty) Descriptor() ([]byte, []int) {
+// Deprecated: Use EntryKind.Descriptor instead.
+func (EntryKind) EnumDescriptor() ([]byte, []int) {
 	return file_filelister_v1_filelister_proto_rawDescGZIP(), []int{0}
 }
 
 type FileListRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Dir           string                 `protobuf:"bytes,1,opt,name=dir,proto3" json:"dir,omitempty"`
-	HostService   uint32                 `protobuf:"varint,2,opt,name=host_service,json=hostService,proto3" json:"host_service,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *FileListRequest) Reset() {
 	*x = FileListRequest{}
-	mi := &file_filelister_v1_filelister_proto_msgTypes[1]
+	mi := &file_filelister_v1_filelister_proto_msgTypes[0]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -80,7 +99,7 @@ func (x *FileListRequest) String() string {
 func (*FileListRequest) ProtoMessage() {}
 
 func (x *FileListRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_filelister_v1_filelister_proto_msgTypes[1]
+	mi := &file_filelister_v1_filelister_proto_msgTypes[0]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -93,7 +112,7 @@ func (x *FileListRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use FileListRequest.ProtoReflect.Descriptor instead.
 func (*FileListRequest) Descriptor() ([]byte, []int) {
-	return file_filelister_v1_filelister_proto_rawDescGZIP(), []int{1}
+	return file_filelister_v1_filelister_proto_rawDescGZIP(), []int{0}
 }
 
 func (x *FileListRequest) GetDir() string {
@@ -103,24 +122,16 @@ func (x *FileListRequest) GetDir() string {
 	return ""
 }
 
-func (x *FileListRequest) GetHostService() uint32 {
-	if x != nil {
-		return x.HostService
-	}
-	return 0
-}
-
 type FileListResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Entry         []string               `protobuf:"bytes,1,rep,name=entry,proto3" json:"entry,omitempty"`
-	Error         *string                `protobuf:"bytes,2,opt,name=error,proto3,oneof" json:"error,omitempty"`
+	Entries       []*FileListEntry       `protobuf:"bytes,3,rep,name=entries,proto3" json:"entries,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *FileListResponse) Reset() {
 	*x = FileListResponse{}
-	mi := &file_filelister_v1_filelister_proto_msgTypes[2]
+	mi := &file_filelister_v1_filelister_proto_msgTypes[1]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -132,7 +143,7 @@ func (x *FileListResponse) String() string {
 func (*FileListResponse) ProtoMessage() {}
 
 func (x *FileListResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_filelister_v1_filelister_proto_msgTypes[2]
+	mi := &file_filelister_v1_filelister_proto_msgTypes[1]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -145,44 +156,84 @@ func (x *FileListResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use FileListResponse.ProtoReflect.Descriptor instead.
 func (*FileListResponse) Descriptor() ([]byte, []int) {
-	return file_filelister_v1_filelister_proto_rawDescGZIP(), []int{2}
+	return file_filelister_v1_filelister_proto_rawDescGZIP(), []int{1}
 }
 
-func (x *FileListResponse) GetEntry() []string {
+func (x *FileListResponse) GetEntries() []*FileListEntry {
 	if x != nil {
-		return x.Entry
+		return x.Entries
 	}
 	return nil
 }
 
-func (x *FileListResponse) GetError() string {
-	if x != nil && x.Error != nil {
-		return *x.Error
+// FileListChunk carries a batch of entries from ListStream.
+type FileListChunk struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Entries       []*FileListEntry       `protobuf:"bytes,1,rep,name=entries,proto3" json:"entries,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FileListChunk) Reset() {
+	*x = FileListChunk{}
+	mi := &file_filelister_v1_filelister_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FileListChunk) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FileListChunk) ProtoMessage() {}
+
+func (x *FileListChunk) ProtoReflect() protoreflect.Message {
+	mi := &file_filelister_v1_filelister_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return ""
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FileListChunk.ProtoReflect.Descriptor instead.
+func (*FileListChunk) Descriptor() ([]byte, []int) {
+	return file_filelister_v1_filelister_proto_rawDescGZIP(), []int{2}
 }
 
-type HostServiceRequest struct {
+func (x *FileListChunk) GetEntries() []*FileListEntry {
+	if x != nil {
+		return x.Entries
+	}
+	return nil
+}
+
+// DisplayHints are optional suggestions from the plugin on how the host should render an entry.
+type DisplayHints struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	HostService   uint32                 `protobuf:"varint,1,opt,name=host_service,json=hostService,proto3" json:"host_service,omitempty"`
+	Color         string                 `protobuf:"bytes,1,opt,name=color,proto3" json:"color,omitempty"`
+	Bold          bool                   `protobuf:"varint,2,opt,name=bold,proto3" json:"bold,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *HostServiceRequest) Reset() {
-	*x = HostServiceRequest{}
+func (x *DisplayHints) Reset() {
+	*x = DisplayHints{}
 	mi := &file_filelister_v1_filelister_proto_msgTypes[3]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *HostServiceRequest) String() string {
+func (x *DisplayHints) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*HostServiceRequest) ProtoMessage() {}
+func (*DisplayHints) ProtoMessage() {}
 
-func (x *HostServiceRequest) ProtoReflect() protoreflect.Message {
+func (x *DisplayHints) ProtoReflect() protoreflect.Message {
 	mi := &file_filelister_v1_filelister_proto_msgTypes[3]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -194,37 +245,154 @@ func (x *HostServiceRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use HostServiceRequest.ProtoReflect.Descriptor instead.
-func (*HostServiceRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use DisplayHints.ProtoReflect.Descriptor instead.
+func (*DisplayHints) Descriptor() ([]byte, []int) {
 	return file_filelister_v1_filelister_proto_rawDescGZIP(), []int{3}
 }
 
-func (x *HostServiceRequest) GetHostService() uint32 {
+func (x *DisplayHints) GetColor() string {
+	if x != nil {
+		return x.Color
+	}
+	return ""
+}
+
+func (x *DisplayHints) GetBold() bool {
 	if x != nil {
-		return x.HostService
+		return x.Bold
+	}
+	return false
+}
+
+// FileListEntry is a single file found by a plugin. preview holds some or all of the file's contents when the
+// plugin chose to include them.
+type FileListEntry struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Path          string                 `protobuf:"bytes,2,opt,name=path,proto3" json:"path,omitempty"`
+	Kind          EntryKind              `protobuf:"varint,3,opt,name=kind,proto3,enum=filelister.v1.EntryKind" json:"kind,omitempty"`
+	Size          int64                  `protobuf:"varint,4,opt,name=size,proto3" json:"size,omitempty"`
+	ModTime       *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=mod_time,json=modTime,proto3" json:"mod_time,omitempty"`
+	Preview       *string                `protobuf:"bytes,6,opt,name=preview,proto3,oneof" json:"preview,omitempty"`
+	Display       *DisplayHints          `protobuf:"bytes,7,opt,name=display,proto3" json:"display,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FileListEntry) Reset() {
+	*x = FileListEntry{}
+	mi := &file_filelister_v1_filelister_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FileListEntry) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FileListEntry) ProtoMessage() {}
+
+func (x *FileListEntry) ProtoReflect() protoreflect.Message {
+	mi := &file_filelister_v1_filelister_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FileListEntry.ProtoReflect.Descriptor instead.
+func (*FileListEntry) Descriptor() ([]byte, []int) {
+	return file_filelister_v1_filelister_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *FileListEntry) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *FileListEntry) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *FileListEntry) GetKind() EntryKind {
+	if x != nil {
+		return x.Kind
+	}
+	return EntryKind_ENTRY_KIND_UNSPECIFIED
+}
+
+func (x *FileListEntry) GetSize() int64 {
+	if x != nil {
+		return x.Size
 	}
 	return 0
 }
 
+func (x *FileListEntry) GetModTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ModTime
+	}
+	return nil
+}
+
+func (x *FileListEntry) GetPreview() string {
+	if x != nil && x.Preview != nil {
+		return *x.Preview
+	}
+	return ""
+}
+
+func (x *FileListEntry) GetDisplay() *DisplayHints {
+	if x != nil {
+		return x.Display
+	}
+	return nil
+}
+
 var File_filelister_v1_filelister_proto protoreflect.FileDescriptor
 
 const file_filelister_v1_filelister_proto_rawDesc = "" +
 	"\n" +
-	"\x1efilelister/v1/filelister.proto\x12\rfilelister.v1\x1a\x19google/protobuf/api.proto\"\a\n" +
-	"\x05Empty\"F\n" +
+	"\x1efilelister/v1/filelister.proto\x12\rfilelister.v1\x1a\x1fgoogle/protobuf/timestamp.proto\")\n" +
 	"\x0fFileListRequest\x12\x10\n" +
-	"\x03dir\x18\x01 \x01(\tR\x03dir\x12!\n" +
-	"\fhost_service\x18\x02 \x01(\rR\vhostService\"M\n" +
-	"\x10FileListResponse\x12\x14\n" +
-	"\x05entry\x18\x01 \x03(\tR\x05entry\x12\x19\n" +
-	"\x05error\x18\x02 \x01(\tH\x00R\x05error\x88\x01\x01B\b\n" +
-	"\x06_error\"7\n" +
-	"\x12HostServiceRequest\x12!\n" +
-	"\fhost_service\x18\x01 \x01(\rR\vhostService2\xa7\x01\n" +
+	"\x03dir\x18\x01 \x01(\tR\x03dirJ\x04\b\x02\x10\x03\"V\n" +
+	"\x10FileListResponse\x126\n" +
+	"\aentries\x18\x03 \x03(\v2\x1c.filelister.v1.FileListEntryR\aentriesJ\x04\b\x01\x10\x02J\x04\b\x02\x10\x03\"M\n" +
+	"\rFileListChunk\x126\n" +
+	"\aentries\x18\x01 \x03(\v2\x1c.filelister.v1.FileListEntryR\aentriesJ\x04\b\x02\x10\x03\"8\n" +
+	"\fDisplayHints\x12\x14\n" +
+	"\x05color\x18\x01 \x01(\tR\x05color\x12\x12\n" +
+	"\x04bold\x18\x02 \x01(\bR\x04bold\"\x92\x02\n" +
+	"\rFileListEntry\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x12\n" +
+	"\x04path\x18\x02 \x01(\tR\x04path\x12,\n" +
+	"\x04kind\x18\x03 \x01(\x0e2\x18.filelister.v1.EntryKindR\x04kind\x12\x12\n" +
+	"\x04size\x18\x04 \x01(\x03R\x04size\x125\n" +
+	"\bmod_time\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\amodTime\x12\x1d\n" +
+	"\apreview\x18\x06 \x01(\tH\x00R\apreview\x88\x01\x01\x125\n" +
+	"\adisplay\x18\a \x01(\v2\x1b.filelister.v1.DisplayHintsR\adisplayB\n" +
+	"\n" +
+	"\b_preview*~\n" +
+	"\tEntryKind\x12\x1a\n" +
+	"\x16ENTRY_KIND_UNSPECIFIED\x10\x00\x12\x13\n" +
+	"\x0fENTRY_KIND_FILE\x10\x01\x12\x12\n" +
+	"\x0eENTRY_KIND_DIR\x10\x02\x12\x16\n" +
+	"\x12ENTRY_KIND_SYMLINK\x10\x03\x12\x14\n" +
+	"\x10ENTRY_KIND_OTHER\x10\x042\xa3\x01\n" +
+	"\n" +
+	"FileLister\x12G\n" +
+	"\x04List\x12\x1e.filelister.v1.FileListRequest\x1a\x1f.filelister.v1.FileListResponse\x12L\n" +
 	"\n" +
-	"FileLister\x12P\n" +
-	"\x15EstablishHostServices\x12!.filelister.v1.HostServiceRequest\x1a\x14.filelister.v1.Empty\x12G\n" +
-	"\x04List\x12\x1e.filelister.v1.FileListRequest\x1a\x1f.filelister.v1.FileListResponseB\xc8\x01\n" +
+	"ListStream\x12\x1e.filelister.v1.FileListRequest\x1a\x1c.filelister.v1.FileListChunk0\x01B\xc8\x01\n" +
 	"\x11com.filelister.v1B\x0fFilelisterProtoP\x01ZMgithub.com/bmj2728/HostServiceTest/shared/protogen/filelister/v1;filelisterv1\xa2\x02\x03FXX\xaa\x02\rFilelister.V1\xca\x02\rFilelister\\V1\xe2\x02\x19Filelister\\V1\\GPBMetadata\xea\x02\x0eFilelister::V1b\x06proto3"
 
 var (
@@ -239,23 +407,32 @@ func file_filelister_v1_filelister_proto_rawDescGZIP() []byte {
 	return file_filelister_v1_filelister_proto_rawDescData
 }
 
-var file_filelister_v1_filelister_proto_msgTypes = make([]protoimpl.MessageInfo, 4)
+var file_filelister_v1_filelister_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_filelister_v1_filelister_proto_msgTypes = make([]protoimpl.MessageInfo, 5)
 var file_filelister_v1_filelister_proto_goTypes = []any{
-	(*Empty)(nil),              // 0: filelister.v1.Empty
-	(*FileListRequest)(nil),    // 1: filelister.v1.FileListRequest
-	(*FileListResponse)(nil),   // 2: filelister.v1.FileListResponse
-	(*HostServiceRequest)(nil), // 3: filelister.v1.HostServiceRequest
+	(EntryKind)(0),                // 0: filelister.v1.EntryKind
+	(*FileListRequest)(nil),       // 1: filelister.v1.FileListRequest
+	(*FileListResponse)(nil),      // 2: filelister.v1.FileListResponse
+	(*FileListChunk)(nil),         // 3: filelister.v1.FileListChunk
+	(*DisplayHints)(nil),          // 4: filelister.v1.DisplayHints
+	(*FileListEntry)(nil),         // 5: filelister.v1.FileListEntry
+	(*timestamppb.Timestamp)(nil), // 6: google.protobuf.Timestamp
 }
 var file_filelister_v1_filelister_proto_depIdxs = []int32{
-	3, // 0: filelister.v1.FileLister.EstablishHostServices:input_type -> filelister.v1.HostServiceRequest
-	1, // 1: filelister.v1.FileLister.List:input_type -> filelister.v1.FileListRequest
-	0, // 2: filelister.v1.FileLister.EstablishHostServices:output_type -> filelister.v1.Empty
-	2, // 3: filelister.v1.FileLister.List:output_type -> filelister.v1.FileListResponse
-	2, // [2:4] is the sub-list for method output_type
-	0, // [0:2] is the sub-list for method input_type
-	0, // [0:0] is the sub-list for extension type_name
-	0, // [0:0] is the sub-list for extension extendee
-	0, // [0:0] is the sub-list for field type_name
+	5, // 0: filelister.v1.FileListResponse.entries:type_name -> filelister.v1.FileListEntry
+	5, // 1: filelister.v1.FileListChunk.entries:type_name -> filelister.v1.FileListEntry
+	0, // 2: filelister.v1.FileListEntry.kind:type_name -> filelister.v1.EntryKind
+	6, // 3: filelister.v1.FileListEntry.mod_time:type_name -> google.protobuf.Timestamp
+	4, // 4: filelister.v1.FileListEntry.display:type_name -> filelister.v1.DisplayHints
+	1, // 5: filelister.v1.FileLister.List:input_type -> filelister.v1.FileListRequest
+	1, // 6: filelister.v1.FileLister.ListStream:input_type -> filelister.v1.FileListRequest
+	2, // 7: filelister.v1.FileLister.List:output_type -> filelister.v1.FileListResponse
+	3, // 8: filelister.v1.FileLister.ListStream:output_type -> filelister.v1.FileListChunk
+	7, // [7:9] is the sub-list for method output_type
+	5, // [5:7] is the sub-list for method input_type
+	5, // [5:5] is the sub-list for extension type_name
+	5, // [5:5] is the sub-list for extension extendee
+	0, // [0:5] is the sub-list for field type_name
 }
 
 func init() { file_filelister_v1_filelister_proto_init() }
@@ -263,19 +440,20 @@ func file_filelister_v1_filelister_proto_init() {
 	if File_filelister_v1_filelister_proto != nil {
 		return
 	}
-	file_filelister_v1_filelister_proto_msgTypes[2].OneofWrappers = []any{}
+	file_filelister_v1_filelister_proto_msgTypes[4].OneofWrappers = []any{}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_filelister_v1_filelister_proto_rawDesc), len(file_filelister_v1_filelister_proto_rawDesc)),
-			NumEnums:      0,
-			NumMessages:   4,
+			NumEnums:      1,
+			NumMessages:   5,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
 		GoTypes:           file_filelister_v1_filelister_proto_goTypes,
 		DependencyIndexes: file_filelister_v1_filelister_proto_depIdxs,
+		EnumInfos:         file_filelister_v1_filelister_proto_enumTypes,
 		MessageInfos:      file_filelister_v1_filelister_proto_msgTypes,
 	}.Build()
 	File_filelister_v1_filelister_proto = out.File
@@ -19,16 +19,16 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	FileLister_EstablishHostServices_FullMethodName = "/filelister.v1.FileLister/EstablishHostServices"
-	FileLister_List_FullMethodName                  = "/filelister.v1.FileLister/List"
+	FileLister_List_FullMethodName       = "/filelister.v1.FileLister/List"
+	FileLister_ListStream_FullMethodName = "/filelister.v1.FileLister/ListStream"
 )
 
 // FileListerClient is the client API for FileLister service.
 //
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
 type FileListerClient interface {
-	EstablishHostServices(ctx context.Context, in *HostServiceRequest, opts ...grpc.CallOption) (*Empty, error)
 	List(ctx context.Context, in *FileListRequest, opts ...grpc.CallOption) (*FileListResponse, error)
+	ListStream(ctx context.Context, in *FileListRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[FileListChunk], error)
 }
 
 type fileListerClient struct {
@@ -39,32 +39,41 @@ func NewFileListerClient(cc grpc.ClientConnInterface) FileListerClient {
 	return &fileListerClient{cc}
 }
 
-func (c *fileListerClient) EstablishHostServices(ctx context.Context, in *HostServiceRequest, opts ...grpc.CallOption) (*Empty, error) {
+func (c *fileListerClient) List(ctx context.Context, in *FileListRequest, opts ...grpc.CallOption) (*FileListResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
-	out := new(Empty)
-	err := c.cc.Invoke(ctx, FileLister_EstablishHostServices_FullMethodName, in, out, cOpts...)
+	out := new(FileListResponse)
+	err := c.cc.Invoke(ctx, FileLister_List_FullMethodName, in, out, cOpts...)
 	if err != nil {
 		return nil, err
 	}
 	return out, nil
 }
 
-func (c *fileListerClient) List(ctx context.Context, in *FileListRequest, opts ...grpc.CallOption) (*FileListResponse, error) {
+func (c *fileListerClient) ListStream(ctx context.Context, in *FileListRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[FileListChunk], error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
-	out := new(FileListResponse)
-	err := c.cc.Invoke(ctx, FileLister_List_FullMethodName, in, out, cOpts...)
+	stream, err := c.cc.NewStream(ctx, &FileLister_ServiceDesc.Streams[0], FileLister_ListStream_FullMethodName, cOpts...)
 	if err != nil {
 		return nil, err
 	}
-	return out, nil
+	x := &grpc.GenericClientStream[FileListRequest, FileListChunk]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
 }
 
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type FileLister_ListStreamClient = grpc.ServerStreamingClient[FileListChunk]
+
 // FileListerServer is the server API for FileLister service.
 // All implementations must embed UnimplementedFileListerServer
 // for forward compatibility.
 type FileListerServer interface {
-	EstablishHostServices(context.Context, *HostServiceRequest) (*Empty, error)
 	List(context.Context, *FileListRequest) (*FileListResponse, error)
+	ListStream(*FileListRequest, grpc.ServerStreamingServer[FileListChunk]) error
 	mustEmbedUnimplementedFileListerServer()
 }
 
@@ -75,12 +84,12 @@ type FileListerServer interface {
 // pointer dereference when methods are called.
 type UnimplementedFileListerServer struct{}
 
-func (UnimplementedFileListerServer) EstablishHostServices(context.Context, *HostServiceRequest) (*Empty, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method EstablishHostServices not implemented")
-}
 func (UnimplementedFileListerServer) List(context.Context, *FileListRequest) (*FileListResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method List not implemented")
 }
+func (UnimplementedFileListerServer) ListStream(*FileListRequest, grpc.ServerStreamingServer[FileListChunk]) error {
+	return status.Errorf(codes.Unimplemented, "method ListStream not implemented")
+}
 func (UnimplementedFileListerServer) mustEmbedUnimplementedFileListerServer() {}
 func (UnimplementedFileListerServer) testEmbeddedByValue()                    {}
 
@@ -102,24 +111,6 @@ func RegisterFileListerServer(s grpc.ServiceRegistrar, srv FileListerServer) {
 	s.RegisterService(&FileLister_ServiceDesc, srv)
 }
 
-func _FileLister_EstablishHostServices_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(HostServiceRequest)
-	if err := dec(in); err != nil {
-		return nil, err
-	}
-	if interceptor == nil {
-		return srv.(FileListerServer).EstablishHostServices(ctx, in)
-	}
-	info := &grpc.UnaryServerInfo{
-		Server:     srv,
-		FullMethod: FileLister_EstablishHostServices_FullMethodName,
-	}
-	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(FileListerServer).EstablishHostServices(ctx, req.(*HostServiceRequest))
-	}
-	return interceptor(ctx, in, info, handler)
-}
-
 func _FileLister_List_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(FileListRequest)
 	if err := dec(in); err != nil {
@@ -138,6 +129,17 @@ func _FileLister_List_Handler(srv interface{}, ctx context.Context, dec func(int
 	return interceptor(ctx, in, info, handler)
 }
 
+func _FileLister_ListStream_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(FileListRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(FileListerServer).ListStream(m, &grpc.GenericServerStream[FileListRequest, FileListChunk]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type FileLister_ListStreamServer = grpc.ServerStreamingServer[FileListChunk]
+
 // FileLister_ServiceDesc is the grpc.ServiceDesc for FileLister service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -145,15 +147,17 @@ var FileLister_ServiceDesc = grpc.ServiceDesc{
 	ServiceName: "filelister.v1.FileLister",
 	HandlerType: (*FileListerServer)(nil),
 	Methods: []grpc.MethodDesc{
-		{
-			MethodName: "EstablishHostServices",
-			Handler:    _FileLister_EstablishHostServices_Handler,
-		},
 		{
 			MethodName: "List",
 			Handler:    _FileLister_List_Handler,
 		},
 	},
-	Streams:  []grpc.StreamDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "ListStream",
+			Handler:       _FileLister_ListStream_Handler,
+			ServerStreams: true,
+		},
+	},
 	Metadata: "filelister/v1/filelister.proto",
 }
@@ -9,6 +9,8 @@ package hostservev1
 import (
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	durationpb "google.golang.org/protobuf/types/known/durationpb"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
 	reflect "reflect"
 	sync "sync"
 	unsafe "unsafe"
@@ -21,18 +23,243 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
-// DirEntry represents the basic dir entry data
+// ErrorKind classifies a failed host operation so that clients can restore the matching Go sentinel error.
+type ErrorKind int32
+
+const (
+	ErrorKind_ERROR_KIND_UNSPECIFIED        ErrorKind = 0
+	ErrorKind_ERROR_KIND_NOT_EXIST          ErrorKind = 1
+	ErrorKind_ERROR_KIND_EXIST              ErrorKind = 2
+	ErrorKind_ERROR_KIND_PERMISSION         ErrorKind = 3
+	ErrorKind_ERROR_KIND_INVALID_PATH       ErrorKind = 4
+	ErrorKind_ERROR_KIND_ACCESS_DENIED      ErrorKind = 5
+	ErrorKind_ERROR_KIND_INVALID            ErrorKind = 6
+	ErrorKind_ERROR_KIND_CLOSED             ErrorKind = 7
+	ErrorKind_ERROR_KIND_UNSUPPORTED        ErrorKind = 8
+	ErrorKind_ERROR_KIND_CHUNK_OUT_OF_ORDER ErrorKind = 9
+	ErrorKind_ERROR_KIND_INCOMPLETE_STREAM  ErrorKind = 10
+	ErrorKind_ERROR_KIND_WALK_CYCLE         ErrorKind = 11
+	ErrorKind_ERROR_KIND_CANCELED           ErrorKind = 12
+	ErrorKind_ERROR_KIND_DEADLINE_EXCEEDED  ErrorKind = 13
+	ErrorKind_ERROR_KIND_TX_NOT_FOUND       ErrorKind = 14
+	ErrorKind_ERROR_KIND_CONFLICT           ErrorKind = 15
+	ErrorKind_ERROR_KIND_LOCKED             ErrorKind = 16
+	ErrorKind_ERROR_KIND_LOCK_NOT_FOUND     ErrorKind = 17
+	ErrorKind_ERROR_KIND_QUOTA_EXCEEDED     ErrorKind = 18
+)
+
+// Enum value maps for ErrorKind.
+var (
+	ErrorKind_name = map[int32]string{
+		0:  "ERROR_KIND_UNSPECIFIED",
+		1:  "ERROR_KIND_NOT_EXIST",
+		2:  "ERROR_KIND_EXIST",
+		3:  "ERROR_KIND_PERMISSION",
+		4:  "ERROR_KIND_INVALID_PATH",
+		5:  "ERROR_KIND_ACCESS_DENIED",
+		6:  "ERROR_KIND_INVALID",
+		7:  "ERROR_KIND_CLOSED",
+		8:  "ERROR_KIND_UNSUPPORTED",
+		9:  "ERROR_KIND_CHUNK_OUT_OF_ORDER",
+		10: "ERROR_KIND_INCOMPLETE_STREAM",
+		11: "ERROR_KIND_WALK_CYCLE",
+		12: "ERROR_KIND_CANCELED",
+		13: "ERROR_KIND_DEADLINE_EXCEEDED",
+		14: "ERROR_KIND_TX_NOT_FOUND",
+		15: "ERROR_KIND_CONFLICT",
+		16: "ERROR_KIND_LOCKED",
+		17: "ERROR_KIND_LOCK_NOT_FOUND",
+		18: "ERROR_KIND_QUOTA_EXCEEDED",
+	}
+	ErrorKind_value = map[string]int32{
+		"ERROR_KIND_UNSPECIFIED":        0,
+		"ERROR_KIND_NOT_EXIST":          1,
+		"ERROR_KIND_EXIST":              2,
+		"ERROR_KIND_PERMISSION":         3,
+		"ERROR_KIND_INVALID_PATH":       4,
+		"ERROR_KIND_ACCESS_DENIED":      5,
+		"ERROR_KIND_INVALID":            6,
+		"ERROR_KIND_CLOSED":             7,
+		"ERROR_KIND_UNSUPPORTED":        8,
+		"ERROR_KIND_CHUNK_OUT_OF_ORDER": 9,
+		"ERROR_KIND_INCOMPLETE_STREAM":  10,
+		"ERROR_KIND_WALK_CYCLE":         11,
+		"ERROR_KIND_CANCELED":           12,
+		"ERROR_KIND_DEADLINE_EXCEEDED":  13,
+		"ERROR_KIND_TX_NOT_FOUND":       14,
+		"ERROR_KIND_CONFLICT":           15,
+		"ERROR_KIND_LOCKED":             16,
+		"ERROR_KIND_LOCK_NOT_FOUND":     17,
+		"ERROR_KIND_QUOTA_EXCEEDED":     18,
+	}
+)
+
+func (x ErrorKind) Enum() *ErrorKind {
+	p := new(ErrorKind)
+	*p = x
+	return p
+}
+
+func (x ErrorKind) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ErrorKind) Descriptor() protoreflect.EnumDescriptor {
+	return file_hostserve_v1_hostserve_proto_enumTypes[0].Descriptor()
+}
+
+func (ErrorKind) Type() protoreflect.EnumType {
+	return &file_hostserve_v1_hostserve_proto_enumTypes[0]
+}
+
+func (x ErrorKind) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ErrorKind.Descriptor instead.
+func (ErrorKind) EnumDescriptor() ([]byte, []int) {
+	return file_hostserve_v1_hostserve_proto_rawDescGZIP(), []int{0}
+}
+
+// LockMode is the mode of an advisory lock. Any number of shared locks on a path may be held at once, but an
+// exclusive lock excludes every other lock on it.
+type LockMode int32
+
+const (
+	LockMode_LOCK_MODE_UNSPECIFIED LockMode = 0
+	LockMode_LOCK_MODE_SHARED      LockMode = 1
+	LockMode_LOCK_MODE_EXCLUSIVE   LockMode = 2
+)
+
+// Enum value maps for LockMode.
+var (
+	LockMode_name = map[int32]string{
+		0: "LOCK_MODE_UNSPECIFIED",
+		1: "LOCK_MODE_SHARED",
+		2: "LOCK_MODE_EXCLUSIVE",
+	}
+	LockMode_value = map[string]int32{
+		"LOCK_MODE_UNSPECIFIED": 0,
+		"LOCK_MODE_SHARED":      1,
+		"LOCK_MODE_EXCLUSIVE":   2,
+	}
+)
+
+func (x LockMode) Enum() *LockMode {
+	p := new(LockMode)
+	*p = x
+	return p
+}
+
+func (x LockMode) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (LockMode) Descriptor() protoreflect.EnumDescriptor {
+	return file_hostserve_v1_hostserve_proto_enumTypes[1].Descriptor()
+}
+
+func (LockMode) Type() protoreflect.EnumType {
+	return &file_hostserve_v1_hostserve_proto_enumTypes[1]
+}
+
+func (x LockMode) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use LockMode.Descriptor instead.
+func (LockMode) EnumDescriptor() ([]byte, []int) {
+	return file_hostserve_v1_hostserve_proto_rawDescGZIP(), []int{1}
+}
+
+// ErrorDetail describes a failed host operation. Failed calls return a gRPC status whose code reflects the kind,
+// with an ErrorDetail attached as a status detail; errors affecting a single entry of a streamed result carry one
+// in-band instead. message is the full description of the error on the host.
+type ErrorDetail struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Kind          ErrorKind              `protobuf:"varint,1,opt,name=kind,proto3,enum=hostserve.v1.ErrorKind" json:"kind,omitempty"`
+	Op            string                 `protobuf:"bytes,2,opt,name=op,proto3" json:"op,omitempty"`
+	Path          string                 `protobuf:"bytes,3,opt,name=path,proto3" json:"path,omitempty"`
+	Message       string                 `protobuf:"bytes,4,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ErrorDetail) Reset() {
+	*x = ErrorDetail{}
+	mi := &file_hostserve_v1_hostserve_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ErrorDetail) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ErrorDetail) ProtoMessage() {}
+
+func (x *ErrorDetail) ProtoReflect() protoreflect.Message {
+	mi := &file_hostserve_v1_hostserve_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ErrorDetail.ProtoReflect.Descriptor instead.
+func (*ErrorDetail) Descriptor() ([]byte, []int) {
+	return file_hostserve_v1_hostserve_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *ErrorDetail) GetKind() ErrorKind {
+	if x != nil {
+		return x.Kind
+	}
+	return ErrorKind_ERROR_KIND_UNSPECIFIED
+}
+
+func (x *ErrorDetail) GetOp() string {
+	if x != nil {
+		return x.Op
+	}
+	return ""
+}
+
+func (x *ErrorDetail) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *ErrorDetail) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+// DirEntry represents a dir entry along with the metadata of the file it names.
+// mode holds Go fs.FileMode bits, and is_symlink is set when the entry itself is a symbolic link.
 type DirEntry struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
 	IsDir         bool                   `protobuf:"varint,2,opt,name=is_dir,json=isDir,proto3" json:"is_dir,omitempty"`
+	Size          int64                  `protobuf:"varint,3,opt,name=size,proto3" json:"size,omitempty"`
+	Mode          uint32                 `protobuf:"varint,4,opt,name=mode,proto3" json:"mode,omitempty"`
+	ModTime       *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=mod_time,json=modTime,proto3" json:"mod_time,omitempty"`
+	IsSymlink     bool                   `protobuf:"varint,6,opt,name=is_symlink,json=isSymlink,proto3" json:"is_symlink,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *DirEntry) Reset() {
 	*x = DirEntry{}
-	mi := &file_hostserve_v1_hostserve_proto_msgTypes[0]
+	mi := &file_hostserve_v1_hostserve_proto_msgTypes[1]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -44,7 +271,7 @@ func (x *DirEntry) String() string {
 func (*DirEntry) ProtoMessage() {}
 
 func (x *DirEntry) ProtoReflect() protoreflect.Message {
-	mi := &file_hostserve_v1_hostserve_proto_msgTypes[0]
+	mi := &file_hostserve_v1_hostserve_proto_msgTypes[1]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -57,7 +284,7 @@ func (x *DirEntry) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use DirEntry.ProtoReflect.Descriptor instead.
 func (*DirEntry) Descriptor() ([]byte, []int) {
-	return file_hostserve_v1_hostserve_proto_rawDescGZIP(), []int{0}
+	return file_hostserve_v1_hostserve_proto_rawDescGZIP(), []int{1}
 }
 
 func (x *DirEntry) GetName() string {
@@ -74,6 +301,111 @@ func (x *DirEntry) GetIsDir() bool {
 	return false
 }
 
+func (x *DirEntry) GetSize() int64 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
+func (x *DirEntry) GetMode() uint32 {
+	if x != nil {
+		return x.Mode
+	}
+	return 0
+}
+
+func (x *DirEntry) GetModTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ModTime
+	}
+	return nil
+}
+
+func (x *DirEntry) GetIsSymlink() bool {
+	if x != nil {
+		return x.IsSymlink
+	}
+	return false
+}
+
+// FileInfo describes a file as returned by Stat and Lstat. mode holds Go fs.FileMode bits.
+type FileInfo struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Size          int64                  `protobuf:"varint,2,opt,name=size,proto3" json:"size,omitempty"`
+	Mode          uint32                 `protobuf:"varint,3,opt,name=mode,proto3" json:"mode,omitempty"`
+	ModTime       *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=mod_time,json=modTime,proto3" json:"mod_time,omitempty"`
+	IsDir         bool                   `protobuf:"varint,5,opt,name=is_dir,json=isDir,proto3" json:"is_dir,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FileInfo) Reset() {
+	*x = FileInfo{}
+	mi := &file_hostserve_v1_hostserve_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FileInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FileInfo) ProtoMessage() {}
+
+func (x *FileInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_hostserve_v1_hostserve_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FileInfo.ProtoReflect.Descriptor instead.
+func (*FileInfo) Descriptor() ([]byte, []int) {
+	return file_hostserve_v1_hostserve_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *FileInfo) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *FileInfo) GetSize() int64 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
+func (x *FileInfo) GetMode() uint32 {
+	if x != nil {
+		return x.Mode
+	}
+	return 0
+}
+
+func (x *FileInfo) GetModTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ModTime
+	}
+	return nil
+}
+
+func (x *FileInfo) GetIsDir() bool {
+	if x != nil {
+		return x.IsDir
+	}
+	return false
+}
+
 type FileChunk struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Data          []byte                 `protobuf:"bytes,1,opt,name=data,proto3" json:"data,omitempty"`
@@ -85,7 +417,7 @@ type FileChunk struct {
 
 func (x *FileChunk) Reset() {
 	*x = FileChunk{}
-	mi := &file_hostserve_v1_hostserve_proto_msgTypes[1]
+	mi := &file_hostserve_v1_hostserve_proto_msgTypes[3]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -97,7 +429,7 @@ func (x *FileChunk) String() string {
 func (*FileChunk) ProtoMessage() {}
 
 func (x *FileChunk) ProtoReflect() protoreflect.Message {
-	mi := &file_hostserve_v1_hostserve_proto_msgTypes[1]
+	mi := &file_hostserve_v1_hostserve_proto_msgTypes[3]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -110,7 +442,7 @@ func (x *FileChunk) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use FileChunk.ProtoReflect.Descriptor instead.
 func (*FileChunk) Descriptor() ([]byte, []int) {
-	return file_hostserve_v1_hostserve_proto_rawDescGZIP(), []int{1}
+	return file_hostserve_v1_hostserve_proto_rawDescGZIP(), []int{3}
 }
 
 func (x *FileChunk) GetData() []byte {
@@ -120,43 +452,1941 @@ func (x *FileChunk) GetData() []byte {
 	return nil
 }
 
-func (x *FileChunk) GetOffset() uint64 {
-	if x != nil {
-		return x.Offset
-	}
-	return 0
+func (x *FileChunk) GetOffset() uint64 {
+	if x != nil {
+		return x.Offset
+	}
+	return 0
+}
+
+func (x *FileChunk) GetIsFinal() bool {
+	if x != nil {
+		return x.IsFinal
+	}
+	return false
+}
+
+type ReadFileChunk struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Chunk         *FileChunk             `protobuf:"bytes,1,opt,name=chunk,proto3" json:"chunk,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReadFileChunk) Reset() {
+	*x = ReadFileChunk{}
+	mi := &file_hostserve_v1_hostserve_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReadFileChunk) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReadFileChunk) ProtoMessage() {}
+
+func (x *ReadFileChunk) ProtoReflect() protoreflect.Message {
+	mi := &file_hostserve_v1_hostserve_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReadFileChunk.ProtoReflect.Descriptor instead.
+func (*ReadFileChunk) Descriptor() ([]byte, []int) {
+	return file_hostserve_v1_hostserve_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *ReadFileChunk) GetChunk() *FileChunk {
+	if x != nil {
+		return x.Chunk
+	}
+	return nil
+}
+
+// ReadDirChunk carries a batch of directory entries.
+type ReadDirChunk struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Entries       []*DirEntry            `protobuf:"bytes,1,rep,name=entries,proto3" json:"entries,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReadDirChunk) Reset() {
+	*x = ReadDirChunk{}
+	mi := &file_hostserve_v1_hostserve_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReadDirChunk) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReadDirChunk) ProtoMessage() {}
+
+func (x *ReadDirChunk) ProtoReflect() protoreflect.Message {
+	mi := &file_hostserve_v1_hostserve_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReadDirChunk.ProtoReflect.Descriptor instead.
+func (*ReadDirChunk) Descriptor() ([]byte, []int) {
+	return file_hostserve_v1_hostserve_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *ReadDirChunk) GetEntries() []*DirEntry {
+	if x != nil {
+		return x.Entries
+	}
+	return nil
+}
+
+// WriteFileChunk carries part of a streamed write. The path, permissions and transaction are taken from the first
+// chunk.
+type WriteFileChunk struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Path          string                 `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	Perm          uint32                 `protobuf:"varint,2,opt,name=perm,proto3" json:"perm,omitempty"`
+	Chunk         *FileChunk             `protobuf:"bytes,3,opt,name=chunk,proto3" json:"chunk,omitempty"`
+	TxId          string                 `protobuf:"bytes,4,opt,name=tx_id,json=txId,proto3" json:"tx_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WriteFileChunk) Reset() {
+	*x = WriteFileChunk{}
+	mi := &file_hostserve_v1_hostserve_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WriteFileChunk) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WriteFileChunk) ProtoMessage() {}
+
+func (x *WriteFileChunk) ProtoReflect() protoreflect.Message {
+	mi := &file_hostserve_v1_hostserve_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WriteFileChunk.ProtoReflect.Descriptor instead.
+func (*WriteFileChunk) Descriptor() ([]byte, []int) {
+	return file_hostserve_v1_hostserve_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *WriteFileChunk) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *WriteFileChunk) GetPerm() uint32 {
+	if x != nil {
+		return x.Perm
+	}
+	return 0
+}
+
+func (x *WriteFileChunk) GetChunk() *FileChunk {
+	if x != nil {
+		return x.Chunk
+	}
+	return nil
+}
+
+func (x *WriteFileChunk) GetTxId() string {
+	if x != nil {
+		return x.TxId
+	}
+	return ""
+}
+
+// WatchRequest starts watching path for changes, including every directory beneath it when recursive is set.
+type WatchRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Path          string                 `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	Recursive     bool                   `protobuf:"varint,2,opt,name=recursive,proto3" json:"recursive,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchRequest) Reset() {
+	*x = WatchRequest{}
+	mi := &file_hostserve_v1_hostserve_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchRequest) ProtoMessage() {}
+
+func (x *WatchRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_hostserve_v1_hostserve_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchRequest.ProtoReflect.Descriptor instead.
+func (*WatchRequest) Descriptor() ([]byte, []int) {
+	return file_hostserve_v1_hostserve_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *WatchRequest) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *WatchRequest) GetRecursive() bool {
+	if x != nil {
+		return x.Recursive
+	}
+	return false
+}
+
+// WatchEvent reports a coalesced set of changes to a single path. op holds hostserve.WatchOp bits.
+// The first event on a stream has no path or op and acknowledges that the watch is established.
+type WatchEvent struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Path          string                 `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	Op            uint32                 `protobuf:"varint,2,opt,name=op,proto3" json:"op,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchEvent) Reset() {
+	*x = WatchEvent{}
+	mi := &file_hostserve_v1_hostserve_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchEvent) ProtoMessage() {}
+
+func (x *WatchEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_hostserve_v1_hostserve_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchEvent.ProtoReflect.Descriptor instead.
+func (*WatchEvent) Descriptor() ([]byte, []int) {
+	return file_hostserve_v1_hostserve_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *WatchEvent) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *WatchEvent) GetOp() uint32 {
+	if x != nil {
+		return x.Op
+	}
+	return 0
+}
+
+// WalkRequest walks the tree rooted at path. A max_depth of zero or less walks the whole tree. skip holds doublestar
+// patterns for entries to leave out, along with everything beneath them.
+type WalkRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Path           string                 `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	MaxDepth       int32                  `protobuf:"varint,2,opt,name=max_depth,json=maxDepth,proto3" json:"max_depth,omitempty"`
+	FollowSymlinks bool                   `protobuf:"varint,3,opt,name=follow_symlinks,json=followSymlinks,proto3" json:"follow_symlinks,omitempty"`
+	Skip           []string               `protobuf:"bytes,4,rep,name=skip,proto3" json:"skip,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *WalkRequest) Reset() {
+	*x = WalkRequest{}
+	mi := &file_hostserve_v1_hostserve_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WalkRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WalkRequest) ProtoMessage() {}
+
+func (x *WalkRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_hostserve_v1_hostserve_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WalkRequest.ProtoReflect.Descriptor instead.
+func (*WalkRequest) Descriptor() ([]byte, []int) {
+	return file_hostserve_v1_hostserve_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *WalkRequest) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *WalkRequest) GetMaxDepth() int32 {
+	if x != nil {
+		return x.MaxDepth
+	}
+	return 0
+}
+
+func (x *WalkRequest) GetFollowSymlinks() bool {
+	if x != nil {
+		return x.FollowSymlinks
+	}
+	return false
+}
+
+func (x *WalkRequest) GetSkip() []string {
+	if x != nil {
+		return x.Skip
+	}
+	return nil
+}
+
+// WalkEntry is a single path visited by a walk. An error_detail on an entry applies to that entry alone, for example
+// a directory that could not be read, and the walk continues past it.
+type WalkEntry struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Path          string                 `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	Depth         int32                  `protobuf:"varint,2,opt,name=depth,proto3" json:"depth,omitempty"`
+	Info          *FileInfo              `protobuf:"bytes,3,opt,name=info,proto3" json:"info,omitempty"`
+	ErrorDetail   *ErrorDetail           `protobuf:"bytes,5,opt,name=error_detail,json=errorDetail,proto3" json:"error_detail,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WalkEntry) Reset() {
+	*x = WalkEntry{}
+	mi := &file_hostserve_v1_hostserve_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WalkEntry) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WalkEntry) ProtoMessage() {}
+
+func (x *WalkEntry) ProtoReflect() protoreflect.Message {
+	mi := &file_hostserve_v1_hostserve_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WalkEntry.ProtoReflect.Descriptor instead.
+func (*WalkEntry) Descriptor() ([]byte, []int) {
+	return file_hostserve_v1_hostserve_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *WalkEntry) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *WalkEntry) GetDepth() int32 {
+	if x != nil {
+		return x.Depth
+	}
+	return 0
+}
+
+func (x *WalkEntry) GetInfo() *FileInfo {
+	if x != nil {
+		return x.Info
+	}
+	return nil
+}
+
+func (x *WalkEntry) GetErrorDetail() *ErrorDetail {
+	if x != nil {
+		return x.ErrorDetail
+	}
+	return nil
+}
+
+// WalkChunk carries a batch of walked entries.
+type WalkChunk struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Entries       []*WalkEntry           `protobuf:"bytes,1,rep,name=entries,proto3" json:"entries,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WalkChunk) Reset() {
+	*x = WalkChunk{}
+	mi := &file_hostserve_v1_hostserve_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WalkChunk) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WalkChunk) ProtoMessage() {}
+
+func (x *WalkChunk) ProtoReflect() protoreflect.Message {
+	mi := &file_hostserve_v1_hostserve_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WalkChunk.ProtoReflect.Descriptor instead.
+func (*WalkChunk) Descriptor() ([]byte, []int) {
+	return file_hostserve_v1_hostserve_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *WalkChunk) GetEntries() []*WalkEntry {
+	if x != nil {
+		return x.Entries
+	}
+	return nil
+}
+
+type ReadDirRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Path          string                 `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReadDirRequest) Reset() {
+	*x = ReadDirRequest{}
+	mi := &file_hostserve_v1_hostserve_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReadDirRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReadDirRequest) ProtoMessage() {}
+
+func (x *ReadDirRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_hostserve_v1_hostserve_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReadDirRequest.ProtoReflect.Descriptor instead.
+func (*ReadDirRequest) Descriptor() ([]byte, []int) {
+	return file_hostserve_v1_hostserve_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *ReadDirRequest) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+type ReadDirResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Entries       []*DirEntry            `protobuf:"bytes,1,rep,name=entries,proto3" json:"entries,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReadDirResponse) Reset() {
+	*x = ReadDirResponse{}
+	mi := &file_hostserve_v1_hostserve_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReadDirResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReadDirResponse) ProtoMessage() {}
+
+func (x *ReadDirResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_hostserve_v1_hostserve_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReadDirResponse.ProtoReflect.Descriptor instead.
+func (*ReadDirResponse) Descriptor() ([]byte, []int) {
+	return file_hostserve_v1_hostserve_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *ReadDirResponse) GetEntries() []*DirEntry {
+	if x != nil {
+		return x.Entries
+	}
+	return nil
+}
+
+type ReadFileRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Path          string                 `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReadFileRequest) Reset() {
+	*x = ReadFileRequest{}
+	mi := &file_hostserve_v1_hostserve_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReadFileRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReadFileRequest) ProtoMessage() {}
+
+func (x *ReadFileRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_hostserve_v1_hostserve_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReadFileRequest.ProtoReflect.Descriptor instead.
+func (*ReadFileRequest) Descriptor() ([]byte, []int) {
+	return file_hostserve_v1_hostserve_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *ReadFileRequest) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+type ReadFileResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Contents      []byte                 `protobuf:"bytes,1,opt,name=contents,proto3" json:"contents,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReadFileResponse) Reset() {
+	*x = ReadFileResponse{}
+	mi := &file_hostserve_v1_hostserve_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReadFileResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReadFileResponse) ProtoMessage() {}
+
+func (x *ReadFileResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_hostserve_v1_hostserve_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReadFileResponse.ProtoReflect.Descriptor instead.
+func (*ReadFileResponse) Descriptor() ([]byte, []int) {
+	return file_hostserve_v1_hostserve_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *ReadFileResponse) GetContents() []byte {
+	if x != nil {
+		return x.Contents
+	}
+	return nil
+}
+
+// WriteFileRequest writes a file, staging the write in the transaction named by tx_id if it is set. If a
+// precondition is set the file is only written if it currently satisfies it.
+type WriteFileRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Path          string                 `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	Data          []byte                 `protobuf:"bytes,2,opt,name=data,proto3" json:"data,omitempty"`
+	Perm          uint32                 `protobuf:"varint,3,opt,name=perm,proto3" json:"perm,omitempty"`
+	TxId          string                 `protobuf:"bytes,4,opt,name=tx_id,json=txId,proto3" json:"tx_id,omitempty"`
+	Precondition  *WritePrecondition     `protobuf:"bytes,5,opt,name=precondition,proto3" json:"precondition,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WriteFileRequest) Reset() {
+	*x = WriteFileRequest{}
+	mi := &file_hostserve_v1_hostserve_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WriteFileRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WriteFileRequest) ProtoMessage() {}
+
+func (x *WriteFileRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_hostserve_v1_hostserve_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WriteFileRequest.ProtoReflect.Descriptor instead.
+func (*WriteFileRequest) Descriptor() ([]byte, []int) {
+	return file_hostserve_v1_hostserve_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *WriteFileRequest) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *WriteFileRequest) GetData() []byte {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+func (x *WriteFileRequest) GetPerm() uint32 {
+	if x != nil {
+		return x.Perm
+	}
+	return 0
+}
+
+func (x *WriteFileRequest) GetTxId() string {
+	if x != nil {
+		return x.TxId
+	}
+	return ""
+}
+
+func (x *WriteFileRequest) GetPrecondition() *WritePrecondition {
+	if x != nil {
+		return x.Precondition
+	}
+	return nil
+}
+
+// WritePrecondition is what a file must satisfy before a conditional write replaces it. Every condition that is set
+// must hold.
+type WritePrecondition struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// mod_time, if set, is the modification time the file must have.
+	ModTime *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=mod_time,json=modTime,proto3" json:"mod_time,omitempty"`
+	// sha256, if set, is the SHA-256 digest the file's contents must have.
+	Sha256 []byte `protobuf:"bytes,2,opt,name=sha256,proto3" json:"sha256,omitempty"`
+	// must_not_exist requires that nothing exist at the path.
+	MustNotExist  bool `protobuf:"varint,3,opt,name=must_not_exist,json=mustNotExist,proto3" json:"must_not_exist,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WritePrecondition) Reset() {
+	*x = WritePrecondition{}
+	mi := &file_hostserve_v1_hostserve_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WritePrecondition) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WritePrecondition) ProtoMessage() {}
+
+func (x *WritePrecondition) ProtoReflect() protoreflect.Message {
+	mi := &file_hostserve_v1_hostserve_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WritePrecondition.ProtoReflect.Descriptor instead.
+func (*WritePrecondition) Descriptor() ([]byte, []int) {
+	return file_hostserve_v1_hostserve_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *WritePrecondition) GetModTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ModTime
+	}
+	return nil
+}
+
+func (x *WritePrecondition) GetSha256() []byte {
+	if x != nil {
+		return x.Sha256
+	}
+	return nil
+}
+
+func (x *WritePrecondition) GetMustNotExist() bool {
+	if x != nil {
+		return x.MustNotExist
+	}
+	return false
+}
+
+type WriteFileResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WriteFileResponse) Reset() {
+	*x = WriteFileResponse{}
+	mi := &file_hostserve_v1_hostserve_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WriteFileResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WriteFileResponse) ProtoMessage() {}
+
+func (x *WriteFileResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_hostserve_v1_hostserve_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WriteFileResponse.ProtoReflect.Descriptor instead.
+func (*WriteFileResponse) Descriptor() ([]byte, []int) {
+	return file_hostserve_v1_hostserve_proto_rawDescGZIP(), []int{18}
+}
+
+type StatRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Path          string                 `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StatRequest) Reset() {
+	*x = StatRequest{}
+	mi := &file_hostserve_v1_hostserve_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StatRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StatRequest) ProtoMessage() {}
+
+func (x *StatRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_hostserve_v1_hostserve_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StatRequest.ProtoReflect.Descriptor instead.
+func (*StatRequest) Descriptor() ([]byte, []int) {
+	return file_hostserve_v1_hostserve_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *StatRequest) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+type StatResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Info          *FileInfo              `protobuf:"bytes,1,opt,name=info,proto3" json:"info,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StatResponse) Reset() {
+	*x = StatResponse{}
+	mi := &file_hostserve_v1_hostserve_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StatResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StatResponse) ProtoMessage() {}
+
+func (x *StatResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_hostserve_v1_hostserve_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StatResponse.ProtoReflect.Descriptor instead.
+func (*StatResponse) Descriptor() ([]byte, []int) {
+	return file_hostserve_v1_hostserve_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *StatResponse) GetInfo() *FileInfo {
+	if x != nil {
+		return x.Info
+	}
+	return nil
+}
+
+type GlobRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Pattern       string                 `protobuf:"bytes,1,opt,name=pattern,proto3" json:"pattern,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GlobRequest) Reset() {
+	*x = GlobRequest{}
+	mi := &file_hostserve_v1_hostserve_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GlobRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GlobRequest) ProtoMessage() {}
+
+func (x *GlobRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_hostserve_v1_hostserve_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GlobRequest.ProtoReflect.Descriptor instead.
+func (*GlobRequest) Descriptor() ([]byte, []int) {
+	return file_hostserve_v1_hostserve_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *GlobRequest) GetPattern() string {
+	if x != nil {
+		return x.Pattern
+	}
+	return ""
+}
+
+type GlobResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Matches       []string               `protobuf:"bytes,1,rep,name=matches,proto3" json:"matches,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GlobResponse) Reset() {
+	*x = GlobResponse{}
+	mi := &file_hostserve_v1_hostserve_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GlobResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GlobResponse) ProtoMessage() {}
+
+func (x *GlobResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_hostserve_v1_hostserve_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GlobResponse.ProtoReflect.Descriptor instead.
+func (*GlobResponse) Descriptor() ([]byte, []int) {
+	return file_hostserve_v1_hostserve_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *GlobResponse) GetMatches() []string {
+	if x != nil {
+		return x.Matches
+	}
+	return nil
+}
+
+type MkdirAllRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Path          string                 `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	Perm          uint32                 `protobuf:"varint,2,opt,name=perm,proto3" json:"perm,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MkdirAllRequest) Reset() {
+	*x = MkdirAllRequest{}
+	mi := &file_hostserve_v1_hostserve_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MkdirAllRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MkdirAllRequest) ProtoMessage() {}
+
+func (x *MkdirAllRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_hostserve_v1_hostserve_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MkdirAllRequest.ProtoReflect.Descriptor instead.
+func (*MkdirAllRequest) Descriptor() ([]byte, []int) {
+	return file_hostserve_v1_hostserve_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *MkdirAllRequest) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *MkdirAllRequest) GetPerm() uint32 {
+	if x != nil {
+		return x.Perm
+	}
+	return 0
+}
+
+type MkdirAllResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MkdirAllResponse) Reset() {
+	*x = MkdirAllResponse{}
+	mi := &file_hostserve_v1_hostserve_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MkdirAllResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MkdirAllResponse) ProtoMessage() {}
+
+func (x *MkdirAllResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_hostserve_v1_hostserve_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MkdirAllResponse.ProtoReflect.Descriptor instead.
+func (*MkdirAllResponse) Descriptor() ([]byte, []int) {
+	return file_hostserve_v1_hostserve_proto_rawDescGZIP(), []int{24}
+}
+
+type RemoveRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Path          string                 `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RemoveRequest) Reset() {
+	*x = RemoveRequest{}
+	mi := &file_hostserve_v1_hostserve_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RemoveRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RemoveRequest) ProtoMessage() {}
+
+func (x *RemoveRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_hostserve_v1_hostserve_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RemoveRequest.ProtoReflect.Descriptor instead.
+func (*RemoveRequest) Descriptor() ([]byte, []int) {
+	return file_hostserve_v1_hostserve_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *RemoveRequest) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+type RemoveResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RemoveResponse) Reset() {
+	*x = RemoveResponse{}
+	mi := &file_hostserve_v1_hostserve_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RemoveResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RemoveResponse) ProtoMessage() {}
+
+func (x *RemoveResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_hostserve_v1_hostserve_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RemoveResponse.ProtoReflect.Descriptor instead.
+func (*RemoveResponse) Descriptor() ([]byte, []int) {
+	return file_hostserve_v1_hostserve_proto_rawDescGZIP(), []int{26}
+}
+
+type RemoveAllRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Path          string                 `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RemoveAllRequest) Reset() {
+	*x = RemoveAllRequest{}
+	mi := &file_hostserve_v1_hostserve_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RemoveAllRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RemoveAllRequest) ProtoMessage() {}
+
+func (x *RemoveAllRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_hostserve_v1_hostserve_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RemoveAllRequest.ProtoReflect.Descriptor instead.
+func (*RemoveAllRequest) Descriptor() ([]byte, []int) {
+	return file_hostserve_v1_hostserve_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *RemoveAllRequest) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+type RemoveAllResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RemoveAllResponse) Reset() {
+	*x = RemoveAllResponse{}
+	mi := &file_hostserve_v1_hostserve_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RemoveAllResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RemoveAllResponse) ProtoMessage() {}
+
+func (x *RemoveAllResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_hostserve_v1_hostserve_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RemoveAllResponse.ProtoReflect.Descriptor instead.
+func (*RemoveAllResponse) Descriptor() ([]byte, []int) {
+	return file_hostserve_v1_hostserve_proto_rawDescGZIP(), []int{28}
+}
+
+type RenameRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	OldPath       string                 `protobuf:"bytes,1,opt,name=old_path,json=oldPath,proto3" json:"old_path,omitempty"`
+	NewPath       string                 `protobuf:"bytes,2,opt,name=new_path,json=newPath,proto3" json:"new_path,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RenameRequest) Reset() {
+	*x = RenameRequest{}
+	mi := &file_hostserve_v1_hostserve_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RenameRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RenameRequest) ProtoMessage() {}
+
+func (x *RenameRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_hostserve_v1_hostserve_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RenameRequest.ProtoReflect.Descriptor instead.
+func (*RenameRequest) Descriptor() ([]byte, []int) {
+	return file_hostserve_v1_hostserve_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *RenameRequest) GetOldPath() string {
+	if x != nil {
+		return x.OldPath
+	}
+	return ""
+}
+
+func (x *RenameRequest) GetNewPath() string {
+	if x != nil {
+		return x.NewPath
+	}
+	return ""
+}
+
+type RenameResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RenameResponse) Reset() {
+	*x = RenameResponse{}
+	mi := &file_hostserve_v1_hostserve_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RenameResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RenameResponse) ProtoMessage() {}
+
+func (x *RenameResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_hostserve_v1_hostserve_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RenameResponse.ProtoReflect.Descriptor instead.
+func (*RenameResponse) Descriptor() ([]byte, []int) {
+	return file_hostserve_v1_hostserve_proto_rawDescGZIP(), []int{30}
+}
+
+// CopyRequest copies a file, staging the write of dst in the transaction named by tx_id if it is set.
+type CopyRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Src           string                 `protobuf:"bytes,1,opt,name=src,proto3" json:"src,omitempty"`
+	Dst           string                 `protobuf:"bytes,2,opt,name=dst,proto3" json:"dst,omitempty"`
+	TxId          string                 `protobuf:"bytes,3,opt,name=tx_id,json=txId,proto3" json:"tx_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CopyRequest) Reset() {
+	*x = CopyRequest{}
+	mi := &file_hostserve_v1_hostserve_proto_msgTypes[31]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CopyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CopyRequest) ProtoMessage() {}
+
+func (x *CopyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_hostserve_v1_hostserve_proto_msgTypes[31]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CopyRequest.ProtoReflect.Descriptor instead.
+func (*CopyRequest) Descriptor() ([]byte, []int) {
+	return file_hostserve_v1_hostserve_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *CopyRequest) GetSrc() string {
+	if x != nil {
+		return x.Src
+	}
+	return ""
+}
+
+func (x *CopyRequest) GetDst() string {
+	if x != nil {
+		return x.Dst
+	}
+	return ""
+}
+
+func (x *CopyRequest) GetTxId() string {
+	if x != nil {
+		return x.TxId
+	}
+	return ""
+}
+
+type CopyResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	BytesCopied   int64                  `protobuf:"varint,1,opt,name=bytes_copied,json=bytesCopied,proto3" json:"bytes_copied,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CopyResponse) Reset() {
+	*x = CopyResponse{}
+	mi := &file_hostserve_v1_hostserve_proto_msgTypes[32]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CopyResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CopyResponse) ProtoMessage() {}
+
+func (x *CopyResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_hostserve_v1_hostserve_proto_msgTypes[32]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CopyResponse.ProtoReflect.Descriptor instead.
+func (*CopyResponse) Descriptor() ([]byte, []int) {
+	return file_hostserve_v1_hostserve_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *CopyResponse) GetBytesCopied() int64 {
+	if x != nil {
+		return x.BytesCopied
+	}
+	return 0
+}
+
+type ChmodRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Path          string                 `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	Mode          uint32                 `protobuf:"varint,2,opt,name=mode,proto3" json:"mode,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ChmodRequest) Reset() {
+	*x = ChmodRequest{}
+	mi := &file_hostserve_v1_hostserve_proto_msgTypes[33]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ChmodRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChmodRequest) ProtoMessage() {}
+
+func (x *ChmodRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_hostserve_v1_hostserve_proto_msgTypes[33]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ChmodRequest.ProtoReflect.Descriptor instead.
+func (*ChmodRequest) Descriptor() ([]byte, []int) {
+	return file_hostserve_v1_hostserve_proto_rawDescGZIP(), []int{33}
+}
+
+func (x *ChmodRequest) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *ChmodRequest) GetMode() uint32 {
+	if x != nil {
+		return x.Mode
+	}
+	return 0
+}
+
+type ChmodResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ChmodResponse) Reset() {
+	*x = ChmodResponse{}
+	mi := &file_hostserve_v1_hostserve_proto_msgTypes[34]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ChmodResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChmodResponse) ProtoMessage() {}
+
+func (x *ChmodResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_hostserve_v1_hostserve_proto_msgTypes[34]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ChmodResponse.ProtoReflect.Descriptor instead.
+func (*ChmodResponse) Descriptor() ([]byte, []int) {
+	return file_hostserve_v1_hostserve_proto_rawDescGZIP(), []int{34}
+}
+
+type TruncateRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Path          string                 `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	Size          int64                  `protobuf:"varint,2,opt,name=size,proto3" json:"size,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TruncateRequest) Reset() {
+	*x = TruncateRequest{}
+	mi := &file_hostserve_v1_hostserve_proto_msgTypes[35]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TruncateRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TruncateRequest) ProtoMessage() {}
+
+func (x *TruncateRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_hostserve_v1_hostserve_proto_msgTypes[35]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TruncateRequest.ProtoReflect.Descriptor instead.
+func (*TruncateRequest) Descriptor() ([]byte, []int) {
+	return file_hostserve_v1_hostserve_proto_rawDescGZIP(), []int{35}
+}
+
+func (x *TruncateRequest) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *TruncateRequest) GetSize() int64 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
+type TruncateResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TruncateResponse) Reset() {
+	*x = TruncateResponse{}
+	mi := &file_hostserve_v1_hostserve_proto_msgTypes[36]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TruncateResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TruncateResponse) ProtoMessage() {}
+
+func (x *TruncateResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_hostserve_v1_hostserve_proto_msgTypes[36]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TruncateResponse.ProtoReflect.Descriptor instead.
+func (*TruncateResponse) Descriptor() ([]byte, []int) {
+	return file_hostserve_v1_hostserve_proto_rawDescGZIP(), []int{36}
+}
+
+type AppendRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Path          string                 `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	Data          []byte                 `protobuf:"bytes,2,opt,name=data,proto3" json:"data,omitempty"`
+	Perm          uint32                 `protobuf:"varint,3,opt,name=perm,proto3" json:"perm,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AppendRequest) Reset() {
+	*x = AppendRequest{}
+	mi := &file_hostserve_v1_hostserve_proto_msgTypes[37]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AppendRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AppendRequest) ProtoMessage() {}
+
+func (x *AppendRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_hostserve_v1_hostserve_proto_msgTypes[37]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AppendRequest.ProtoReflect.Descriptor instead.
+func (*AppendRequest) Descriptor() ([]byte, []int) {
+	return file_hostserve_v1_hostserve_proto_rawDescGZIP(), []int{37}
+}
+
+func (x *AppendRequest) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *AppendRequest) GetData() []byte {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+func (x *AppendRequest) GetPerm() uint32 {
+	if x != nil {
+		return x.Perm
+	}
+	return 0
+}
+
+type AppendResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AppendResponse) Reset() {
+	*x = AppendResponse{}
+	mi := &file_hostserve_v1_hostserve_proto_msgTypes[38]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AppendResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AppendResponse) ProtoMessage() {}
+
+func (x *AppendResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_hostserve_v1_hostserve_proto_msgTypes[38]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AppendResponse.ProtoReflect.Descriptor instead.
+func (*AppendResponse) Descriptor() ([]byte, []int) {
+	return file_hostserve_v1_hostserve_proto_rawDescGZIP(), []int{38}
+}
+
+type BeginTxRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BeginTxRequest) Reset() {
+	*x = BeginTxRequest{}
+	mi := &file_hostserve_v1_hostserve_proto_msgTypes[39]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BeginTxRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BeginTxRequest) ProtoMessage() {}
+
+func (x *BeginTxRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_hostserve_v1_hostserve_proto_msgTypes[39]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BeginTxRequest.ProtoReflect.Descriptor instead.
+func (*BeginTxRequest) Descriptor() ([]byte, []int) {
+	return file_hostserve_v1_hostserve_proto_rawDescGZIP(), []int{39}
+}
+
+// BeginTxResponse names the transaction that was started.
+type BeginTxResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TxId          string                 `protobuf:"bytes,1,opt,name=tx_id,json=txId,proto3" json:"tx_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BeginTxResponse) Reset() {
+	*x = BeginTxResponse{}
+	mi := &file_hostserve_v1_hostserve_proto_msgTypes[40]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BeginTxResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BeginTxResponse) ProtoMessage() {}
+
+func (x *BeginTxResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_hostserve_v1_hostserve_proto_msgTypes[40]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BeginTxResponse.ProtoReflect.Descriptor instead.
+func (*BeginTxResponse) Descriptor() ([]byte, []int) {
+	return file_hostserve_v1_hostserve_proto_rawDescGZIP(), []int{40}
+}
+
+func (x *BeginTxResponse) GetTxId() string {
+	if x != nil {
+		return x.TxId
+	}
+	return ""
+}
+
+type CommitTxRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TxId          string                 `protobuf:"bytes,1,opt,name=tx_id,json=txId,proto3" json:"tx_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CommitTxRequest) Reset() {
+	*x = CommitTxRequest{}
+	mi := &file_hostserve_v1_hostserve_proto_msgTypes[41]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CommitTxRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CommitTxRequest) ProtoMessage() {}
+
+func (x *CommitTxRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_hostserve_v1_hostserve_proto_msgTypes[41]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CommitTxRequest.ProtoReflect.Descriptor instead.
+func (*CommitTxRequest) Descriptor() ([]byte, []int) {
+	return file_hostserve_v1_hostserve_proto_rawDescGZIP(), []int{41}
+}
+
+func (x *CommitTxRequest) GetTxId() string {
+	if x != nil {
+		return x.TxId
+	}
+	return ""
+}
+
+type CommitTxResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CommitTxResponse) Reset() {
+	*x = CommitTxResponse{}
+	mi := &file_hostserve_v1_hostserve_proto_msgTypes[42]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CommitTxResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CommitTxResponse) ProtoMessage() {}
+
+func (x *CommitTxResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_hostserve_v1_hostserve_proto_msgTypes[42]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CommitTxResponse.ProtoReflect.Descriptor instead.
+func (*CommitTxResponse) Descriptor() ([]byte, []int) {
+	return file_hostserve_v1_hostserve_proto_rawDescGZIP(), []int{42}
+}
+
+type RollbackTxRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TxId          string                 `protobuf:"bytes,1,opt,name=tx_id,json=txId,proto3" json:"tx_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RollbackTxRequest) Reset() {
+	*x = RollbackTxRequest{}
+	mi := &file_hostserve_v1_hostserve_proto_msgTypes[43]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RollbackTxRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RollbackTxRequest) ProtoMessage() {}
+
+func (x *RollbackTxRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_hostserve_v1_hostserve_proto_msgTypes[43]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RollbackTxRequest.ProtoReflect.Descriptor instead.
+func (*RollbackTxRequest) Descriptor() ([]byte, []int) {
+	return file_hostserve_v1_hostserve_proto_rawDescGZIP(), []int{43}
 }
 
-func (x *FileChunk) GetIsFinal() bool {
+func (x *RollbackTxRequest) GetTxId() string {
 	if x != nil {
-		return x.IsFinal
+		return x.TxId
 	}
-	return false
+	return ""
 }
 
-type ReadFileChunk struct {
+type RollbackTxResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Chunk         *FileChunk             `protobuf:"bytes,1,opt,name=chunk,proto3" json:"chunk,omitempty"`
-	Error         *string                `protobuf:"bytes,2,opt,name=error,proto3,oneof" json:"error,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ReadFileChunk) Reset() {
-	*x = ReadFileChunk{}
-	mi := &file_hostserve_v1_hostserve_proto_msgTypes[2]
+func (x *RollbackTxResponse) Reset() {
+	*x = RollbackTxResponse{}
+	mi := &file_hostserve_v1_hostserve_proto_msgTypes[44]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ReadFileChunk) String() string {
+func (x *RollbackTxResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ReadFileChunk) ProtoMessage() {}
+func (*RollbackTxResponse) ProtoMessage() {}
 
-func (x *ReadFileChunk) ProtoReflect() protoreflect.Message {
-	mi := &file_hostserve_v1_hostserve_proto_msgTypes[2]
+func (x *RollbackTxResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_hostserve_v1_hostserve_proto_msgTypes[44]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -167,49 +2397,37 @@ func (x *ReadFileChunk) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ReadFileChunk.ProtoReflect.Descriptor instead.
-func (*ReadFileChunk) Descriptor() ([]byte, []int) {
-	return file_hostserve_v1_hostserve_proto_rawDescGZIP(), []int{2}
-}
-
-func (x *ReadFileChunk) GetChunk() *FileChunk {
-	if x != nil {
-		return x.Chunk
-	}
-	return nil
-}
-
-func (x *ReadFileChunk) GetError() string {
-	if x != nil && x.Error != nil {
-		return *x.Error
-	}
-	return ""
+// Deprecated: Use RollbackTxResponse.ProtoReflect.Descriptor instead.
+func (*RollbackTxResponse) Descriptor() ([]byte, []int) {
+	return file_hostserve_v1_hostserve_proto_rawDescGZIP(), []int{44}
 }
 
-type WriteFileChunk struct {
+// LockRequest acquires an advisory lock on a path for the given lease, after which it is released if it has not
+// been unlocked. A missing lease uses the host's default.
+type LockRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Path          string                 `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
-	Perm          uint32                 `protobuf:"varint,2,opt,name=perm,proto3" json:"perm,omitempty"`
-	Chunk         *FileChunk             `protobuf:"bytes,3,opt,name=chunk,proto3" json:"chunk,omitempty"`
+	Mode          LockMode               `protobuf:"varint,2,opt,name=mode,proto3,enum=hostserve.v1.LockMode" json:"mode,omitempty"`
+	Lease         *durationpb.Duration   `protobuf:"bytes,3,opt,name=lease,proto3" json:"lease,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *WriteFileChunk) Reset() {
-	*x = WriteFileChunk{}
-	mi := &file_hostserve_v1_hostserve_proto_msgTypes[3]
+func (x *LockRequest) Reset() {
+	*x = LockRequest{}
+	mi := &file_hostserve_v1_hostserve_proto_msgTypes[45]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *WriteFileChunk) String() string {
+func (x *LockRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*WriteFileChunk) ProtoMessage() {}
+func (*LockRequest) ProtoMessage() {}
 
-func (x *WriteFileChunk) ProtoReflect() protoreflect.Message {
-	mi := &file_hostserve_v1_hostserve_proto_msgTypes[3]
+func (x *LockRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_hostserve_v1_hostserve_proto_msgTypes[45]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -220,54 +2438,57 @@ func (x *WriteFileChunk) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use WriteFileChunk.ProtoReflect.Descriptor instead.
-func (*WriteFileChunk) Descriptor() ([]byte, []int) {
-	return file_hostserve_v1_hostserve_proto_rawDescGZIP(), []int{3}
+// Deprecated: Use LockRequest.ProtoReflect.Descriptor instead.
+func (*LockRequest) Descriptor() ([]byte, []int) {
+	return file_hostserve_v1_hostserve_proto_rawDescGZIP(), []int{45}
 }
 
-func (x *WriteFileChunk) GetPath() string {
+func (x *LockRequest) GetPath() string {
 	if x != nil {
 		return x.Path
 	}
 	return ""
 }
 
-func (x *WriteFileChunk) GetPerm() uint32 {
+func (x *LockRequest) GetMode() LockMode {
 	if x != nil {
-		return x.Perm
+		return x.Mode
 	}
-	return 0
+	return LockMode_LOCK_MODE_UNSPECIFIED
 }
 
-func (x *WriteFileChunk) GetChunk() *FileChunk {
+func (x *LockRequest) GetLease() *durationpb.Duration {
 	if x != nil {
-		return x.Chunk
+		return x.Lease
 	}
 	return nil
 }
 
-type ReadDirRequest struct {
+// LockResponse names the lock that was acquired and when its lease runs out, which may be sooner than requested if
+// the host caps leases.
+type LockResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Path          string                 `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	LockId        string                 `protobuf:"bytes,1,opt,name=lock_id,json=lockId,proto3" json:"lock_id,omitempty"`
+	Expires       *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=expires,proto3" json:"expires,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ReadDirRequest) Reset() {
-	*x = ReadDirRequest{}
-	mi := &file_hostserve_v1_hostserve_proto_msgTypes[4]
+func (x *LockResponse) Reset() {
+	*x = LockResponse{}
+	mi := &file_hostserve_v1_hostserve_proto_msgTypes[46]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ReadDirRequest) String() string {
+func (x *LockResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ReadDirRequest) ProtoMessage() {}
+func (*LockResponse) ProtoMessage() {}
 
-func (x *ReadDirRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_hostserve_v1_hostserve_proto_msgTypes[4]
+func (x *LockResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_hostserve_v1_hostserve_proto_msgTypes[46]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -278,41 +2499,47 @@ func (x *ReadDirRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ReadDirRequest.ProtoReflect.Descriptor instead.
-func (*ReadDirRequest) Descriptor() ([]byte, []int) {
-	return file_hostserve_v1_hostserve_proto_rawDescGZIP(), []int{4}
+// Deprecated: Use LockResponse.ProtoReflect.Descriptor instead.
+func (*LockResponse) Descriptor() ([]byte, []int) {
+	return file_hostserve_v1_hostserve_proto_rawDescGZIP(), []int{46}
 }
 
-func (x *ReadDirRequest) GetPath() string {
+func (x *LockResponse) GetLockId() string {
 	if x != nil {
-		return x.Path
+		return x.LockId
 	}
 	return ""
 }
 
-type ReadDirResponse struct {
+func (x *LockResponse) GetExpires() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Expires
+	}
+	return nil
+}
+
+type UnlockRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Entries       []*DirEntry            `protobuf:"bytes,1,rep,name=entries,proto3" json:"entries,omitempty"`
-	Error         *string                `protobuf:"bytes,2,opt,name=error,proto3,oneof" json:"error,omitempty"`
+	LockId        string                 `protobuf:"bytes,1,opt,name=lock_id,json=lockId,proto3" json:"lock_id,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ReadDirResponse) Reset() {
-	*x = ReadDirResponse{}
-	mi := &file_hostserve_v1_hostserve_proto_msgTypes[5]
+func (x *UnlockRequest) Reset() {
+	*x = UnlockRequest{}
+	mi := &file_hostserve_v1_hostserve_proto_msgTypes[47]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ReadDirResponse) String() string {
+func (x *UnlockRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ReadDirResponse) ProtoMessage() {}
+func (*UnlockRequest) ProtoMessage() {}
 
-func (x *ReadDirResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_hostserve_v1_hostserve_proto_msgTypes[5]
+func (x *UnlockRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_hostserve_v1_hostserve_proto_msgTypes[47]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -323,47 +2550,39 @@ func (x *ReadDirResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ReadDirResponse.ProtoReflect.Descriptor instead.
-func (*ReadDirResponse) Descriptor() ([]byte, []int) {
-	return file_hostserve_v1_hostserve_proto_rawDescGZIP(), []int{5}
+// Deprecated: Use UnlockRequest.ProtoReflect.Descriptor instead.
+func (*UnlockRequest) Descriptor() ([]byte, []int) {
+	return file_hostserve_v1_hostserve_proto_rawDescGZIP(), []int{47}
 }
 
-func (x *ReadDirResponse) GetEntries() []*DirEntry {
+func (x *UnlockRequest) GetLockId() string {
 	if x != nil {
-		return x.Entries
-	}
-	return nil
-}
-
-func (x *ReadDirResponse) GetError() string {
-	if x != nil && x.Error != nil {
-		return *x.Error
+		return x.LockId
 	}
 	return ""
 }
 
-type ReadFileRequest struct {
+type UnlockResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Path          string                 `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ReadFileRequest) Reset() {
-	*x = ReadFileRequest{}
-	mi := &file_hostserve_v1_hostserve_proto_msgTypes[6]
+func (x *UnlockResponse) Reset() {
+	*x = UnlockResponse{}
+	mi := &file_hostserve_v1_hostserve_proto_msgTypes[48]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ReadFileRequest) String() string {
+func (x *UnlockResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ReadFileRequest) ProtoMessage() {}
+func (*UnlockResponse) ProtoMessage() {}
 
-func (x *ReadFileRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_hostserve_v1_hostserve_proto_msgTypes[6]
+func (x *UnlockResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_hostserve_v1_hostserve_proto_msgTypes[48]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -374,41 +2593,33 @@ func (x *ReadFileRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ReadFileRequest.ProtoReflect.Descriptor instead.
-func (*ReadFileRequest) Descriptor() ([]byte, []int) {
-	return file_hostserve_v1_hostserve_proto_rawDescGZIP(), []int{6}
-}
-
-func (x *ReadFileRequest) GetPath() string {
-	if x != nil {
-		return x.Path
-	}
-	return ""
+// Deprecated: Use UnlockResponse.ProtoReflect.Descriptor instead.
+func (*UnlockResponse) Descriptor() ([]byte, []int) {
+	return file_hostserve_v1_hostserve_proto_rawDescGZIP(), []int{48}
 }
 
-type ReadFileResponse struct {
+type GetEnvRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Contents      []byte                 `protobuf:"bytes,1,opt,name=contents,proto3" json:"contents,omitempty"`
-	Error         *string                `protobuf:"bytes,2,opt,name=error,proto3,oneof" json:"error,omitempty"`
+	Key           string                 `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ReadFileResponse) Reset() {
-	*x = ReadFileResponse{}
-	mi := &file_hostserve_v1_hostserve_proto_msgTypes[7]
+func (x *GetEnvRequest) Reset() {
+	*x = GetEnvRequest{}
+	mi := &file_hostserve_v1_hostserve_proto_msgTypes[49]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ReadFileResponse) String() string {
+func (x *GetEnvRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ReadFileResponse) ProtoMessage() {}
+func (*GetEnvRequest) ProtoMessage() {}
 
-func (x *ReadFileResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_hostserve_v1_hostserve_proto_msgTypes[7]
+func (x *GetEnvRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_hostserve_v1_hostserve_proto_msgTypes[49]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -419,49 +2630,40 @@ func (x *ReadFileResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ReadFileResponse.ProtoReflect.Descriptor instead.
-func (*ReadFileResponse) Descriptor() ([]byte, []int) {
-	return file_hostserve_v1_hostserve_proto_rawDescGZIP(), []int{7}
+// Deprecated: Use GetEnvRequest.ProtoReflect.Descriptor instead.
+func (*GetEnvRequest) Descriptor() ([]byte, []int) {
+	return file_hostserve_v1_hostserve_proto_rawDescGZIP(), []int{49}
 }
 
-func (x *ReadFileResponse) GetContents() []byte {
+func (x *GetEnvRequest) GetKey() string {
 	if x != nil {
-		return x.Contents
-	}
-	return nil
-}
-
-func (x *ReadFileResponse) GetError() string {
-	if x != nil && x.Error != nil {
-		return *x.Error
+		return x.Key
 	}
 	return ""
 }
 
-type WriteFileRequest struct {
+type GetEnvResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Path          string                 `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
-	Data          []byte                 `protobuf:"bytes,2,opt,name=data,proto3" json:"data,omitempty"`
-	Perm          uint32                 `protobuf:"varint,3,opt,name=perm,proto3" json:"perm,omitempty"`
+	Val           string                 `protobuf:"bytes,1,opt,name=val,proto3" json:"val,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *WriteFileRequest) Reset() {
-	*x = WriteFileRequest{}
-	mi := &file_hostserve_v1_hostserve_proto_msgTypes[8]
+func (x *GetEnvResponse) Reset() {
+	*x = GetEnvResponse{}
+	mi := &file_hostserve_v1_hostserve_proto_msgTypes[50]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *WriteFileRequest) String() string {
+func (x *GetEnvResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*WriteFileRequest) ProtoMessage() {}
+func (*GetEnvResponse) ProtoMessage() {}
 
-func (x *WriteFileRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_hostserve_v1_hostserve_proto_msgTypes[8]
+func (x *GetEnvResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_hostserve_v1_hostserve_proto_msgTypes[50]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -472,54 +2674,85 @@ func (x *WriteFileRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use WriteFileRequest.ProtoReflect.Descriptor instead.
-func (*WriteFileRequest) Descriptor() ([]byte, []int) {
-	return file_hostserve_v1_hostserve_proto_rawDescGZIP(), []int{8}
+// Deprecated: Use GetEnvResponse.ProtoReflect.Descriptor instead.
+func (*GetEnvResponse) Descriptor() ([]byte, []int) {
+	return file_hostserve_v1_hostserve_proto_rawDescGZIP(), []int{50}
 }
 
-func (x *WriteFileRequest) GetPath() string {
+func (x *GetEnvResponse) GetVal() string {
 	if x != nil {
-		return x.Path
+		return x.Val
 	}
 	return ""
 }
 
-func (x *WriteFileRequest) GetData() []byte {
+type LookupEnvRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Key           string                 `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LookupEnvRequest) Reset() {
+	*x = LookupEnvRequest{}
+	mi := &file_hostserve_v1_hostserve_proto_msgTypes[51]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LookupEnvRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LookupEnvRequest) ProtoMessage() {}
+
+func (x *LookupEnvRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_hostserve_v1_hostserve_proto_msgTypes[51]
 	if x != nil {
-		return x.Data
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return nil
+	return mi.MessageOf(x)
 }
 
-func (x *WriteFileRequest) GetPerm() uint32 {
+// Deprecated: Use LookupEnvRequest.ProtoReflect.Descriptor instead.
+func (*LookupEnvRequest) Descriptor() ([]byte, []int) {
+	return file_hostserve_v1_hostserve_proto_rawDescGZIP(), []int{51}
+}
+
+func (x *LookupEnvRequest) GetKey() string {
 	if x != nil {
-		return x.Perm
+		return x.Key
 	}
-	return 0
+	return ""
 }
 
-type WriteFileResponse struct {
+type LookupEnvResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Error         *string                `protobuf:"bytes,1,opt,name=error,proto3,oneof" json:"error,omitempty"`
+	Val           string                 `protobuf:"bytes,1,opt,name=val,proto3" json:"val,omitempty"`
+	Found         bool                   `protobuf:"varint,2,opt,name=found,proto3" json:"found,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *WriteFileResponse) Reset() {
-	*x = WriteFileResponse{}
-	mi := &file_hostserve_v1_hostserve_proto_msgTypes[9]
+func (x *LookupEnvResponse) Reset() {
+	*x = LookupEnvResponse{}
+	mi := &file_hostserve_v1_hostserve_proto_msgTypes[52]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *WriteFileResponse) String() string {
+func (x *LookupEnvResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*WriteFileResponse) ProtoMessage() {}
+func (*LookupEnvResponse) ProtoMessage() {}
 
-func (x *WriteFileResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_hostserve_v1_hostserve_proto_msgTypes[9]
+func (x *LookupEnvResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_hostserve_v1_hostserve_proto_msgTypes[52]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -530,40 +2763,46 @@ func (x *WriteFileResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use WriteFileResponse.ProtoReflect.Descriptor instead.
-func (*WriteFileResponse) Descriptor() ([]byte, []int) {
-	return file_hostserve_v1_hostserve_proto_rawDescGZIP(), []int{9}
+// Deprecated: Use LookupEnvResponse.ProtoReflect.Descriptor instead.
+func (*LookupEnvResponse) Descriptor() ([]byte, []int) {
+	return file_hostserve_v1_hostserve_proto_rawDescGZIP(), []int{52}
 }
 
-func (x *WriteFileResponse) GetError() string {
-	if x != nil && x.Error != nil {
-		return *x.Error
+func (x *LookupEnvResponse) GetVal() string {
+	if x != nil {
+		return x.Val
 	}
 	return ""
 }
 
-type GetEnvRequest struct {
+func (x *LookupEnvResponse) GetFound() bool {
+	if x != nil {
+		return x.Found
+	}
+	return false
+}
+
+type EnvironRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Key           string                 `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetEnvRequest) Reset() {
-	*x = GetEnvRequest{}
-	mi := &file_hostserve_v1_hostserve_proto_msgTypes[10]
+func (x *EnvironRequest) Reset() {
+	*x = EnvironRequest{}
+	mi := &file_hostserve_v1_hostserve_proto_msgTypes[53]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetEnvRequest) String() string {
+func (x *EnvironRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetEnvRequest) ProtoMessage() {}
+func (*EnvironRequest) ProtoMessage() {}
 
-func (x *GetEnvRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_hostserve_v1_hostserve_proto_msgTypes[10]
+func (x *EnvironRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_hostserve_v1_hostserve_proto_msgTypes[53]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -574,40 +2813,34 @@ func (x *GetEnvRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetEnvRequest.ProtoReflect.Descriptor instead.
-func (*GetEnvRequest) Descriptor() ([]byte, []int) {
-	return file_hostserve_v1_hostserve_proto_rawDescGZIP(), []int{10}
-}
-
-func (x *GetEnvRequest) GetKey() string {
-	if x != nil {
-		return x.Key
-	}
-	return ""
+// Deprecated: Use EnvironRequest.ProtoReflect.Descriptor instead.
+func (*EnvironRequest) Descriptor() ([]byte, []int) {
+	return file_hostserve_v1_hostserve_proto_rawDescGZIP(), []int{53}
 }
 
-type GetEnvResponse struct {
+// EnvironResponse lists the variables visible to the plugin in "KEY=value" form.
+type EnvironResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Val           string                 `protobuf:"bytes,1,opt,name=val,proto3" json:"val,omitempty"`
+	Vars          []string               `protobuf:"bytes,1,rep,name=vars,proto3" json:"vars,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetEnvResponse) Reset() {
-	*x = GetEnvResponse{}
-	mi := &file_hostserve_v1_hostserve_proto_msgTypes[11]
+func (x *EnvironResponse) Reset() {
+	*x = EnvironResponse{}
+	mi := &file_hostserve_v1_hostserve_proto_msgTypes[54]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetEnvResponse) String() string {
+func (x *EnvironResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetEnvResponse) ProtoMessage() {}
+func (*EnvironResponse) ProtoMessage() {}
 
-func (x *GetEnvResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_hostserve_v1_hostserve_proto_msgTypes[11]
+func (x *EnvironResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_hostserve_v1_hostserve_proto_msgTypes[54]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -618,68 +2851,220 @@ func (x *GetEnvResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetEnvResponse.ProtoReflect.Descriptor instead.
-func (*GetEnvResponse) Descriptor() ([]byte, []int) {
-	return file_hostserve_v1_hostserve_proto_rawDescGZIP(), []int{11}
+// Deprecated: Use EnvironResponse.ProtoReflect.Descriptor instead.
+func (*EnvironResponse) Descriptor() ([]byte, []int) {
+	return file_hostserve_v1_hostserve_proto_rawDescGZIP(), []int{54}
 }
 
-func (x *GetEnvResponse) GetVal() string {
+func (x *EnvironResponse) GetVars() []string {
 	if x != nil {
-		return x.Val
+		return x.Vars
 	}
-	return ""
+	return nil
 }
 
 var File_hostserve_v1_hostserve_proto protoreflect.FileDescriptor
 
 const file_hostserve_v1_hostserve_proto_rawDesc = "" +
 	"\n" +
-	"\x1chostserve/v1/hostserve.proto\x12\fhostserve.v1\"5\n" +
+	"\x1chostserve/v1/hostserve.proto\x12\fhostserve.v1\x1a\x1egoogle/protobuf/duration.proto\x1a\x1fgoogle/protobuf/timestamp.proto\"x\n" +
+	"\vErrorDetail\x12+\n" +
+	"\x04kind\x18\x01 \x01(\x0e2\x17.hostserve.v1.ErrorKindR\x04kind\x12\x0e\n" +
+	"\x02op\x18\x02 \x01(\tR\x02op\x12\x12\n" +
+	"\x04path\x18\x03 \x01(\tR\x04path\x12\x18\n" +
+	"\amessage\x18\x04 \x01(\tR\amessage\"\xb3\x01\n" +
 	"\bDirEntry\x12\x12\n" +
 	"\x04name\x18\x01 \x01(\tR\x04name\x12\x15\n" +
-	"\x06is_dir\x18\x02 \x01(\bR\x05isDir\"R\n" +
+	"\x06is_dir\x18\x02 \x01(\bR\x05isDir\x12\x12\n" +
+	"\x04size\x18\x03 \x01(\x03R\x04size\x12\x12\n" +
+	"\x04mode\x18\x04 \x01(\rR\x04mode\x125\n" +
+	"\bmod_time\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\amodTime\x12\x1d\n" +
+	"\n" +
+	"is_symlink\x18\x06 \x01(\bR\tisSymlink\"\x94\x01\n" +
+	"\bFileInfo\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x12\n" +
+	"\x04size\x18\x02 \x01(\x03R\x04size\x12\x12\n" +
+	"\x04mode\x18\x03 \x01(\rR\x04mode\x125\n" +
+	"\bmod_time\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\amodTime\x12\x15\n" +
+	"\x06is_dir\x18\x05 \x01(\bR\x05isDir\"R\n" +
 	"\tFileChunk\x12\x12\n" +
 	"\x04data\x18\x01 \x01(\fR\x04data\x12\x16\n" +
 	"\x06offset\x18\x02 \x01(\x04R\x06offset\x12\x19\n" +
-	"\bis_final\x18\x03 \x01(\bR\aisFinal\"c\n" +
+	"\bis_final\x18\x03 \x01(\bR\aisFinal\"D\n" +
 	"\rReadFileChunk\x12-\n" +
-	"\x05chunk\x18\x01 \x01(\v2\x17.hostserve.v1.FileChunkR\x05chunk\x12\x19\n" +
-	"\x05error\x18\x02 \x01(\tH\x00R\x05error\x88\x01\x01B\b\n" +
-	"\x06_error\"g\n" +
+	"\x05chunk\x18\x01 \x01(\v2\x17.hostserve.v1.FileChunkR\x05chunkJ\x04\b\x02\x10\x03\"F\n" +
+	"\fReadDirChunk\x120\n" +
+	"\aentries\x18\x01 \x03(\v2\x16.hostserve.v1.DirEntryR\aentriesJ\x04\b\x02\x10\x03\"|\n" +
 	"\x0eWriteFileChunk\x12\x12\n" +
 	"\x04path\x18\x01 \x01(\tR\x04path\x12\x12\n" +
 	"\x04perm\x18\x02 \x01(\rR\x04perm\x12-\n" +
-	"\x05chunk\x18\x03 \x01(\v2\x17.hostserve.v1.FileChunkR\x05chunk\"$\n" +
+	"\x05chunk\x18\x03 \x01(\v2\x17.hostserve.v1.FileChunkR\x05chunk\x12\x13\n" +
+	"\x05tx_id\x18\x04 \x01(\tR\x04txId\"@\n" +
+	"\fWatchRequest\x12\x12\n" +
+	"\x04path\x18\x01 \x01(\tR\x04path\x12\x1c\n" +
+	"\trecursive\x18\x02 \x01(\bR\trecursive\"6\n" +
+	"\n" +
+	"WatchEvent\x12\x12\n" +
+	"\x04path\x18\x01 \x01(\tR\x04path\x12\x0e\n" +
+	"\x02op\x18\x02 \x01(\rR\x02opJ\x04\b\x03\x10\x04\"{\n" +
+	"\vWalkRequest\x12\x12\n" +
+	"\x04path\x18\x01 \x01(\tR\x04path\x12\x1b\n" +
+	"\tmax_depth\x18\x02 \x01(\x05R\bmaxDepth\x12'\n" +
+	"\x0ffollow_symlinks\x18\x03 \x01(\bR\x0efollowSymlinks\x12\x12\n" +
+	"\x04skip\x18\x04 \x03(\tR\x04skip\"\xa5\x01\n" +
+	"\tWalkEntry\x12\x12\n" +
+	"\x04path\x18\x01 \x01(\tR\x04path\x12\x14\n" +
+	"\x05depth\x18\x02 \x01(\x05R\x05depth\x12*\n" +
+	"\x04info\x18\x03 \x01(\v2\x16.hostserve.v1.FileInfoR\x04info\x12<\n" +
+	"\ferror_detail\x18\x05 \x01(\v2\x19.hostserve.v1.ErrorDetailR\verrorDetailJ\x04\b\x04\x10\x05\"D\n" +
+	"\tWalkChunk\x121\n" +
+	"\aentries\x18\x01 \x03(\v2\x17.hostserve.v1.WalkEntryR\aentriesJ\x04\b\x02\x10\x03\"$\n" +
 	"\x0eReadDirRequest\x12\x12\n" +
-	"\x04path\x18\x01 \x01(\tR\x04path\"h\n" +
+	"\x04path\x18\x01 \x01(\tR\x04path\"I\n" +
 	"\x0fReadDirResponse\x120\n" +
-	"\aentries\x18\x01 \x03(\v2\x16.hostserve.v1.DirEntryR\aentries\x12\x19\n" +
-	"\x05error\x18\x02 \x01(\tH\x00R\x05error\x88\x01\x01B\b\n" +
-	"\x06_error\"%\n" +
+	"\aentries\x18\x01 \x03(\v2\x16.hostserve.v1.DirEntryR\aentriesJ\x04\b\x02\x10\x03\"%\n" +
 	"\x0fReadFileRequest\x12\x12\n" +
-	"\x04path\x18\x01 \x01(\tR\x04path\"S\n" +
+	"\x04path\x18\x01 \x01(\tR\x04path\"4\n" +
 	"\x10ReadFileResponse\x12\x1a\n" +
-	"\bcontents\x18\x01 \x01(\fR\bcontents\x12\x19\n" +
-	"\x05error\x18\x02 \x01(\tH\x00R\x05error\x88\x01\x01B\b\n" +
-	"\x06_error\"N\n" +
+	"\bcontents\x18\x01 \x01(\fR\bcontentsJ\x04\b\x02\x10\x03\"\xa8\x01\n" +
 	"\x10WriteFileRequest\x12\x12\n" +
 	"\x04path\x18\x01 \x01(\tR\x04path\x12\x12\n" +
 	"\x04data\x18\x02 \x01(\fR\x04data\x12\x12\n" +
-	"\x04perm\x18\x03 \x01(\rR\x04perm\"8\n" +
-	"\x11WriteFileResponse\x12\x19\n" +
-	"\x05error\x18\x01 \x01(\tH\x00R\x05error\x88\x01\x01B\b\n" +
-	"\x06_error\"!\n" +
+	"\x04perm\x18\x03 \x01(\rR\x04perm\x12\x13\n" +
+	"\x05tx_id\x18\x04 \x01(\tR\x04txId\x12C\n" +
+	"\fprecondition\x18\x05 \x01(\v2\x1f.hostserve.v1.WritePreconditionR\fprecondition\"\x88\x01\n" +
+	"\x11WritePrecondition\x125\n" +
+	"\bmod_time\x18\x01 \x01(\v2\x1a.google.protobuf.TimestampR\amodTime\x12\x16\n" +
+	"\x06sha256\x18\x02 \x01(\fR\x06sha256\x12$\n" +
+	"\x0emust_not_exist\x18\x03 \x01(\bR\fmustNotExist\"\x19\n" +
+	"\x11WriteFileResponseJ\x04\b\x01\x10\x02\"!\n" +
+	"\vStatRequest\x12\x12\n" +
+	"\x04path\x18\x01 \x01(\tR\x04path\"@\n" +
+	"\fStatResponse\x12*\n" +
+	"\x04info\x18\x01 \x01(\v2\x16.hostserve.v1.FileInfoR\x04infoJ\x04\b\x02\x10\x03\"'\n" +
+	"\vGlobRequest\x12\x18\n" +
+	"\apattern\x18\x01 \x01(\tR\apattern\".\n" +
+	"\fGlobResponse\x12\x18\n" +
+	"\amatches\x18\x01 \x03(\tR\amatchesJ\x04\b\x02\x10\x03\"9\n" +
+	"\x0fMkdirAllRequest\x12\x12\n" +
+	"\x04path\x18\x01 \x01(\tR\x04path\x12\x12\n" +
+	"\x04perm\x18\x02 \x01(\rR\x04perm\"\x18\n" +
+	"\x10MkdirAllResponseJ\x04\b\x01\x10\x02\"#\n" +
+	"\rRemoveRequest\x12\x12\n" +
+	"\x04path\x18\x01 \x01(\tR\x04path\"\x16\n" +
+	"\x0eRemoveResponseJ\x04\b\x01\x10\x02\"&\n" +
+	"\x10RemoveAllRequest\x12\x12\n" +
+	"\x04path\x18\x01 \x01(\tR\x04path\"\x19\n" +
+	"\x11RemoveAllResponseJ\x04\b\x01\x10\x02\"E\n" +
+	"\rRenameRequest\x12\x19\n" +
+	"\bold_path\x18\x01 \x01(\tR\aoldPath\x12\x19\n" +
+	"\bnew_path\x18\x02 \x01(\tR\anewPath\"\x16\n" +
+	"\x0eRenameResponseJ\x04\b\x01\x10\x02\"F\n" +
+	"\vCopyRequest\x12\x10\n" +
+	"\x03src\x18\x01 \x01(\tR\x03src\x12\x10\n" +
+	"\x03dst\x18\x02 \x01(\tR\x03dst\x12\x13\n" +
+	"\x05tx_id\x18\x03 \x01(\tR\x04txId\"7\n" +
+	"\fCopyResponse\x12!\n" +
+	"\fbytes_copied\x18\x01 \x01(\x03R\vbytesCopiedJ\x04\b\x02\x10\x03\"6\n" +
+	"\fChmodRequest\x12\x12\n" +
+	"\x04path\x18\x01 \x01(\tR\x04path\x12\x12\n" +
+	"\x04mode\x18\x02 \x01(\rR\x04mode\"\x15\n" +
+	"\rChmodResponseJ\x04\b\x01\x10\x02\"9\n" +
+	"\x0fTruncateRequest\x12\x12\n" +
+	"\x04path\x18\x01 \x01(\tR\x04path\x12\x12\n" +
+	"\x04size\x18\x02 \x01(\x03R\x04size\"\x18\n" +
+	"\x10TruncateResponseJ\x04\b\x01\x10\x02\"K\n" +
+	"\rAppendRequest\x12\x12\n" +
+	"\x04path\x18\x01 \x01(\tR\x04path\x12\x12\n" +
+	"\x04data\x18\x02 \x01(\fR\x04data\x12\x12\n" +
+	"\x04perm\x18\x03 \x01(\rR\x04perm\"\x16\n" +
+	"\x0eAppendResponseJ\x04\b\x01\x10\x02\"\x10\n" +
+	"\x0eBeginTxRequest\"&\n" +
+	"\x0fBeginTxResponse\x12\x13\n" +
+	"\x05tx_id\x18\x01 \x01(\tR\x04txId\"&\n" +
+	"\x0fCommitTxRequest\x12\x13\n" +
+	"\x05tx_id\x18\x01 \x01(\tR\x04txId\"\x12\n" +
+	"\x10CommitTxResponse\"(\n" +
+	"\x11RollbackTxRequest\x12\x13\n" +
+	"\x05tx_id\x18\x01 \x01(\tR\x04txId\"\x14\n" +
+	"\x12RollbackTxResponse\"~\n" +
+	"\vLockRequest\x12\x12\n" +
+	"\x04path\x18\x01 \x01(\tR\x04path\x12*\n" +
+	"\x04mode\x18\x02 \x01(\x0e2\x16.hostserve.v1.LockModeR\x04mode\x12/\n" +
+	"\x05lease\x18\x03 \x01(\v2\x19.google.protobuf.DurationR\x05lease\"]\n" +
+	"\fLockResponse\x12\x17\n" +
+	"\alock_id\x18\x01 \x01(\tR\x06lockId\x124\n" +
+	"\aexpires\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\aexpires\"(\n" +
+	"\rUnlockRequest\x12\x17\n" +
+	"\alock_id\x18\x01 \x01(\tR\x06lockId\"\x10\n" +
+	"\x0eUnlockResponse\"!\n" +
 	"\rGetEnvRequest\x12\x10\n" +
 	"\x03key\x18\x01 \x01(\tR\x03key\"\"\n" +
 	"\x0eGetEnvResponse\x12\x10\n" +
-	"\x03val\x18\x01 \x01(\tR\x03val2\xd7\x03\n" +
+	"\x03val\x18\x01 \x01(\tR\x03val\"$\n" +
+	"\x10LookupEnvRequest\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\";\n" +
+	"\x11LookupEnvResponse\x12\x10\n" +
+	"\x03val\x18\x01 \x01(\tR\x03val\x12\x14\n" +
+	"\x05found\x18\x02 \x01(\bR\x05found\"\x10\n" +
+	"\x0eEnvironRequest\"%\n" +
+	"\x0fEnvironResponse\x12\x12\n" +
+	"\x04vars\x18\x01 \x03(\tR\x04vars*\x9e\x04\n" +
+	"\tErrorKind\x12\x1a\n" +
+	"\x16ERROR_KIND_UNSPECIFIED\x10\x00\x12\x18\n" +
+	"\x14ERROR_KIND_NOT_EXIST\x10\x01\x12\x14\n" +
+	"\x10ERROR_KIND_EXIST\x10\x02\x12\x19\n" +
+	"\x15ERROR_KIND_PERMISSION\x10\x03\x12\x1b\n" +
+	"\x17ERROR_KIND_INVALID_PATH\x10\x04\x12\x1c\n" +
+	"\x18ERROR_KIND_ACCESS_DENIED\x10\x05\x12\x16\n" +
+	"\x12ERROR_KIND_INVALID\x10\x06\x12\x15\n" +
+	"\x11ERROR_KIND_CLOSED\x10\a\x12\x1a\n" +
+	"\x16ERROR_KIND_UNSUPPORTED\x10\b\x12!\n" +
+	"\x1dERROR_KIND_CHUNK_OUT_OF_ORDER\x10\t\x12 \n" +
+	"\x1cERROR_KIND_INCOMPLETE_STREAM\x10\n" +
+	"\x12\x19\n" +
+	"\x15ERROR_KIND_WALK_CYCLE\x10\v\x12\x17\n" +
+	"\x13ERROR_KIND_CANCELED\x10\f\x12 \n" +
+	"\x1cERROR_KIND_DEADLINE_EXCEEDED\x10\r\x12\x1b\n" +
+	"\x17ERROR_KIND_TX_NOT_FOUND\x10\x0e\x12\x17\n" +
+	"\x13ERROR_KIND_CONFLICT\x10\x0f\x12\x15\n" +
+	"\x11ERROR_KIND_LOCKED\x10\x10\x12\x1d\n" +
+	"\x19ERROR_KIND_LOCK_NOT_FOUND\x10\x11\x12\x1d\n" +
+	"\x19ERROR_KIND_QUOTA_EXCEEDED\x10\x12*T\n" +
+	"\bLockMode\x12\x19\n" +
+	"\x15LOCK_MODE_UNSPECIFIED\x10\x00\x12\x14\n" +
+	"\x10LOCK_MODE_SHARED\x10\x01\x12\x17\n" +
+	"\x13LOCK_MODE_EXCLUSIVE\x10\x022\xd5\x0f\n" +
 	"\vHostService\x12F\n" +
 	"\aReadDir\x12\x1c.hostserve.v1.ReadDirRequest\x1a\x1d.hostserve.v1.ReadDirResponse\x12I\n" +
 	"\bReadFile\x12\x1d.hostserve.v1.ReadFileRequest\x1a\x1e.hostserve.v1.ReadFileResponse\x12L\n" +
-	"\tWriteFile\x12\x1e.hostserve.v1.WriteFileRequest\x1a\x1f.hostserve.v1.WriteFileResponse\x12N\n" +
-	"\x0eReadFileStream\x12\x1d.hostserve.v1.ReadFileRequest\x1a\x1b.hostserve.v1.ReadFileChunk0\x01\x12R\n" +
-	"\x0fWriteFileStream\x12\x1c.hostserve.v1.WriteFileChunk\x1a\x1f.hostserve.v1.WriteFileResponse(\x01\x12C\n" +
-	"\x06GetEnv\x12\x1b.hostserve.v1.GetEnvRequest\x1a\x1c.hostserve.v1.GetEnvResponseB\xc0\x01\n" +
+	"\tWriteFile\x12\x1e.hostserve.v1.WriteFileRequest\x1a\x1f.hostserve.v1.WriteFileResponse\x12=\n" +
+	"\x04Stat\x12\x19.hostserve.v1.StatRequest\x1a\x1a.hostserve.v1.StatResponse\x12>\n" +
+	"\x05Lstat\x12\x19.hostserve.v1.StatRequest\x1a\x1a.hostserve.v1.StatResponse\x12I\n" +
+	"\bMkdirAll\x12\x1d.hostserve.v1.MkdirAllRequest\x1a\x1e.hostserve.v1.MkdirAllResponse\x12C\n" +
+	"\x06Remove\x12\x1b.hostserve.v1.RemoveRequest\x1a\x1c.hostserve.v1.RemoveResponse\x12L\n" +
+	"\tRemoveAll\x12\x1e.hostserve.v1.RemoveAllRequest\x1a\x1f.hostserve.v1.RemoveAllResponse\x12C\n" +
+	"\x06Rename\x12\x1b.hostserve.v1.RenameRequest\x1a\x1c.hostserve.v1.RenameResponse\x12=\n" +
+	"\x04Copy\x12\x19.hostserve.v1.CopyRequest\x1a\x1a.hostserve.v1.CopyResponse\x12@\n" +
+	"\x05Chmod\x12\x1a.hostserve.v1.ChmodRequest\x1a\x1b.hostserve.v1.ChmodResponse\x12I\n" +
+	"\bTruncate\x12\x1d.hostserve.v1.TruncateRequest\x1a\x1e.hostserve.v1.TruncateResponse\x12C\n" +
+	"\x06Append\x12\x1b.hostserve.v1.AppendRequest\x1a\x1c.hostserve.v1.AppendResponse\x12N\n" +
+	"\x0eReadFileStream\x12\x1d.hostserve.v1.ReadFileRequest\x1a\x1b.hostserve.v1.ReadFileChunk0\x01\x12K\n" +
+	"\rReadDirStream\x12\x1c.hostserve.v1.ReadDirRequest\x1a\x1a.hostserve.v1.ReadDirChunk0\x01\x12R\n" +
+	"\x0fWriteFileStream\x12\x1c.hostserve.v1.WriteFileChunk\x1a\x1f.hostserve.v1.WriteFileResponse(\x01\x12?\n" +
+	"\x05Watch\x12\x1a.hostserve.v1.WatchRequest\x1a\x18.hostserve.v1.WatchEvent0\x01\x12<\n" +
+	"\x04Walk\x12\x19.hostserve.v1.WalkRequest\x1a\x17.hostserve.v1.WalkChunk0\x01\x12=\n" +
+	"\x04Glob\x12\x19.hostserve.v1.GlobRequest\x1a\x1a.hostserve.v1.GlobResponse\x12F\n" +
+	"\aBeginTx\x12\x1c.hostserve.v1.BeginTxRequest\x1a\x1d.hostserve.v1.BeginTxResponse\x12I\n" +
+	"\bCommitTx\x12\x1d.hostserve.v1.CommitTxRequest\x1a\x1e.hostserve.v1.CommitTxResponse\x12O\n" +
+	"\n" +
+	"RollbackTx\x12\x1f.hostserve.v1.RollbackTxRequest\x1a .hostserve.v1.RollbackTxResponse\x12=\n" +
+	"\x04Lock\x12\x19.hostserve.v1.LockRequest\x1a\x1a.hostserve.v1.LockResponse\x12@\n" +
+	"\aTryLock\x12\x19.hostserve.v1.LockRequest\x1a\x1a.hostserve.v1.LockResponse\x12C\n" +
+	"\x06Unlock\x12\x1b.hostserve.v1.UnlockRequest\x1a\x1c.hostserve.v1.UnlockResponse\x12C\n" +
+	"\x06GetEnv\x12\x1b.hostserve.v1.GetEnvRequest\x1a\x1c.hostserve.v1.GetEnvResponse\x12L\n" +
+	"\tLookupEnv\x12\x1e.hostserve.v1.LookupEnvRequest\x1a\x1f.hostserve.v1.LookupEnvResponse\x12F\n" +
+	"\aEnviron\x12\x1c.hostserve.v1.EnvironRequest\x1a\x1d.hostserve.v1.EnvironResponseB\xc0\x01\n" +
 	"\x10com.hostserve.v1B\x0eHostserveProtoP\x01ZKgithub.com/bmj2728/HostServiceTest/shared/protogen/hostserve/v1;hostservev1\xa2\x02\x03HXX\xaa\x02\fHostserve.V1\xca\x02\fHostserve\\V1\xe2\x02\x18Hostserve\\V1\\GPBMetadata\xea\x02\rHostserve::V1b\x06proto3"
 
 var (
@@ -694,42 +3079,147 @@ func file_hostserve_v1_hostserve_proto_rawDescGZIP() []byte {
 	return file_hostserve_v1_hostserve_proto_rawDescData
 }
 
-var file_hostserve_v1_hostserve_proto_msgTypes = make([]protoimpl.MessageInfo, 12)
+var file_hostserve_v1_hostserve_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
+var file_hostserve_v1_hostserve_proto_msgTypes = make([]protoimpl.MessageInfo, 55)
 var file_hostserve_v1_hostserve_proto_goTypes = []any{
-	(*DirEntry)(nil),          // 0: hostserve.v1.DirEntry
-	(*FileChunk)(nil),         // 1: hostserve.v1.FileChunk
-	(*ReadFileChunk)(nil),     // 2: hostserve.v1.ReadFileChunk
-	(*WriteFileChunk)(nil),    // 3: hostserve.v1.WriteFileChunk
-	(*ReadDirRequest)(nil),    // 4: hostserve.v1.ReadDirRequest
-	(*ReadDirResponse)(nil),   // 5: hostserve.v1.ReadDirResponse
-	(*ReadFileRequest)(nil),   // 6: hostserve.v1.ReadFileRequest
-	(*ReadFileResponse)(nil),  // 7: hostserve.v1.ReadFileResponse
-	(*WriteFileRequest)(nil),  // 8: hostserve.v1.WriteFileRequest
-	(*WriteFileResponse)(nil), // 9: hostserve.v1.WriteFileResponse
-	(*GetEnvRequest)(nil),     // 10: hostserve.v1.GetEnvRequest
-	(*GetEnvResponse)(nil),    // 11: hostserve.v1.GetEnvResponse
+	(ErrorKind)(0),                // 0: hostserve.v1.ErrorKind
+	(LockMode)(0),                 // 1: hostserve.v1.LockMode
+	(*ErrorDetail)(nil),           // 2: hostserve.v1.ErrorDetail
+	(*DirEntry)(nil),              // 3: hostserve.v1.DirEntry
+	(*FileInfo)(nil),              // 4: hostserve.v1.FileInfo
+	(*FileChunk)(nil),             // 5: hostserve.v1.FileChunk
+	(*ReadFileChunk)(nil),         // 6: hostserve.v1.ReadFileChunk
+	(*ReadDirChunk)(nil),          // 7: hostserve.v1.ReadDirChunk
+	(*WriteFileChunk)(nil),        // 8: hostserve.v1.WriteFileChunk
+	(*WatchRequest)(nil),          // 9: hostserve.v1.WatchRequest
+	(*WatchEvent)(nil),            // 10: hostserve.v1.WatchEvent
+	(*WalkRequest)(nil),           // 11: hostserve.v1.WalkRequest
+	(*WalkEntry)(nil),             // 12: hostserve.v1.WalkEntry
+	(*WalkChunk)(nil),             // 13: hostserve.v1.WalkChunk
+	(*ReadDirRequest)(nil),        // 14: hostserve.v1.ReadDirRequest
+	(*ReadDirResponse)(nil),       // 15: hostserve.v1.ReadDirResponse
+	(*ReadFileRequest)(nil),       // 16: hostserve.v1.ReadFileRequest
+	(*ReadFileResponse)(nil),      // 17: hostserve.v1.ReadFileResponse
+	(*WriteFileRequest)(nil),      // 18: hostserve.v1.WriteFileRequest
+	(*WritePrecondition)(nil),     // 19: hostserve.v1.WritePrecondition
+	(*WriteFileResponse)(nil),     // 20: hostserve.v1.WriteFileResponse
+	(*StatRequest)(nil),           // 21: hostserve.v1.StatRequest
+	(*StatResponse)(nil),          // 22: hostserve.v1.StatResponse
+	(*GlobRequest)(nil),           // 23: hostserve.v1.GlobRequest
+	(*GlobResponse)(nil),          // 24: hostserve.v1.GlobResponse
+	(*MkdirAllRequest)(nil),       // 25: hostserve.v1.MkdirAllRequest
+	(*MkdirAllResponse)(nil),      // 26: hostserve.v1.MkdirAllResponse
+	(*RemoveRequest)(nil),         // 27: hostserve.v1.RemoveRequest
+	(*RemoveResponse)(nil),        // 28: hostserve.v1.RemoveResponse
+	(*RemoveAllRequest)(nil),      // 29: hostserve.v1.RemoveAllRequest
+	(*RemoveAllResponse)(nil),     // 30: hostserve.v1.RemoveAllResponse
+	(*RenameRequest)(nil),         // 31: hostserve.v1.RenameRequest
+	(*RenameResponse)(nil),        // 32: hostserve.v1.RenameResponse
+	(*CopyRequest)(nil),           // 33: hostserve.v1.CopyRequest
+	(*CopyResponse)(nil),          // 34: hostserve.v1.CopyResponse
+	(*ChmodRequest)(nil),          // 35: hostserve.v1.ChmodRequest
+	(*ChmodResponse)(nil),         // 36: hostserve.v1.ChmodResponse
+	(*TruncateRequest)(nil),       // 37: hostserve.v1.TruncateRequest
+	(*TruncateResponse)(nil),      // 38: hostserve.v1.TruncateResponse
+	(*AppendRequest)(nil),         // 39: hostserve.v1.AppendRequest
+	(*AppendResponse)(nil),        // 40: hostserve.v1.AppendResponse
+	(*BeginTxRequest)(nil),        // 41: hostserve.v1.BeginTxRequest
+	(*BeginTxResponse)(nil),       // 42: hostserve.v1.BeginTxResponse
+	(*CommitTxRequest)(nil),       // 43: hostserve.v1.CommitTxRequest
+	(*CommitTxResponse)(nil),      // 44: hostserve.v1.CommitTxResponse
+	(*RollbackTxRequest)(nil),     // 45: hostserve.v1.RollbackTxRequest
+	(*RollbackTxResponse)(nil),    // 46: hostserve.v1.RollbackTxResponse
+	(*LockRequest)(nil),           // 47: hostserve.v1.LockRequest
+	(*LockResponse)(nil),          // 48: hostserve.v1.LockResponse
+	(*UnlockRequest)(nil),         // 49: hostserve.v1.UnlockRequest
+	(*UnlockResponse)(nil),        // 50: hostserve.v1.UnlockResponse
+	(*GetEnvRequest)(nil),         // 51: hostserve.v1.GetEnvRequest
+	(*GetEnvResponse)(nil),        // 52: hostserve.v1.GetEnvResponse
+	(*LookupEnvRequest)(nil),      // 53: hostserve.v1.LookupEnvRequest
+	(*LookupEnvResponse)(nil),     // 54: hostserve.v1.LookupEnvResponse
+	(*EnvironRequest)(nil),        // 55: hostserve.v1.EnvironRequest
+	(*EnvironResponse)(nil),       // 56: hostserve.v1.EnvironResponse
+	(*timestamppb.Timestamp)(nil), // 57: google.protobuf.Timestamp
+	(*durationpb.Duration)(nil),   // 58: google.protobuf.Duration
 }
 var file_hostserve_v1_hostserve_proto_depIdxs = []int32{
-	1,  // 0: hostserve.v1.ReadFileChunk.chunk:type_name -> hostserve.v1.FileChunk
-	1,  // 1: hostserve.v1.WriteFileChunk.chunk:type_name -> hostserve.v1.FileChunk
-	0,  // 2: hostserve.v1.ReadDirResponse.entries:type_name -> hostserve.v1.DirEntry
-	4,  // 3: hostserve.v1.HostService.ReadDir:input_type -> hostserve.v1.ReadDirRequest
-	6,  // 4: hostserve.v1.HostService.ReadFile:input_type -> hostserve.v1.ReadFileRequest
-	8,  // 5: hostserve.v1.HostService.WriteFile:input_type -> hostserve.v1.WriteFileRequest
-	6,  // 6: hostserve.v1.HostService.ReadFileStream:input_type -> hostserve.v1.ReadFileRequest
-	3,  // 7: hostserve.v1.HostService.WriteFileStream:input_type -> hostserve.v1.WriteFileChunk
-	10, // 8: hostserve.v1.HostService.GetEnv:input_type -> hostserve.v1.GetEnvRequest
-	5,  // 9: hostserve.v1.HostService.ReadDir:output_type -> hostserve.v1.ReadDirResponse
-	7,  // 10: hostserve.v1.HostService.ReadFile:output_type -> hostserve.v1.ReadFileResponse
-	9,  // 11: hostserve.v1.HostService.WriteFile:output_type -> hostserve.v1.WriteFileResponse
-	2,  // 12: hostserve.v1.HostService.ReadFileStream:output_type -> hostserve.v1.ReadFileChunk
-	9,  // 13: hostserve.v1.HostService.WriteFileStream:output_type -> hostserve.v1.WriteFileResponse
-	11, // 14: hostserve.v1.HostService.GetEnv:output_type -> hostserve.v1.GetEnvResponse
-	9,  // [9:15] is the sub-list for method output_type
-	3,  // [3:9] is the sub-list for method input_type
-	3,  // [3:3] is the sub-list for extension type_name
-	3,  // [3:3] is the sub-list for extension extendee
-	0,  // [0:3] is the sub-list for field type_name
+	0,  // 0: hostserve.v1.ErrorDetail.kind:type_name -> hostserve.v1.ErrorKind
+	57, // 1: hostserve.v1.DirEntry.mod_time:type_name -> google.protobuf.Timestamp
+	57, // 2: hostserve.v1.FileInfo.mod_time:type_name -> google.protobuf.Timestamp
+	5,  // 3: hostserve.v1.ReadFileChunk.chunk:type_name -> hostserve.v1.FileChunk
+	3,  // 4: hostserve.v1.ReadDirChunk.entries:type_name -> hostserve.v1.DirEntry
+	5,  // 5: hostserve.v1.WriteFileChunk.chunk:type_name -> hostserve.v1.FileChunk
+	4,  // 6: hostserve.v1.WalkEntry.info:type_name -> hostserve.v1.FileInfo
+	2,  // 7: hostserve.v1.WalkEntry.error_detail:type_name -> hostserve.v1.ErrorDetail
+	12, // 8: hostserve.v1.WalkChunk.entries:type_name -> hostserve.v1.WalkEntry
+	3,  // 9: hostserve.v1.ReadDirResponse.entries:type_name -> hostserve.v1.DirEntry
+	19, // 10: hostserve.v1.WriteFileRequest.precondition:type_name -> hostserve.v1.WritePrecondition
+	57, // 11: hostserve.v1.WritePrecondition.mod_time:type_name -> google.protobuf.Timestamp
+	4,  // 12: hostserve.v1.StatResponse.info:type_name -> hostserve.v1.FileInfo
+	1,  // 13: hostserve.v1.LockRequest.mode:type_name -> hostserve.v1.LockMode
+	58, // 14: hostserve.v1.LockRequest.lease:type_name -> google.protobuf.Duration
+	57, // 15: hostserve.v1.LockResponse.expires:type_name -> google.protobuf.Timestamp
+	14, // 16: hostserve.v1.HostService.ReadDir:input_type -> hostserve.v1.ReadDirRequest
+	16, // 17: hostserve.v1.HostService.ReadFile:input_type -> hostserve.v1.ReadFileRequest
+	18, // 18: hostserve.v1.HostService.WriteFile:input_type -> hostserve.v1.WriteFileRequest
+	21, // 19: hostserve.v1.HostService.Stat:input_type -> hostserve.v1.StatRequest
+	21, // 20: hostserve.v1.HostService.Lstat:input_type -> hostserve.v1.StatRequest
+	25, // 21: hostserve.v1.HostService.MkdirAll:input_type -> hostserve.v1.MkdirAllRequest
+	27, // 22: hostserve.v1.HostService.Remove:input_type -> hostserve.v1.RemoveRequest
+	29, // 23: hostserve.v1.HostService.RemoveAll:input_type -> hostserve.v1.RemoveAllRequest
+	31, // 24: hostserve.v1.HostService.Rename:input_type -> hostserve.v1.RenameRequest
+	33, // 25: hostserve.v1.HostService.Copy:input_type -> hostserve.v1.CopyRequest
+	35, // 26: hostserve.v1.HostService.Chmod:input_type -> hostserve.v1.ChmodRequest
+	37, // 27: hostserve.v1.HostService.Truncate:input_type -> hostserve.v1.TruncateRequest
+	39, // 28: hostserve.v1.HostService.Append:input_type -> hostserve.v1.AppendRequest
+	16, // 29: hostserve.v1.HostService.ReadFileStream:input_type -> hostserve.v1.ReadFileRequest
+	14, // 30: hostserve.v1.HostService.ReadDirStream:input_type -> hostserve.v1.ReadDirRequest
+	8,  // 31: hostserve.v1.HostService.WriteFileStream:input_type -> hostserve.v1.WriteFileChunk
+	9,  // 32: hostserve.v1.HostService.Watch:input_type -> hostserve.v1.WatchRequest
+	11, // 33: hostserve.v1.HostService.Walk:input_type -> hostserve.v1.WalkRequest
+	23, // 34: hostserve.v1.HostService.Glob:input_type -> hostserve.v1.GlobRequest
+	41, // 35: hostserve.v1.HostService.BeginTx:input_type -> hostserve.v1.BeginTxRequest
+	43, // 36: hostserve.v1.HostService.CommitTx:input_type -> hostserve.v1.CommitTxRequest
+	45, // 37: hostserve.v1.HostService.RollbackTx:input_type -> hostserve.v1.RollbackTxRequest
+	47, // 38: hostserve.v1.HostService.Lock:input_type -> hostserve.v1.LockRequest
+	47, // 39: hostserve.v1.HostService.TryLock:input_type -> hostserve.v1.LockRequest
+	49, // 40: hostserve.v1.HostService.Unlock:input_type -> hostserve.v1.UnlockRequest
+	51, // 41: hostserve.v1.HostService.GetEnv:input_type -> hostserve.v1.GetEnvRequest
+	53, // 42: hostserve.v1.HostService.LookupEnv:input_type -> hostserve.v1.LookupEnvRequest
+	55, // 43: hostserve.v1.HostService.Environ:input_type -> hostserve.v1.EnvironRequest
+	15, // 44: hostserve.v1.HostService.ReadDir:output_type -> hostserve.v1.ReadDirResponse
+	17, // 45: hostserve.v1.HostService.ReadFile:output_type -> hostserve.v1.ReadFileResponse
+	20, // 46: hostserve.v1.HostService.WriteFile:output_type -> hostserve.v1.WriteFileResponse
+	22, // 47: hostserve.v1.HostService.Stat:output_type -> hostserve.v1.StatResponse
+	22, // 48: hostserve.v1.HostService.Lstat:output_type -> hostserve.v1.StatResponse
+	26, // 49: hostserve.v1.HostService.MkdirAll:output_type -> hostserve.v1.MkdirAllResponse
+	28, // 50: hostserve.v1.HostService.Remove:output_type -> hostserve.v1.RemoveResponse
+	30, // 51: hostserve.v1.HostService.RemoveAll:output_type -> hostserve.v1.RemoveAllResponse
+	32, // 52: hostserve.v1.HostService.Rename:output_type -> hostserve.v1.RenameResponse
+	34, // 53: hostserve.v1.HostService.Copy:output_type -> hostserve.v1.CopyResponse
+	36, // 54: hostserve.v1.HostService.Chmod:output_type -> hostserve.v1.ChmodResponse
+	38, // 55: hostserve.v1.HostService.Truncate:output_type -> hostserve.v1.TruncateResponse
+	40, // 56: hostserve.v1.HostService.Append:output_type -> hostserve.v1.AppendResponse
+	6,  // 57: hostserve.v1.HostService.ReadFileStream:output_type -> hostserve.v1.ReadFileChunk
+	7,  // 58: hostserve.v1.HostService.ReadDirStream:output_type -> hostserve.v1.ReadDirChunk
+	20, // 59: hostserve.v1.HostService.WriteFileStream:output_type -> hostserve.v1.WriteFileResponse
+	10, // 60: hostserve.v1.HostService.Watch:output_type -> hostserve.v1.WatchEvent
+	13, // 61: hostserve.v1.HostService.Walk:output_type -> hostserve.v1.WalkChunk
+	24, // 62: hostserve.v1.HostService.Glob:output_type -> hostserve.v1.GlobResponse
+	42, // 63: hostserve.v1.HostService.BeginTx:output_type -> hostserve.v1.BeginTxResponse
+	44, // 64: hostserve.v1.HostService.CommitTx:output_type -> hostserve.v1.CommitTxResponse
+	46, // 65: hostserve.v1.HostService.RollbackTx:output_type -> hostserve.v1.RollbackTxResponse
+	48, // 66: hostserve.v1.HostService.Lock:output_type -> hostserve.v1.LockResponse
+	48, // 67: hostserve.v1.HostService.TryLock:output_type -> hostserve.v1.LockResponse
+	50, // 68: hostserve.v1.HostService.Unlock:output_type -> hostserve.v1.UnlockResponse
+	52, // 69: hostserve.v1.HostService.GetEnv:output_type -> hostserve.v1.GetEnvResponse
+	54, // 70: hostserve.v1.HostService.LookupEnv:output_type -> hostserve.v1.LookupEnvResponse
+	56, // 71: hostserve.v1.HostService.Environ:output_type -> hostserve.v1.EnvironResponse
+	44, // [44:72] is the sub-list for method output_type
+	16, // [16:44] is the sub-list for method input_type
+	16, // [16:16] is the sub-list for extension type_name
+	16, // [16:16] is the sub-list for extension extendee
+	0,  // [0:16] is the sub-list for field type_name
 }
 
 func init() { file_hostserve_v1_hostserve_proto_init() }
@@ -737,22 +3227,19 @@ func file_hostserve_v1_hostserve_proto_init() {
 	if File_hostserve_v1_hostserve_proto != nil {
 		return
 	}
-	file_hostserve_v1_hostserve_proto_msgTypes[2].OneofWrappers = []any{}
-	file_hostserve_v1_hostserve_proto_msgTypes[5].OneofWrappers = []any{}
-	file_hostserve_v1_hostserve_proto_msgTypes[7].OneofWrappers = []any{}
-	file_hostserve_v1_hostserve_proto_msgTypes[9].OneofWrappers = []any{}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_hostserve_v1_hostserve_proto_rawDesc), len(file_hostserve_v1_hostserve_proto_rawDesc)),
-			NumEnums:      0,
-			NumMessages:   12,
+			NumEnums:      2,
+			NumMessages:   55,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
 		GoTypes:           file_hostserve_v1_hostserve_proto_goTypes,
 		DependencyIndexes: file_hostserve_v1_hostserve_proto_depIdxs,
+		EnumInfos:         file_hostserve_v1_hostserve_proto_enumTypes,
 		MessageInfos:      file_hostserve_v1_hostserve_proto_msgTypes,
 	}.Build()
 	File_hostserve_v1_hostserve_proto = out.File